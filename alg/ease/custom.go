@@ -0,0 +1,80 @@
+package ease
+
+import "math"
+
+// Out returns the mirror of an easing function, so an In easing becomes its Out
+// counterpart and the reverse.
+func Out(f Func) Func {
+	return func(t float64) float64 {
+		return 1 - f(1-t)
+	}
+}
+
+// InOut returns an easing function that follows f for the first half of progress
+// and Out(f) for the second.
+func InOut(f Func) Func {
+	return func(t float64) float64 {
+		if t < .5 {
+			return f(2*t) / 2
+		}
+		return 1 - f(2-2*t)/2
+	}
+}
+
+// Reverse returns an easing function which runs f backward, from 1 to 0.
+func Reverse(f Func) Func {
+	return func(t float64) float64 {
+		return f(1 - t)
+	}
+}
+
+// Steps returns an easing function which jumps between n evenly spaced levels,
+// reaching 1 only at the end.
+func Steps(n int) Func {
+	if n < 1 {
+		n = 1
+	}
+	return func(t float64) float64 {
+		if t >= 1 {
+			return 1
+		}
+		return math.Floor(t*float64(n)) / float64(n)
+	}
+}
+
+// CubicBezier returns an easing function following a cubic bezier curve from
+// (0,0) to (1,1) with the control points (x1,y1) and (x2,y2), as CSS timing
+// functions are defined. X values are clamped to lie between 0 and 1.
+func CubicBezier(x1, y1, x2, y2 float64) Func {
+	x1 = math.Max(0, math.Min(1, x1))
+	x2 = math.Max(0, math.Min(1, x2))
+	bez := func(a, b, s float64) float64 {
+		return 3*a*s*(1-s)*(1-s) + 3*b*s*s*(1-s) + s*s*s
+	}
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return bez(y1, y2, math.Max(0, math.Min(1, t)))
+		}
+		// X increases monotonically with s, so s can be found by bisection.
+		lo, hi := 0.0, 1.0
+		s := t
+		for i := 0; i < 50; i++ {
+			x := bez(x1, x2, s)
+			if math.Abs(x-t) < 1e-9 {
+				break
+			}
+			if x < t {
+				lo = s
+			} else {
+				hi = s
+			}
+			s = (lo + hi) / 2
+		}
+		return bez(y1, y2, s)
+	}
+}
+
+// Lerp returns the value f of the way from a to b.
+func Lerp(a, b, f float64) float64 {
+	return a + (b-a)*f
+}
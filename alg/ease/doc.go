@@ -0,0 +1,3 @@
+// Package ease provides easing functions, which map linear progress from 0 to 1
+// onto curves that start and end at the same places.
+package ease
@@ -0,0 +1,239 @@
+package ease
+
+import "math"
+
+// A Func maps progress from 0 to 1 onto an eased progress. Eased progress starts
+// at 0 and ends at 1, but may leave that range in between.
+type Func func(t float64) float64
+
+// Linear does not ease.
+func Linear(t float64) float64 {
+	return t
+}
+
+// InQuad accelerates from zero velocity.
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+// OutQuad decelerates to zero velocity.
+func OutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// InOutQuad accelerates until halfway, then decelerates.
+func InOutQuad(t float64) float64 {
+	if t < .5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// InCubic accelerates from zero velocity.
+func InCubic(t float64) float64 {
+	return t * t * t
+}
+
+// OutCubic decelerates to zero velocity.
+func OutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// InOutCubic accelerates until halfway, then decelerates.
+func InOutCubic(t float64) float64 {
+	if t < .5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// InQuart accelerates from zero velocity.
+func InQuart(t float64) float64 {
+	return t * t * t * t
+}
+
+// OutQuart decelerates to zero velocity.
+func OutQuart(t float64) float64 {
+	t--
+	return 1 - t*t*t*t
+}
+
+// InOutQuart accelerates until halfway, then decelerates.
+func InOutQuart(t float64) float64 {
+	if t < .5 {
+		return 8 * t * t * t * t
+	}
+	t--
+	return 1 - 8*t*t*t*t
+}
+
+// InQuint accelerates from zero velocity.
+func InQuint(t float64) float64 {
+	return t * t * t * t * t
+}
+
+// OutQuint decelerates to zero velocity.
+func OutQuint(t float64) float64 {
+	t--
+	return t*t*t*t*t + 1
+}
+
+// InOutQuint accelerates until halfway, then decelerates.
+func InOutQuint(t float64) float64 {
+	if t < .5 {
+		return 16 * t * t * t * t * t
+	}
+	t--
+	return 16*t*t*t*t*t + 1
+}
+
+// InSine accelerates along a sine wave.
+func InSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// OutSine decelerates along a sine wave.
+func OutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// InOutSine accelerates and then decelerates along a sine wave.
+func InOutSine(t float64) float64 {
+	return (1 - math.Cos(math.Pi*t)) / 2
+}
+
+// InExpo accelerates exponentially.
+func InExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// OutExpo decelerates exponentially.
+func OutExpo(t float64) float64 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// InOutExpo accelerates and then decelerates exponentially.
+func InOutExpo(t float64) float64 {
+	switch {
+	case t <= 0:
+		return 0
+	case t >= 1:
+		return 1
+	case t < .5:
+		return math.Pow(2, 20*t-10) / 2
+	default:
+		return (2 - math.Pow(2, -20*t+10)) / 2
+	}
+}
+
+// InCirc accelerates along a quarter circle.
+func InCirc(t float64) float64 {
+	return 1 - math.Sqrt(1-t*t)
+}
+
+// OutCirc decelerates along a quarter circle.
+func OutCirc(t float64) float64 {
+	t--
+	return math.Sqrt(1 - t*t)
+}
+
+// InOutCirc accelerates and then decelerates along quarter circles.
+func InOutCirc(t float64) float64 {
+	if t < .5 {
+		return (1 - math.Sqrt(1-4*t*t)) / 2
+	}
+	t = 2*t - 2
+	return (math.Sqrt(1-t*t) + 1) / 2
+}
+
+// backOvershoot is how far back easing pulls past its ends, about ten percent.
+const backOvershoot = 1.70158
+
+// InBack pulls back before accelerating forward.
+func InBack(t float64) float64 {
+	return t * t * ((backOvershoot+1)*t - backOvershoot)
+}
+
+// OutBack overshoots its end before settling onto it.
+func OutBack(t float64) float64 {
+	t--
+	return t*t*((backOvershoot+1)*t+backOvershoot) + 1
+}
+
+// InOutBack pulls back before starting and overshoots before ending.
+func InOutBack(t float64) float64 {
+	const s = backOvershoot * 1.525
+	if t < .5 {
+		t *= 2
+		return t * t * ((s+1)*t - s) / 2
+	}
+	t = 2*t - 2
+	return (t*t*((s+1)*t+s) + 2) / 2
+}
+
+// InElastic winds up with a growing oscillation before starting.
+func InElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return math.Max(0, math.Min(1, t))
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*2*math.Pi/3)
+}
+
+// OutElastic overshoots its end and oscillates onto it.
+func OutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return math.Max(0, math.Min(1, t))
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*2*math.Pi/3) + 1
+}
+
+// InOutElastic oscillates out of its start and into its end.
+func InOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return math.Max(0, math.Min(1, t))
+	}
+	const c = 2 * math.Pi / 4.5
+	if t < .5 {
+		return -math.Pow(2, 20*t-10) * math.Sin((20*t-11.125)*c) / 2
+	}
+	return math.Pow(2, -20*t+10)*math.Sin((20*t-11.125)*c)/2 + 1
+}
+
+// InBounce bounces with growing height before leaving its start.
+func InBounce(t float64) float64 {
+	return 1 - OutBounce(1-t)
+}
+
+// OutBounce falls onto its end and bounces to a stop.
+func OutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + .75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + .9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + .984375
+	}
+}
+
+// InOutBounce bounces out of its start and into its end.
+func InOutBounce(t float64) float64 {
+	if t < .5 {
+		return (1 - OutBounce(1-2*t)) / 2
+	}
+	return (1 + OutBounce(2*t-1)) / 2
+}
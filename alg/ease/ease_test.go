@@ -0,0 +1,91 @@
+package ease
+
+import (
+	"math"
+	"testing"
+)
+
+var all = map[string]Func{
+	"Linear": Linear, "InQuad": InQuad, "OutQuad": OutQuad, "InOutQuad": InOutQuad,
+	"InCubic": InCubic, "OutCubic": OutCubic, "InOutCubic": InOutCubic,
+	"InQuart": InQuart, "OutQuart": OutQuart, "InOutQuart": InOutQuart,
+	"InQuint": InQuint, "OutQuint": OutQuint, "InOutQuint": InOutQuint,
+	"InSine": InSine, "OutSine": OutSine, "InOutSine": InOutSine,
+	"InExpo": InExpo, "OutExpo": OutExpo, "InOutExpo": InOutExpo,
+	"InCirc": InCirc, "OutCirc": OutCirc, "InOutCirc": InOutCirc,
+	"InBack": InBack, "OutBack": OutBack, "InOutBack": InOutBack,
+	"InElastic": InElastic, "OutElastic": OutElastic, "InOutElastic": InOutElastic,
+	"InBounce": InBounce, "OutBounce": OutBounce, "InOutBounce": InOutBounce,
+}
+
+func TestEndpoints(t *testing.T) {
+	for name, f := range all {
+		if v := f(0); math.Abs(v) > 1e-9 {
+			t.Errorf("%s(0) = %v", name, v)
+		}
+		if v := f(1); math.Abs(v-1) > 1e-9 {
+			t.Errorf("%s(1) = %v", name, v)
+		}
+	}
+}
+
+func TestOutMatchesPenner(t *testing.T) {
+	pairs := []struct {
+		name    string
+		in, out Func
+		inOut   Func
+	}{
+		{"Quad", InQuad, OutQuad, InOutQuad},
+		{"Cubic", InCubic, OutCubic, InOutCubic},
+		{"Quart", InQuart, OutQuart, InOutQuart},
+		{"Quint", InQuint, OutQuint, InOutQuint},
+		{"Sine", InSine, OutSine, InOutSine},
+		{"Expo", InExpo, OutExpo, InOutExpo},
+		{"Circ", InCirc, OutCirc, InOutCirc},
+		{"Bounce", InBounce, OutBounce, InOutBounce},
+		{"Elastic", InElastic, OutElastic, nil},
+		{"Back", InBack, OutBack, nil},
+	}
+	for _, p := range pairs {
+		out, inOut := Out(p.in), InOut(p.in)
+		for i := 0; i <= 20; i++ {
+			x := float64(i) / 20
+			if a, b := out(x), p.out(x); math.Abs(a-b) > 1e-9 {
+				t.Errorf("Out(In%s)(%v) = %v, Out%s = %v", p.name, x, a, p.name, b)
+			}
+			if p.inOut == nil {
+				continue
+			}
+			if a, b := inOut(x), p.inOut(x); math.Abs(a-b) > 1e-9 {
+				t.Errorf("InOut(In%s)(%v) = %v, InOut%s = %v", p.name, x, a, p.name, b)
+			}
+		}
+	}
+}
+
+func TestCustom(t *testing.T) {
+	linear := CubicBezier(0, 0, 1, 1)
+	for i := 0; i <= 10; i++ {
+		x := float64(i) / 10
+		if v := linear(x); math.Abs(v-x) > 1e-6 {
+			t.Errorf("linear bezier(%v) = %v", x, v)
+		}
+	}
+	easeInOut := CubicBezier(.42, 0, .58, 1)
+	if v := easeInOut(.5); math.Abs(v-.5) > 1e-6 {
+		t.Errorf("symmetric bezier(.5) = %v", v)
+	}
+	if v := easeInOut(.25); v >= .25 {
+		t.Errorf("ease in bezier(.25) = %v, expected to start slow", v)
+	}
+	steps := Steps(4)
+	if steps(.3) != .25 || steps(.99) != .75 || steps(1) != 1 {
+		t.Errorf("unexpected steps: %v %v %v", steps(.3), steps(.99), steps(1))
+	}
+	if v := Reverse(InQuad)(.25); math.Abs(v-.5625) > 1e-9 {
+		t.Errorf("reversed quad(.25) = %v", v)
+	}
+	if Lerp(10, 20, .25) != 12.5 {
+		t.Errorf("unexpected lerp")
+	}
+}
@@ -0,0 +1,129 @@
+package floatgeom
+
+import "math"
+
+// polygonOf creates a polygon from a slice of at least three points.
+func polygonOf(pts []Point2) Polygon2 {
+	return NewPolygon2(pts[0], pts[1], pts[2], pts[3:]...)
+}
+
+// SignedArea returns the area of this polygon, positive if its points are wound
+// positively (see ConvexHull) and negative otherwise. The area of a self
+// intersecting polygon is the sum of the signed areas of its loops.
+func (pg Polygon2) SignedArea() float64 {
+	area := 0.0
+	for i, p := range pg.Points {
+		q := pg.Points[(i+1)%len(pg.Points)]
+		area += p.X()*q.Y() - q.X()*p.Y()
+	}
+	return area / 2
+}
+
+// Area returns the area of this polygon.
+func (pg Polygon2) Area() float64 {
+	return math.Abs(pg.SignedArea())
+}
+
+// Centroid returns the center of mass of this polygon. If the polygon has no
+// area, the average of its points is returned.
+func (pg Polygon2) Centroid() Point2 {
+	area := pg.SignedArea()
+	if area == 0 {
+		sum := Point2{}
+		for _, p := range pg.Points {
+			sum = sum.Add(p)
+		}
+		return sum.DivConst(float64(len(pg.Points)))
+	}
+	cx, cy := 0.0, 0.0
+	for i, p := range pg.Points {
+		q := pg.Points[(i+1)%len(pg.Points)]
+		f := p.X()*q.Y() - q.X()*p.Y()
+		cx += (p.X() + q.X()) * f
+		cy += (p.Y() + q.Y()) * f
+	}
+	return Point2{cx / (6 * area), cy / (6 * area)}
+}
+
+// Reverse returns this polygon with its points in reverse order.
+func (pg Polygon2) Reverse() Polygon2 {
+	pts := make([]Point2, len(pg.Points))
+	for i, p := range pg.Points {
+		pts[len(pts)-1-i] = p
+	}
+	return polygonOf(pts)
+}
+
+// Wound returns this polygon with its points wound positively (see ConvexHull)
+// if positive is true, or negatively otherwise, reversing it if needed.
+func (pg Polygon2) Wound(positive bool) Polygon2 {
+	if (pg.SignedArea() >= 0) != positive {
+		return pg.Reverse()
+	}
+	return pg
+}
+
+// Simplify removes points from this polygon which are within tolerance of the
+// outline of the remaining points, with the Douglas-Peucker algorithm. If fewer
+// than three points would remain, the polygon is returned unchanged.
+func (pg Polygon2) Simplify(tolerance float64) Polygon2 {
+	// split the loop at the point farthest from the first, which is always kept
+	far, farDist := 0, -1.0
+	for i, p := range pg.Points {
+		if d := p.Distance(pg.Points[0]); d > farDist {
+			far, farDist = i, d
+		}
+	}
+	a := Simplify(tolerance, pg.Points[:far+1]...)
+	b := Simplify(tolerance, append(pg.Points[far:len(pg.Points):len(pg.Points)], pg.Points[0])...)
+	pts := append(a, b[1:len(b)-1]...)
+	if len(pts) < 3 {
+		return pg
+	}
+	return polygonOf(pts)
+}
+
+// Simplify removes points from a path which are within tolerance of the path
+// through the remaining points, with the Douglas-Peucker algorithm. The first and
+// last points are always kept.
+func Simplify(tolerance float64, pts ...Point2) []Point2 {
+	if len(pts) < 3 {
+		return append([]Point2{}, pts...)
+	}
+	keep := make([]bool, len(pts))
+	keep[0], keep[len(pts)-1] = true, true
+	var simplify func(lo, hi int)
+	simplify = func(lo, hi int) {
+		far, farDist := -1, tolerance
+		for i := lo + 1; i < hi; i++ {
+			if d := segmentDistance(pts[i], pts[lo], pts[hi]); d > farDist {
+				far, farDist = i, d
+			}
+		}
+		if far == -1 {
+			return
+		}
+		keep[far] = true
+		simplify(lo, far)
+		simplify(far, hi)
+	}
+	simplify(0, len(pts)-1)
+	out := make([]Point2, 0, len(pts))
+	for i, p := range pts {
+		if keep[i] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// segmentDistance returns the distance from p to the segment ab.
+func segmentDistance(p, a, b Point2) float64 {
+	ab := b.Sub(a)
+	lenSq := ab.Dot(ab)
+	if lenSq == 0 {
+		return p.Distance(a)
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/lenSq))
+	return p.Distance(a.Add(ab.MulConst(t)))
+}
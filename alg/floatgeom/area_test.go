@@ -0,0 +1,62 @@
+package floatgeom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func square(x, y, size float64) Polygon2 {
+	return NewPolygon2(Point2{x, y}, Point2{x + size, y}, Point2{x + size, y + size}, Point2{x, y + size})
+}
+
+func TestPolygon2Area(t *testing.T) {
+	sq := square(0, 0, 2)
+	if sq.SignedArea() != 4 || sq.Reverse().SignedArea() != -4 || sq.Reverse().Area() != 4 {
+		t.Fatalf("unexpected areas %v %v", sq.SignedArea(), sq.Reverse().SignedArea())
+	}
+	if c := sq.Reverse().Centroid(); c != (Point2{1, 1}) {
+		t.Fatalf("expected centroid 1,1, got %v", c)
+	}
+	l := NewPolygon2(Point2{0, 0}, Point2{3, 0}, Point2{3, 1}, Point2{1, 1}, Point2{1, 3}, Point2{0, 3})
+	// two rectangles of area 3 and 2 centered at 1.5,.5 and .5,2
+	want := Point2{(1.5*3 + .5*2) / 5, (.5*3 + 2*2) / 5}
+	if c := l.Centroid(); c.Distance(want) > 1e-12 {
+		t.Fatalf("expected centroid %v, got %v", want, c)
+	}
+	if sq.Wound(true).SignedArea() < 0 || sq.Wound(false).SignedArea() > 0 || sq.Reverse().Wound(true).SignedArea() < 0 {
+		t.Fatal("unexpected winding")
+	}
+	flat := NewPolygon2(Point2{0, 0}, Point2{1, 1}, Point2{2, 2})
+	if c := flat.Centroid(); c != (Point2{1, 1}) {
+		t.Fatalf("expected average of points for centroid of flat polygon, got %v", c)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	path := []Point2{{0, 0}, {1, .05}, {2, -.05}, {3, 0}, {3, 1}, {3.05, 2}, {3, 3}}
+	got := Simplify(.1, path...)
+	want := []Point2{{0, 0}, {3, 0}, {3, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := Simplify(.01, path...); len(got) != len(path) {
+		t.Fatalf("expected all points kept at low tolerance, got %v", got)
+	}
+
+	var circle []Point2
+	for i := 0; i < 64; i++ {
+		circle = append(circle, RadianPoint(float64(i)*2*math.Pi/64).MulConst(10))
+	}
+	pg := polygonOf(circle)
+	simple := pg.Simplify(.5)
+	if len(simple.Points) >= 64 || len(simple.Points) < 8 {
+		t.Fatalf("unexpected simplified point count %v", len(simple.Points))
+	}
+	if math.Abs(simple.Area()-pg.Area())/pg.Area() > .05 {
+		t.Fatalf("simplified area %v too far from %v", simple.Area(), pg.Area())
+	}
+	if got := pg.Simplify(100); !reflect.DeepEqual(got.Points, pg.Points) {
+		t.Fatal("expected polygon unchanged when simplified below three points")
+	}
+}
@@ -0,0 +1,439 @@
+package floatgeom
+
+import (
+	"math"
+	"sort"
+)
+
+type booleanOp int
+
+const (
+	opUnion booleanOp = iota
+	opIntersection
+	opDifference
+	opXor
+)
+
+// Union returns the area covered by either a or b. Regions within each of a and
+// b should not overlap one another.
+func Union(a, b []Region) []Region {
+	return boolean(a, b, opUnion)
+}
+
+// Intersection returns the area covered by both a and b. Regions within each of
+// a and b should not overlap one another.
+func Intersection(a, b []Region) []Region {
+	return boolean(a, b, opIntersection)
+}
+
+// Difference returns the area covered by a and not b. Regions within each of a
+// and b should not overlap one another.
+func Difference(a, b []Region) []Region {
+	return boolean(a, b, opDifference)
+}
+
+// Xor returns the area covered by exactly one of a and b. Regions within each of
+// a and b should not overlap one another.
+func Xor(a, b []Region) []Region {
+	return boolean(a, b, opXor)
+}
+
+type planarEdge struct {
+	from, to int
+	set      int
+	// ring is the index of the ring, among those added to the graph, the edge is from.
+	ring int
+}
+
+// planarGraph holds the edges of sets of polygons, split so that edges only meet
+// at their endpoints.
+type planarGraph struct {
+	pts   []Point2
+	edges []planarEdge
+	eps   float64
+	rings int
+
+	// grid buckets points by their position in eps sized cells, so points within
+	// eps of each other are in the same or neighboring cells.
+	grid map[[2]int64][]int
+}
+
+func newPlanarGraph(eps float64) *planarGraph {
+	return &planarGraph{eps: eps, grid: make(map[[2]int64][]int)}
+}
+
+func (g *planarGraph) cell(p Point2) [2]int64 {
+	if g.eps == 0 {
+		return [2]int64{}
+	}
+	return [2]int64{int64(math.Floor(p.X() / g.eps)), int64(math.Floor(p.Y() / g.eps))}
+}
+
+// point returns the index of p, merging it with any existing point within eps.
+func (g *planarGraph) point(p Point2) int {
+	c := g.cell(p)
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, i := range g.grid[[2]int64{c[0] + dx, c[1] + dy}] {
+				q := g.pts[i]
+				if math.Abs(p.X()-q.X()) <= g.eps && math.Abs(p.Y()-q.Y()) <= g.eps {
+					return i
+				}
+			}
+		}
+	}
+	g.pts = append(g.pts, p)
+	g.grid[c] = append(g.grid[c], len(g.pts)-1)
+	return len(g.pts) - 1
+}
+
+func (g *planarGraph) addRings(rs [][]Point2, set int) {
+	for _, ring := range rs {
+		for i, p := range ring {
+			u, v := g.point(p), g.point(ring[(i+1)%len(ring)])
+			if u != v {
+				g.edges = append(g.edges, planarEdge{u, v, set, g.rings})
+			}
+		}
+		g.rings++
+	}
+}
+
+// split divides edges where they cross and where points lie along them.
+func (g *planarGraph) split() {
+	// sweep edges in order of their left ends, only testing edges whose x extents overlap
+	order := make([]int, len(g.edges))
+	boxes := make([]Rect2, len(g.edges))
+	for i, e := range g.edges {
+		order[i] = i
+		boxes[i] = NewBoundingRect2(g.pts[e.from], g.pts[e.to])
+	}
+	sort.Slice(order, func(i, j int) bool { return boxes[order[i]].Min.X() < boxes[order[j]].Min.X() })
+	for oi, i := range order {
+		for _, j := range order[oi+1:] {
+			if boxes[j].Min.X() > boxes[i].Max.X() {
+				break
+			}
+			if boxes[j].Min.Y() > boxes[i].Max.Y() || boxes[j].Max.Y() < boxes[i].Min.Y() {
+				continue
+			}
+			a, b := g.pts[g.edges[i].from], g.pts[g.edges[i].to]
+			c, d := g.pts[g.edges[j].from], g.pts[g.edges[j].to]
+			if crosses(a, b, c, d) {
+				r, s := b.Sub(a), d.Sub(c)
+				t := ((c.X()-a.X())*s.Y() - (c.Y()-a.Y())*s.X()) / (r.X()*s.Y() - r.Y()*s.X())
+				g.point(a.Add(r.MulConst(t)))
+			}
+		}
+	}
+	// points sorted by x, to find those which may lie along each edge
+	byX := make([]int, len(g.pts))
+	for i := range byX {
+		byX[i] = i
+	}
+	sort.Slice(byX, func(i, j int) bool { return g.pts[byX[i]].X() < g.pts[byX[j]].X() })
+	edges := make([]planarEdge, 0, len(g.edges))
+	for ei, e := range g.edges {
+		a, b := g.pts[e.from], g.pts[e.to]
+		ab := b.Sub(a)
+		lenSq := ab.Dot(ab)
+		type stop struct {
+			t float64
+			i int
+		}
+		stops := []stop{{0, e.from}, {1, e.to}}
+		box := boxes[ei]
+		first := sort.Search(len(byX), func(k int) bool { return g.pts[byX[k]].X() >= box.Min.X()-g.eps })
+		for _, i := range byX[first:] {
+			p := g.pts[i]
+			if p.X() > box.Max.X()+g.eps {
+				break
+			}
+			if i == e.from || i == e.to || p.Y() < box.Min.Y()-g.eps || p.Y() > box.Max.Y()+g.eps {
+				continue
+			}
+			t := p.Sub(a).Dot(ab) / lenSq
+			if t > 0 && t < 1 && segmentDistance(p, a, b) <= g.eps {
+				stops = append(stops, stop{t, i})
+			}
+		}
+		sort.Slice(stops, func(i, j int) bool { return stops[i].t < stops[j].t })
+		for k := 1; k < len(stops); k++ {
+			if stops[k-1].i != stops[k].i {
+				e.from, e.to = stops[k-1].i, stops[k].i
+				edges = append(edges, e)
+			}
+		}
+	}
+	g.edges = edges
+}
+
+// evenOdd returns whether p is inside an odd number of rings.
+func evenOdd(rs [][]Point2, p Point2) bool {
+	in := false
+	for _, ring := range rs {
+		if polygonOf(ring).Contains(p.X(), p.Y()) {
+			in = !in
+		}
+	}
+	return in
+}
+
+func boolean(a, b []Region, op booleanOp) []Region {
+	sets := [2][][]Point2{rings(a), rings(b)}
+	var all []Point2
+	for _, set := range sets {
+		for _, ring := range set {
+			all = append(all, ring...)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	bounds := NewBoundingRect2(all...)
+	g := newPlanarGraph(math.Max(bounds.W(), bounds.H()) * 1e-10)
+	g.addRings(sets[0], 0)
+	g.addRings(sets[1], 1)
+	g.split()
+
+	has := [2]map[[2]int]bool{{}, {}}
+	for _, e := range g.edges {
+		has[e.set][[2]int{e.from, e.to}] = true
+	}
+	var kept []planarEdge
+	keep := func(e planarEdge, reverse bool) {
+		if reverse {
+			e.from, e.to = e.to, e.from
+		}
+		kept = append(kept, e)
+	}
+	for _, e := range g.edges {
+		other := 1 - e.set
+		switch {
+		case has[other][[2]int{e.from, e.to}]:
+			// both sets are on the same side of this edge
+			if e.set == 0 && (op == opUnion || op == opIntersection) {
+				keep(e, false)
+			}
+		case has[other][[2]int{e.to, e.from}]:
+			// the sets are on opposite sides of this edge
+			if op == opXor || (op == opDifference && e.set == 0) {
+				keep(e, false)
+			}
+		default:
+			mid := g.pts[e.from].Add(g.pts[e.to]).DivConst(2)
+			inside := evenOdd(sets[other], mid)
+			switch op {
+			case opUnion:
+				if !inside {
+					keep(e, false)
+				}
+			case opIntersection:
+				if inside {
+					keep(e, false)
+				}
+			case opDifference:
+				if e.set == 0 && !inside {
+					keep(e, false)
+				} else if e.set == 1 && inside {
+					keep(e, true)
+				}
+			case opXor:
+				keep(e, inside)
+			}
+		}
+	}
+	return g.regions(g.trace(kept))
+}
+
+// fill returns the area where inside is true of the winding numbers of each set of
+// rings, in which positively wound rings count one and negatively wound rings
+// count negative one. Unlike boolean operations, rings within a set may overlap,
+// so many shapes can be combined in one pass.
+func fill(sets [][][]Point2, inside func(winding []int) bool) []Region {
+	var all []Point2
+	for _, set := range sets {
+		for _, ring := range set {
+			all = append(all, ring...)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	bounds := NewBoundingRect2(all...)
+	g := newPlanarGraph(math.Max(bounds.W(), bounds.H()) * 1e-10)
+
+	type ringInfo struct {
+		Polygon2
+		set, sign int
+	}
+	var infos []ringInfo
+	for s, set := range sets {
+		for _, ring := range set {
+			if len(ring) < 3 {
+				continue
+			}
+			pg := polygonOf(ring)
+			sign := 1
+			if area := pg.SignedArea(); area == 0 {
+				continue
+			} else if area < 0 {
+				sign = -1
+			}
+			infos = append(infos, ringInfo{pg, s, sign})
+			g.addRings([][]Point2{ring}, s)
+		}
+	}
+	g.split()
+
+	// edges from different rings may coincide; each undirected edge is resolved once
+	type side struct {
+		ring    int
+		forward bool
+	}
+	var keys [][2]int
+	sides := make(map[[2]int][]side)
+	for _, e := range g.edges {
+		key, forward := [2]int{e.from, e.to}, true
+		if e.from > e.to {
+			key, forward = [2]int{e.to, e.from}, false
+		}
+		if _, ok := sides[key]; !ok {
+			keys = append(keys, key)
+		}
+		sides[key] = append(sides[key], side{e.ring, forward})
+	}
+
+	left, right := make([]int, len(sets)), make([]int, len(sets))
+	var kept []planarEdge
+	for _, key := range keys {
+		for i := range left {
+			left[i], right[i] = 0, 0
+		}
+		on := sides[key]
+		mid := g.pts[key[0]].Add(g.pts[key[1]]).DivConst(2)
+	rings:
+		for i, info := range infos {
+			for _, sd := range on {
+				if sd.ring == i {
+					continue rings
+				}
+			}
+			if info.Contains(mid.X(), mid.Y()) {
+				left[info.set] += info.sign
+				right[info.set] += info.sign
+			}
+		}
+		for _, sd := range on {
+			// a ring's inside is to the left of its edges if it is wound positively
+			info := infos[sd.ring]
+			if sd.forward == (info.sign > 0) {
+				left[info.set] += info.sign
+			} else {
+				right[info.set] += info.sign
+			}
+		}
+		inLeft, inRight := inside(left), inside(right)
+		if inLeft && !inRight {
+			kept = append(kept, planarEdge{from: key[0], to: key[1]})
+		} else if inRight && !inLeft {
+			kept = append(kept, planarEdge{from: key[1], to: key[0]})
+		}
+	}
+	return g.regions(g.trace(kept))
+}
+
+// trace links edges into rings, turning as far left as possible where several
+// edges leave a point so that rings touching at a point are kept apart.
+func (g *planarGraph) trace(edges []planarEdge) [][]Point2 {
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e.from] = append(out[e.from], i)
+	}
+	used := make([]bool, len(edges))
+	var rs [][]Point2
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var ring []Point2
+		cur := start
+		for {
+			used[cur] = true
+			e := edges[cur]
+			ring = append(ring, g.pts[e.from])
+			in := g.pts[e.to].Sub(g.pts[e.from])
+			best, bestTurn := -1, math.Inf(-1)
+			for _, next := range out[e.to] {
+				if used[next] && next != start {
+					continue
+				}
+				d := g.pts[edges[next].to].Sub(g.pts[e.to])
+				turn := math.Atan2(in.X()*d.Y()-in.Y()*d.X(), in.Dot(d))
+				if turn >= math.Pi {
+					turn = -math.Pi
+				}
+				if turn > bestTurn {
+					best, bestTurn = next, turn
+				}
+			}
+			if best == -1 || best == start {
+				break
+			}
+			cur = best
+		}
+		if ring = g.clean(ring); ring != nil {
+			rs = append(rs, ring)
+		}
+	}
+	return rs
+}
+
+// clean removes points along straight edges of a ring, returning nil if the ring
+// has no area.
+func (g *planarGraph) clean(ring []Point2) []Point2 {
+	for i := 0; i < len(ring) && len(ring) >= 3; {
+		prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+		if segmentDistance(ring[i], prev, next) <= g.eps {
+			ring = append(ring[:i], ring[i+1:]...)
+			i = 0
+			continue
+		}
+		i++
+	}
+	if len(ring) < 3 {
+		return nil
+	}
+	return ring
+}
+
+// regions assigns each negatively wound ring, a hole, to the smallest positively
+// wound ring containing it.
+func (g *planarGraph) regions(rs [][]Point2) []Region {
+	var regions []Region
+	var holes []Polygon2
+	for _, r := range rs {
+		pg := polygonOf(r)
+		if pg.SignedArea() > 0 {
+			regions = append(regions, Region{Outer: pg})
+		} else {
+			holes = append(holes, pg)
+		}
+	}
+	for _, h := range holes {
+		// the region's area is to the left of a hole's edges
+		a, b := h.Points[0], h.Points[1]
+		d := b.Sub(a).Normalize()
+		p := a.Add(b).DivConst(2).Add(Point2{-d.Y(), d.X()}.MulConst(g.eps * 100))
+		owner, ownerArea := -1, math.Inf(1)
+		for i, r := range regions {
+			if area := r.Outer.Area(); area < ownerArea && r.Outer.Contains(p.X(), p.Y()) {
+				owner, ownerArea = i, area
+			}
+		}
+		if owner != -1 {
+			regions[owner].Holes = append(regions[owner].Holes, h)
+		}
+	}
+	return regions
+}
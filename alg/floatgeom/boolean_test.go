@@ -0,0 +1,139 @@
+package floatgeom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func regionsArea(rs []Region) float64 {
+	area := 0.0
+	for _, r := range rs {
+		area += r.Area()
+	}
+	return area
+}
+
+func regionsContain(rs []Region, p Point2) bool {
+	for _, r := range rs {
+		if r.Contains(p.X(), p.Y()) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBooleanSquares(t *testing.T) {
+	a := []Region{NewRegion(square(0, 0, 2))}
+	b := []Region{NewRegion(square(1, 1, 2).Reverse())}
+	tcs := []struct {
+		name    string
+		op      func(a, b []Region) []Region
+		area    float64
+		regions int
+	}{
+		{"union", Union, 7, 1},
+		{"intersection", Intersection, 1, 1},
+		{"difference", Difference, 3, 1},
+		{"xor", Xor, 6, 2},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tc.op(a, b)
+			if len(out) != tc.regions {
+				t.Fatalf("expected %v regions, got %v", tc.regions, out)
+			}
+			if got := regionsArea(out); math.Abs(got-tc.area) > 1e-9 {
+				t.Fatalf("expected area %v, got %v", tc.area, got)
+			}
+		})
+	}
+}
+
+func TestBooleanHoles(t *testing.T) {
+	frame := []Region{NewRegion(square(0, 0, 10), square(3, 3, 4))}
+	if got := frame[0].Area(); got != 84 {
+		t.Fatalf("expected frame area 84, got %v", got)
+	}
+	// a square punched through the frame's hole and into its side
+	bar := []Region{NewRegion(NewPolygon2(Point2{-1, 4}, Point2{11, 4}, Point2{11, 6}, Point2{-1, 6}))}
+	diff := Difference(frame, bar)
+	if got := regionsArea(diff); math.Abs(got-(84-12)) > 1e-9 {
+		t.Fatalf("expected area %v, got %v", 84-12, got)
+	}
+	if len(diff) != 2 {
+		t.Fatalf("expected frame to be cut in two, got %v", diff)
+	}
+	union := Union(frame, bar)
+	if got := regionsArea(union); math.Abs(got-(84+8+4)) > 1e-9 {
+		t.Fatalf("expected area %v, got %v", 84+8+4, got)
+	}
+	if len(union) != 1 || len(union[0].Holes) != 2 {
+		t.Fatalf("expected one region with the hole split in two, got %v", union)
+	}
+	// filling the hole exactly leaves no holes
+	filled := Union(frame, []Region{NewRegion(square(3, 3, 4))})
+	if len(filled) != 1 || len(filled[0].Holes) != 0 || filled[0].Area() != 100 {
+		t.Fatalf("expected a filled square, got %v", filled)
+	}
+	// a hole from a difference within a region
+	holed := Difference([]Region{NewRegion(square(0, 0, 10))}, []Region{NewRegion(square(2, 2, 2))})
+	if len(holed) != 1 || len(holed[0].Holes) != 1 || holed[0].Contains(3, 3) || !holed[0].Contains(5, 5) {
+		t.Fatalf("expected a region with a hole, got %v", holed)
+	}
+}
+
+func TestBooleanTouching(t *testing.T) {
+	a := []Region{NewRegion(square(0, 0, 1))}
+	corner := []Region{NewRegion(square(1, 1, 1))}
+	if out := Union(a, corner); len(out) != 2 || regionsArea(out) != 2 {
+		t.Fatalf("expected squares touching at a corner to stay apart, got %v", out)
+	}
+	side := []Region{NewRegion(square(1, 0, 1))}
+	out := Union(a, side)
+	if len(out) != 1 || len(out[0].Outer.Points) != 4 || regionsArea(out) != 2 {
+		t.Fatalf("expected squares sharing a side to merge into a rectangle, got %v", out)
+	}
+	if out := Intersection(a, side); len(out) != 0 {
+		t.Fatalf("expected no intersection between squares sharing a side, got %v", out)
+	}
+	if out := Difference(a, a); len(out) != 0 {
+		t.Fatalf("expected nothing left subtracting a region from itself, got %v", out)
+	}
+}
+
+func TestBooleanRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomPoly := func() Region {
+		// a star shaped polygon around a random center
+		c := Point2{rng.Float64() * 10, rng.Float64() * 10}
+		var pts []Point2
+		for i := 0; i < 7; i++ {
+			pts = append(pts, c.Add(RadianPoint(float64(i)*2*math.Pi/7).MulConst(2+rng.Float64()*4)))
+		}
+		return NewRegion(polygonOf(pts))
+	}
+	for i := 0; i < 20; i++ {
+		a, b := []Region{randomPoly()}, []Region{randomPoly()}
+		union, inter := Union(a, b), Intersection(a, b)
+		diff, xor := Difference(a, b), Xor(a, b)
+		aa, ba := a[0].Area(), b[0].Area()
+		if got := regionsArea(union) + regionsArea(inter); math.Abs(got-aa-ba) > 1e-6 {
+			t.Fatalf("union and intersection areas sum to %v, not %v", got, aa+ba)
+		}
+		if got := regionsArea(diff) + regionsArea(inter); math.Abs(got-aa) > 1e-6 {
+			t.Fatalf("difference and intersection areas sum to %v, not %v", got, aa)
+		}
+		if got := regionsArea(xor); math.Abs(got-(regionsArea(union)-regionsArea(inter))) > 1e-6 {
+			t.Fatalf("xor area %v is not union minus intersection", got)
+		}
+		for j := 0; j < 100; j++ {
+			p := Point2{rng.Float64()*20 - 5, rng.Float64()*20 - 5}
+			inA, inB := regionsContain(a, p), regionsContain(b, p)
+			if regionsContain(union, p) != (inA || inB) || regionsContain(inter, p) != (inA && inB) ||
+				regionsContain(diff, p) != (inA && !inB) || regionsContain(xor, p) != (inA != inB) {
+				t.Fatalf("inconsistent containment of %v", p)
+			}
+		}
+	}
+}
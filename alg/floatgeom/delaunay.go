@@ -0,0 +1,319 @@
+package floatgeom
+
+import (
+	"math"
+	"sort"
+)
+
+// inCircle returns whether d is clearly inside the circumcircle of abc, when abc
+// is wound positively (see cross). Points within rounding error of the circle are
+// not inside it.
+func inCircle(a, b, c, d Point2) bool {
+	adx, ady := a.X()-d.X(), a.Y()-d.Y()
+	bdx, bdy := b.X()-d.X(), b.Y()-d.Y()
+	cdx, cdy := c.X()-d.X(), c.Y()-d.Y()
+	alift, blift, clift := adx*adx+ady*ady, bdx*bdx+bdy*bdy, cdx*cdx+cdy*cdy
+	bc, ca, ab := bdx*cdy-cdx*bdy, cdx*ady-adx*cdy, adx*bdy-bdx*ady
+	det := alift*bc + blift*ca + clift*ab
+	bound := alift*math.Abs(bc) + blift*math.Abs(ca) + clift*math.Abs(ab)
+	return det > bound*1e-12
+}
+
+// Delaunay returns the Delaunay triangulation of a set of points: triangles
+// covering their convex hull such that no point lies within any triangle's
+// circumcircle. Each triangle holds indices into pts, and is wound positively
+// (see ConvexHull). Where several points share a position, only the first is
+// used. Where four or more points share a circumcircle, any of their valid
+// triangulations may be returned.
+//
+// If there are fewer than three distinct points, or all points are collinear,
+// no triangles are returned.
+func Delaunay(pts ...Point2) [][3]int {
+	order := uniqueOrder(pts)
+	if len(order) < 3 {
+		return nil
+	}
+	d := &delaunay{pts: pts}
+	if !d.sweep(order) {
+		return nil
+	}
+	d.flip()
+	return d.tris
+}
+
+// ConstrainedDelaunay returns a triangulation of a set of points, as Delaunay,
+// which includes each of the given edges between pairs of points. Edges should
+// only meet at their endpoints and should not pass through other points.
+// Triangles are as close to Delaunay as the edges allow: only triangles crossed
+// by an edge are replaced.
+func ConstrainedDelaunay(pts []Point2, edges [][2]int) [][3]int {
+	order := uniqueOrder(pts)
+	if len(order) < 3 {
+		return nil
+	}
+	d := &delaunay{pts: pts}
+	if !d.sweep(order) {
+		return nil
+	}
+	d.flip()
+	first := make(map[Point2]int, len(order))
+	for _, o := range order {
+		first[pts[o]] = o
+	}
+	for _, e := range edges {
+		if a, b := first[pts[e[0]]], first[pts[e[1]]]; a != b {
+			d.constrain(a, b)
+		}
+	}
+	return d.tris
+}
+
+// uniqueOrder returns the indices of distinct points, sorted by x then y.
+func uniqueOrder(pts []Point2) []int {
+	order := make([]int, len(pts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := pts[order[i]], pts[order[j]]
+		if a.X() != b.X() {
+			return a.X() < b.X()
+		}
+		return a.Y() < b.Y()
+	})
+	out := order[:0]
+	for i, o := range order {
+		if i == 0 || pts[o] != pts[order[i-1]] {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+type delaunay struct {
+	pts  []Point2
+	tris [][3]int
+	// edges maps each directed edge to the triangle it winds around.
+	edges map[[2]int]int
+}
+
+func (d *delaunay) link(i int) {
+	tri := d.tris[i]
+	for k := 0; k < 3; k++ {
+		d.edges[[2]int{tri[k], tri[(k+1)%3]}] = i
+	}
+}
+
+func (d *delaunay) unlink(i int) {
+	tri := d.tris[i]
+	for k := 0; k < 3; k++ {
+		delete(d.edges, [2]int{tri[k], tri[(k+1)%3]})
+	}
+}
+
+func (d *delaunay) addTri(a, b, c int) {
+	if cross(d.pts[a], d.pts[b], d.pts[c]) < 0 {
+		b, c = c, b
+	}
+	d.tris = append(d.tris, [3]int{a, b, c})
+	d.link(len(d.tris) - 1)
+}
+
+// sweep triangulates the sorted points, adding each to the hull of those before
+// it by connecting it to every hull edge it can see.
+func (d *delaunay) sweep(order []int) bool {
+	d.edges = make(map[[2]int]int)
+	// the leading points may be collinear; fan them to the first point off
+	// their line
+	first := 2
+	for first < len(order) && cross(d.pts[order[0]], d.pts[order[1]], d.pts[order[first]]) == 0 {
+		first++
+	}
+	if first == len(order) {
+		return false
+	}
+	apex := order[first]
+	for i := 0; i+1 < first; i++ {
+		d.addTri(order[i], order[i+1], apex)
+	}
+	// hull is wound positively
+	var hull []int
+	if cross(d.pts[order[0]], d.pts[order[1]], d.pts[apex]) > 0 {
+		hull = append(hull, order[:first]...)
+		hull = append(hull, apex)
+	} else {
+		hull = append(hull, apex)
+		for i := first - 1; i >= 0; i-- {
+			hull = append(hull, order[i])
+		}
+	}
+	for _, p := range order[first+1:] {
+		pt := d.pts[p]
+		n := len(hull)
+		visible := make([]bool, n)
+		for i := range hull {
+			visible[i] = cross(d.pts[hull[i]], d.pts[hull[(i+1)%n]], pt) < 0
+		}
+		// visible edges are contiguous; find the hidden edge before them
+		start := -1
+		for i := range hull {
+			if !visible[i] && visible[(i+1)%n] {
+				start = i
+				break
+			}
+		}
+		if start == -1 {
+			continue
+		}
+		next := make([]int, 0, n+1)
+		i := (start + 1) % n
+		for visible[i] {
+			d.addTri(hull[(i+1)%n], hull[i], p)
+			i = (i + 1) % n
+		}
+		// hull[(start+1)%n] through hull[i] are replaced by p between them
+		next = append(next, hull[(start+1)%n], p)
+		for j := i; j != (start+1)%n; j = (j + 1) % n {
+			next = append(next, hull[j])
+		}
+		hull = next
+	}
+	return true
+}
+
+// flip flips edges of the triangulation until every edge is locally Delaunay,
+// which makes the whole triangulation Delaunay.
+func (d *delaunay) flip() {
+	stack := make([][2]int, 0, len(d.edges))
+	for e := range d.edges {
+		stack = append(stack, e)
+	}
+	for len(stack) != 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		t1, ok := d.edges[e]
+		if !ok {
+			continue
+		}
+		t2, ok := d.edges[[2]int{e[1], e[0]}]
+		if !ok {
+			continue
+		}
+		a, b := e[0], e[1]
+		c := third(d.tris[t1], a, b)
+		dd := third(d.tris[t2], a, b)
+		if !inCircle(d.pts[a], d.pts[b], d.pts[c], d.pts[dd]) {
+			continue
+		}
+		d.unlink(t1)
+		d.unlink(t2)
+		d.tris[t1] = [3]int{a, dd, c}
+		d.tris[t2] = [3]int{dd, b, c}
+		d.link(t1)
+		d.link(t2)
+		stack = append(stack, [2]int{a, dd}, [2]int{dd, b}, [2]int{b, c}, [2]int{c, a})
+	}
+}
+
+// constrain makes the edge from a to b part of the triangulation, removing the
+// triangles it crosses and triangulating the cavities on either side of it.
+func (d *delaunay) constrain(a, b int) {
+	if _, ok := d.edges[[2]int{a, b}]; ok {
+		return
+	}
+	if _, ok := d.edges[[2]int{b, a}]; ok {
+		return
+	}
+	pa, pb := d.pts[a], d.pts[b]
+	var cavity [][2]int
+	kept := make([][3]int, 0, len(d.tris))
+	for _, tri := range d.tris {
+		crossed := false
+		for k := 0; k < 3; k++ {
+			if crosses(pa, pb, d.pts[tri[k]], d.pts[tri[(k+1)%3]]) {
+				crossed = true
+				break
+			}
+		}
+		if !crossed {
+			kept = append(kept, tri)
+			continue
+		}
+		cavity = append(cavity, [2]int{tri[0], tri[1]}, [2]int{tri[1], tri[2]}, [2]int{tri[2], tri[0]})
+	}
+	if len(cavity) == 0 {
+		return
+	}
+	// the cavity's boundary is made of the crossed triangles' unshared edges,
+	// wound positively like the triangles
+	shared := make(map[[2]int]bool, len(cavity))
+	for _, e := range cavity {
+		shared[e] = true
+	}
+	next := make(map[int]int, len(cavity))
+	for _, e := range cavity {
+		if !shared[[2]int{e[1], e[0]}] {
+			next[e[0]] = e[1]
+		}
+	}
+	// the boundary runs from a to b on one side of the edge, and back on the other
+	var right, left []int
+	chain := &right
+	for v := a; ; {
+		*chain = append(*chain, v)
+		if v == b {
+			chain = &left
+			*chain = append(*chain, v)
+		}
+		v = next[v]
+		if v == a {
+			break
+		}
+	}
+	left = append(left, a)
+	d.tris = kept
+	d.edges = make(map[[2]int]int, len(d.tris)*3)
+	for i := range d.tris {
+		d.link(i)
+	}
+	d.fill(right)
+	d.fill(left)
+}
+
+// fill triangulates the polygon formed by a chain of points and the edge between
+// its ends, picking the point of each triangle on the edge so that no other
+// point in the chain is within its circumcircle.
+func (d *delaunay) fill(chain []int) {
+	if len(chain) < 3 {
+		return
+	}
+	a, b := chain[0], chain[len(chain)-1]
+	c := 1
+	for i := 2; i < len(chain)-1; i++ {
+		p, q, r := d.pts[a], d.pts[b], d.pts[chain[c]]
+		if cross(p, q, r) < 0 {
+			q, r = r, q
+		}
+		if inCircle(p, q, r, d.pts[chain[i]]) {
+			c = i
+		}
+	}
+	d.addTri(a, chain[c], b)
+	d.fill(chain[:c+1])
+	d.fill(chain[c:])
+}
+
+// crosses returns whether segments ab and cd cross at a point interior to both.
+func crosses(a, b, c, d Point2) bool {
+	return cross(a, b, c)*cross(a, b, d) < 0 && cross(c, d, a)*cross(c, d, b) < 0
+}
+
+// third returns the point of a triangle which is neither a nor b.
+func third(tri [3]int, a, b int) int {
+	for _, v := range tri {
+		if v != a && v != b {
+			return v
+		}
+	}
+	return -1
+}
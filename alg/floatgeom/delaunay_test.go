@@ -0,0 +1,129 @@
+package floatgeom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func triArea(pts []Point2, tris [][3]int) float64 {
+	area := 0.0
+	for _, t := range tris {
+		area += cross(pts[t[0]], pts[t[1]], pts[t[2]]) / 2
+	}
+	return area
+}
+
+func polyArea(pts []Point2) float64 {
+	if len(pts) < 3 {
+		return 0
+	}
+	return NewPolygon2(pts[0], pts[1], pts[2], pts[3:]...).SignedArea()
+}
+
+func checkDelaunay(t *testing.T, pts []Point2, tris [][3]int) {
+	t.Helper()
+	for _, tri := range tris {
+		a, b, c := pts[tri[0]], pts[tri[1]], pts[tri[2]]
+		if cross(a, b, c) <= 0 {
+			t.Fatalf("triangle %v is not wound positively", tri)
+		}
+		for i, p := range pts {
+			if i == tri[0] || i == tri[1] || i == tri[2] {
+				continue
+			}
+			if inCircle(a, b, c, p) {
+				t.Fatalf("point %v inside circumcircle of %v", p, tri)
+			}
+		}
+	}
+	hull := polyArea(ConvexHull(pts...))
+	if got := triArea(pts, tris); math.Abs(got-hull) > 1e-9*hull {
+		t.Fatalf("expected triangles to cover hull area %v, covered %v", hull, got)
+	}
+}
+
+func TestDelaunayRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 3; n < 200; n += 13 {
+		pts := make([]Point2, n)
+		for i := range pts {
+			pts[i] = Point2{rng.Float64() * 100, rng.Float64() * 100}
+		}
+		tris := Delaunay(pts...)
+		checkDelaunay(t, pts, tris)
+	}
+}
+
+func TestDelaunayDegenerate(t *testing.T) {
+	// a grid has many cocircular points and collinear leading points
+	var grid []Point2
+	for x := 0; x < 6; x++ {
+		for y := 0; y < 6; y++ {
+			grid = append(grid, Point2{float64(x), float64(y)})
+		}
+	}
+	tris := Delaunay(grid...)
+	if len(tris) != 50 {
+		t.Fatalf("expected 50 triangles, got %v", len(tris))
+	}
+	checkDelaunay(t, grid, tris)
+
+	dupes := []Point2{{0, 0}, {1, 0}, {0, 1}, {1, 0}, {0, 0}}
+	if tris := Delaunay(dupes...); len(tris) != 1 {
+		t.Fatalf("expected duplicates to be ignored, got %v", tris)
+	}
+	if tris := Delaunay(Point2{0, 0}, Point2{1, 1}, Point2{2, 2}); tris != nil {
+		t.Fatalf("expected no triangles for collinear points, got %v", tris)
+	}
+}
+
+func TestConstrainedDelaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, n := range []int{5, 12, 40} {
+		// a star's edges cut across the triangles Delaunay would choose
+		var pts []Point2
+		for i := 0; i < 2*n; i++ {
+			r := 100.0
+			if i%2 == 1 {
+				r = 40
+			}
+			pts = append(pts, RadianPoint(math.Pi*float64(i)/float64(n)).MulConst(r))
+		}
+		var edges [][2]int
+		for i := range pts {
+			edges = append(edges, [2]int{i, (i + 1) % len(pts)})
+		}
+		for i := 0; i < n; i++ {
+			pts = append(pts, Point2{rng.Float64()*200 - 100, rng.Float64()*200 - 100})
+		}
+		tris := ConstrainedDelaunay(pts, edges)
+		has := make(map[[2]int]bool)
+		for _, tri := range tris {
+			if cross(pts[tri[0]], pts[tri[1]], pts[tri[2]]) <= 0 {
+				t.Fatalf("triangle %v is not wound positively", tri)
+			}
+			for k := 0; k < 3; k++ {
+				has[[2]int{tri[k], tri[(k+1)%3]}] = true
+			}
+		}
+		for _, e := range edges {
+			if !has[e] && !has[[2]int{e[1], e[0]}] {
+				t.Fatalf("%d point star: missing constrained edge %v", n, e)
+			}
+		}
+		hull := polyArea(ConvexHull(pts...))
+		if got := triArea(pts, tris); math.Abs(got-hull) > 1e-9*hull {
+			t.Fatalf("%d point star: expected triangles to cover hull area %v, covered %v", n, hull, got)
+		}
+	}
+	if tris := ConstrainedDelaunay([]Point2{{0, 0}, {1, 1}, {2, 2}}, [][2]int{{0, 2}}); tris != nil {
+		t.Fatalf("expected no triangles for collinear points, got %v", tris)
+	}
+	// edges which are already Delaunay change nothing
+	grid := []Point2{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {3, 3}}
+	want := Delaunay(grid...)
+	if got := ConstrainedDelaunay(grid, [][2]int{{0, 1}, {1, 1}}); len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
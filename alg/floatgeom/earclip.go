@@ -0,0 +1,85 @@
+package floatgeom
+
+// Triangulate splits a simple polygon, which may be concave, into triangles by
+// ear clipping. Each triangle holds indices into the polygon's points and is
+// wound positively (see ConvexHull). Points along straight edges of the polygon
+// are skipped. Self intersecting polygons are not supported, but will still be
+// covered by some triangles.
+func (pg Polygon2) Triangulate() [][3]int {
+	n := len(pg.Points)
+	if n < 3 {
+		return nil
+	}
+	remaining := make([]int, n)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	if pg.SignedArea() < 0 {
+		for i := range remaining {
+			remaining[i] = n - 1 - i
+		}
+	}
+	pt := func(i int) Point2 {
+		return pg.Points[remaining[i]]
+	}
+	// drop points along straight edges, which would only form empty triangles
+	for i := 0; i < len(remaining) && len(remaining) > 3; {
+		m := len(remaining)
+		if cross(pt((i+m-1)%m), pt(i), pt((i+1)%m)) == 0 {
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			i = 0
+			continue
+		}
+		i++
+	}
+	tris := make([][3]int, 0, n-2)
+	for len(remaining) > 3 {
+		m := len(remaining)
+		clipped := false
+		for i := 0; i < m; i++ {
+			prev, next := (i+m-1)%m, (i+1)%m
+			if cross(pt(prev), pt(i), pt(next)) <= 0 || !pg.isEar(remaining, prev, i, next) {
+				continue
+			}
+			tris = append(tris, [3]int{remaining[prev], remaining[i], remaining[next]})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			// the polygon is not simple; clip anything to make progress
+			tris = append(tris, pg.wound(remaining[m-1], remaining[0], remaining[1]))
+			remaining = remaining[1:]
+		}
+	}
+	if cross(pt(0), pt(1), pt(2)) != 0 {
+		tris = append(tris, pg.wound(remaining[0], remaining[1], remaining[2]))
+	}
+	return tris
+}
+
+// isEar returns whether no other remaining point lies within the triangle at
+// positions prev, i and next of remaining.
+func (pg Polygon2) isEar(remaining []int, prev, i, next int) bool {
+	a, b, c := pg.Points[remaining[prev]], pg.Points[remaining[i]], pg.Points[remaining[next]]
+	for j, r := range remaining {
+		if j == prev || j == i || j == next {
+			continue
+		}
+		p := pg.Points[r]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if cross(a, b, p) >= 0 && cross(b, c, p) >= 0 && cross(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (pg Polygon2) wound(a, b, c int) [3]int {
+	if cross(pg.Points[a], pg.Points[b], pg.Points[c]) < 0 {
+		return [3]int{a, c, b}
+	}
+	return [3]int{a, b, c}
+}
@@ -0,0 +1,43 @@
+package floatgeom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygon2Triangulate(t *testing.T) {
+	tcs := []struct {
+		name string
+		pts  []Point2
+		tris int
+	}{
+		{"triangle", []Point2{{0, 0}, {1, 0}, {0, 1}}, 1},
+		{"square", []Point2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, 2},
+		{"reversed square", []Point2{{0, 1}, {1, 1}, {1, 0}, {0, 0}}, 2},
+		{"u shape", []Point2{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}, 6},
+		{"collinear points", []Point2{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}}, 2},
+		{"star", []Point2{
+			{0, 3}, {1, 1}, {3, 1}, {1.5, -.5}, {2, -3}, {0, -1.5}, {-2, -3}, {-1.5, -.5}, {-3, 1}, {-1, 1},
+		}, 8},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			pg := NewPolygon2(tc.pts[0], tc.pts[1], tc.pts[2], tc.pts[3:]...)
+			tris := pg.Triangulate()
+			if len(tris) != tc.tris {
+				t.Fatalf("expected %v triangles, got %v", tc.tris, tris)
+			}
+			want := pg.Area()
+			if got := triArea(pg.Points, tris); math.Abs(got-want) > 1e-9 {
+				t.Fatalf("expected triangles to cover area %v, covered %v", want, got)
+			}
+			for _, tri := range tris {
+				a, b, c := pg.Points[tri[0]], pg.Points[tri[1]], pg.Points[tri[2]]
+				center := a.Add(b, c).DivConst(3)
+				if !pg.Contains(center.X(), center.Y()) {
+					t.Fatalf("triangle %v is outside polygon", tri)
+				}
+			}
+		})
+	}
+}
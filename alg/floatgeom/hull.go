@@ -0,0 +1,60 @@
+package floatgeom
+
+import "sort"
+
+// cross returns twice the signed area of the triangle abc. It is positive if c is
+// to the left of the line from a to b, with y increasing upwards, and so negative
+// if c appears to the left on screen.
+func cross(a, b, c Point2) float64 {
+	return b.Sub(a).Cross(c.Sub(a))
+}
+
+// sortPoints sorts points by x, then y.
+func sortPoints(pts []Point2) {
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].X() != pts[j].X() {
+			return pts[i].X() < pts[j].X()
+		}
+		return pts[i].Y() < pts[j].Y()
+	})
+}
+
+// ConvexHull returns the smallest convex polygon containing all of the given
+// points, using Andrew's monotone chain algorithm. The hull's points are wound so
+// that cross products of consecutive edges are positive, starting from the point
+// with the least x value. Points along the hull's edges are not included.
+//
+// If fewer than three points are given, or all points are collinear, the
+// returned slice holds the distinct extreme points.
+func ConvexHull(pts ...Point2) []Point2 {
+	sorted := make([]Point2, len(pts))
+	copy(sorted, pts)
+	sortPoints(sorted)
+	if len(sorted) < 3 {
+		if len(sorted) == 2 && sorted[0] == sorted[1] {
+			return sorted[:1]
+		}
+		return sorted
+	}
+	hull := make([]Point2, 0, len(sorted)*2)
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// the last point is the first point again
+	hull = hull[:len(hull)-1]
+	if len(hull) == 2 && hull[0] == hull[1] {
+		return hull[:1]
+	}
+	return hull
+}
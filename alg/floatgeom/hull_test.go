@@ -0,0 +1,44 @@
+package floatgeom
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	hull := ConvexHull(
+		Point2{0, 0}, Point2{2, 0}, Point2{4, 0}, Point2{4, 4},
+		Point2{0, 4}, Point2{1, 1}, Point2{3, 2}, Point2{4, 4},
+	)
+	want := []Point2{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	if !reflect.DeepEqual(hull, want) {
+		t.Fatalf("expected hull %v, got %v", want, hull)
+	}
+	if got := ConvexHull(Point2{0, 0}, Point2{1, 1}, Point2{2, 2}); !reflect.DeepEqual(got, []Point2{{0, 0}, {2, 2}}) {
+		t.Fatalf("expected collinear hull to be its ends, got %v", got)
+	}
+	if got := ConvexHull(Point2{1, 1}, Point2{1, 1}); !reflect.DeepEqual(got, []Point2{{1, 1}}) {
+		t.Fatalf("expected duplicate points to have a single point hull, got %v", got)
+	}
+	if got := ConvexHull(); len(got) != 0 {
+		t.Fatalf("expected empty hull, got %v", got)
+	}
+}
+
+func TestConvexHullRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	pts := make([]Point2, 200)
+	for i := range pts {
+		pts[i] = Point2{rng.Float64() * 100, rng.Float64() * 100}
+	}
+	hull := ConvexHull(pts...)
+	for i := range hull {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		for _, p := range pts {
+			if cross(a, b, p) < 0 {
+				t.Fatalf("point %v outside hull edge %v %v", p, a, b)
+			}
+		}
+	}
+}
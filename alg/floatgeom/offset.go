@@ -0,0 +1,112 @@
+package floatgeom
+
+import "math"
+
+// A Join determines the shape of corners added when offsetting polygons.
+type Join int
+
+const (
+	// MiterJoin extends edges until they meet, beveling corners where they would
+	// meet more than MiterLimit times the offset distance from the original
+	// corner.
+	MiterJoin Join = iota
+	// RoundJoin rounds corners with arcs.
+	RoundJoin
+)
+
+// MiterLimit is how far, as a multiple of the offset distance, a mitered corner
+// may extend before it is beveled.
+const MiterLimit = 2.0
+
+// roundSegments is how many segments approximate a full circle in round joins.
+const roundSegments = 32
+
+// Offset returns this polygon grown outwards by d, or shrunk inwards if d is
+// negative. See Region.Offset.
+func (pg Polygon2) Offset(d float64, join Join) []Region {
+	return NewRegion(pg).Offset(d, join)
+}
+
+// Offset returns this region grown outwards by d, or shrunk inwards if d is
+// negative, so every point of the result's outline is d from the region's
+// outline. Growing may merge holes away, and shrinking may split the region.
+func (r Region) Offset(d float64, join Join) []Region {
+	r = r.Normalize()
+	if d == 0 {
+		return []Region{r}
+	}
+	dist := math.Abs(d)
+	var shapes [][]Point2
+	for _, ring := range rings([]Region{r}) {
+		n := len(ring)
+		for i, p := range ring {
+			q := ring[(i+1)%n]
+			if p == q {
+				continue
+			}
+			// a band covering both sides of each edge
+			normal := leftNormal(p, q).MulConst(dist)
+			shapes = append(shapes, []Point2{
+				p.Sub(normal), q.Sub(normal), q.Add(normal), p.Add(normal),
+			})
+			// corners leave gaps between bands outside convex corners when
+			// growing, and inside concave corners when shrinking
+			prev := ring[(i+n-1)%n]
+			turn := cross(prev, p, q)
+			if turn == 0 || (turn > 0) != (d > 0) {
+				continue
+			}
+			if corner, ok := joinShape(prev, p, q, d, join); ok {
+				shapes = append(shapes, corner.Wound(true).Points)
+			}
+		}
+	}
+	// every band and corner is resolved against the region at once: growing keeps
+	// what is in the region or any shape, and shrinking what is in the region and
+	// no shape
+	return fill([][][]Point2{rings([]Region{r}), shapes}, func(winding []int) bool {
+		if d > 0 {
+			return winding[0] > 0 || winding[1] > 0
+		}
+		return winding[0] > 0 && winding[1] == 0
+	})
+}
+
+// leftNormal returns the unit normal to the left of the line from a to b, which
+// points into a positively wound polygon.
+func leftNormal(a, b Point2) Point2 {
+	dir := b.Sub(a).Normalize()
+	return Point2{-dir.Y(), dir.X()}
+}
+
+// joinShape returns the polygon filling the gap at corner p between the offset
+// edges prev-p and p-next.
+func joinShape(prev, p, next Point2, d float64, join Join) (Polygon2, bool) {
+	// the gap is outside the polygon when growing and inside when shrinking
+	n1 := leftNormal(prev, p).MulConst(-d)
+	n2 := leftNormal(p, next).MulConst(-d)
+	switch join {
+	case RoundJoin:
+		a1, a2 := n1.ToRadians(), n2.ToRadians()
+		sweep := math.Mod(a2-a1+3*math.Pi, 2*math.Pi) - math.Pi
+		steps := int(math.Ceil(math.Abs(sweep) / (2 * math.Pi / roundSegments)))
+		pts := []Point2{p}
+		dist := math.Abs(d)
+		for i := 0; i <= steps; i++ {
+			a := a1 + sweep*float64(i)/float64(steps)
+			pts = append(pts, p.Add(RadianPoint(a).MulConst(dist)))
+		}
+		if len(pts) < 3 {
+			return Polygon2{}, false
+		}
+		return polygonOf(pts), true
+	default:
+		mid := n1.Add(n2).Normalize()
+		dot := mid.Dot(n1.Normalize())
+		if dot <= 1/MiterLimit {
+			return polygonOf([]Point2{p, p.Add(n1), p.Add(n2)}), true
+		}
+		miter := p.Add(mid.MulConst(math.Abs(d) / dot))
+		return polygonOf([]Point2{p, p.Add(n1), miter, p.Add(n2)}), true
+	}
+}
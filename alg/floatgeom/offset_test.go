@@ -0,0 +1,124 @@
+package floatgeom
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestOffset(t *testing.T) {
+	sq := square(0, 0, 10)
+	miter := sq.Offset(1, MiterJoin)
+	if len(miter) != 1 || math.Abs(miter[0].Area()-144) > 1e-9 {
+		t.Fatalf("expected mitered square of area 144, got %v", miter)
+	}
+	if len(miter[0].Outer.Points) != 4 {
+		t.Fatalf("expected mitered square to keep square corners, got %v", miter[0].Outer.Points)
+	}
+	round := sq.Offset(1, RoundJoin)
+	want := 100 + 40 + math.Pi
+	if len(round) != 1 || math.Abs(round[0].Area()-want) > .05 {
+		t.Fatalf("expected rounded square of area near %v, got %v", want, round[0].Area())
+	}
+	shrunk := sq.Offset(-1, RoundJoin)
+	if len(shrunk) != 1 || math.Abs(shrunk[0].Area()-64) > 1e-9 {
+		t.Fatalf("expected shrunk square of area 64, got %v", shrunk)
+	}
+	if gone := sq.Offset(-6, MiterJoin); len(gone) != 0 {
+		t.Fatalf("expected square to vanish, got %v", gone)
+	}
+}
+
+func TestOffsetConcave(t *testing.T) {
+	// an L shape, with one concave corner at 1,1
+	l := NewPolygon2(Point2{0, 0}, Point2{3, 0}, Point2{3, 1}, Point2{1, 1}, Point2{1, 3}, Point2{0, 3})
+	grown := l.Offset(.5, MiterJoin)
+	// the L grows to a 4x4 square minus a 2x2 square
+	if len(grown) != 1 || math.Abs(grown[0].Area()-12) > 1e-9 {
+		t.Fatalf("expected grown area 12, got %v", regionsArea(grown))
+	}
+	shrunk := l.Offset(-.25, RoundJoin)
+	// shrinking rounds the concave corner
+	want := 2.5*.5 + .5*2.5 - .25 + (1-math.Pi/4)*.25*.25
+	if len(shrunk) != 1 || math.Abs(shrunk[0].Area()-want) > .01 {
+		t.Fatalf("expected shrunk area near %v, got %v", want, regionsArea(shrunk))
+	}
+	// narrow passages split when shrunk
+	dumbbell := NewRegion(
+		NewPolygon2(Point2{0, 0}, Point2{4, 0}, Point2{4, 1.9}, Point2{6, 1.9}, Point2{6, 0}, Point2{10, 0},
+			Point2{10, 4}, Point2{6, 4}, Point2{6, 2.1}, Point2{4, 2.1}, Point2{4, 4}, Point2{0, 4}),
+	)
+	if split := dumbbell.Offset(-.5, MiterJoin); len(split) != 2 {
+		t.Fatalf("expected dumbbell to split in two, got %v", split)
+	}
+	// growing a frame closes its hole
+	frame := NewRegion(square(0, 0, 10), square(4, 4, 2))
+	if closed := frame.Offset(1.5, MiterJoin); len(closed) != 1 || len(closed[0].Holes) != 0 {
+		t.Fatalf("expected hole to close, got %v", closed)
+	}
+}
+
+func TestOffsetDistance(t *testing.T) {
+	pg := star(12)
+	edgeDistance := func(p Point2) float64 {
+		dist := math.Inf(1)
+		for i, a := range pg.Points {
+			dist = math.Min(dist, segmentDistance(p, a, pg.Points[(i+1)%len(pg.Points)]))
+		}
+		return dist
+	}
+	// a round offset holds every point within d of the star, give or take the
+	// error of approximating arcs with segments
+	const tolerance = .1
+	for _, d := range []float64{8, -8} {
+		offset := pg.Offset(d, RoundJoin)
+		for x := -110.0; x <= 110; x += 2.5 {
+			for y := -110.0; y <= 110; y += 2.5 {
+				p := Point2{x, y}
+				dist := edgeDistance(p)
+				if math.Abs(dist-math.Abs(d)) < tolerance {
+					continue
+				}
+				want := pg.Contains(x, y)
+				if d > 0 {
+					want = want || dist < d
+				} else {
+					want = want && dist > -d
+				}
+				if got := regionsContain(offset, p); got != want {
+					t.Fatalf("offset by %v: expected contains %v at %v, %v from the star", d, want, p, dist)
+				}
+			}
+		}
+	}
+}
+
+// star returns a star polygon with n points, alternating between radii of 100
+// and 40.
+func star(n int) Polygon2 {
+	pts := make([]Point2, 0, 2*n)
+	for i := 0; i < 2*n; i++ {
+		r := 100.0
+		if i%2 == 1 {
+			r = 40
+		}
+		pts = append(pts, RadianPoint(math.Pi*float64(i)/float64(n)).MulConst(r))
+	}
+	return polygonOf(pts)
+}
+
+func BenchmarkOffset(b *testing.B) {
+	for _, n := range []int{16, 128} {
+		for _, join := range []struct {
+			name string
+			Join
+		}{{"Miter", MiterJoin}, {"Round", RoundJoin}} {
+			pg := star(n / 2)
+			b.Run(fmt.Sprintf("%s%d", join.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					pg.Offset(5, join.Join)
+				}
+			})
+		}
+	}
+}
@@ -187,6 +187,13 @@ func (p Point2) MulConst(fs ...float64) Point2 {
 	return p
 }
 
+// Cross returns the z component of the cross product of two Point 2s extended
+// into three dimensions. It is positive if p2 is counter-clockwise from p, with y
+// increasing upwards.
+func (p Point2) Cross(p2 Point2) float64 {
+	return p.X()*p2.Y() - p.Y()*p2.X()
+}
+
 // Cross gets the cross product of two Point 3s
 func (p Point3) Cross(p2 Point3) Point3 {
 	return Point3{p.Y()*p2.Z() - p.Z()*p2.Y(), p.Z()*p2.X() - p.X()*p2.Z(), p.X()*p2.Y() - p.Y()*p2.X()}
@@ -0,0 +1,58 @@
+package floatgeom
+
+// A Region is the area inside an outer polygon and outside each of its holes.
+type Region struct {
+	Outer Polygon2
+	Holes []Polygon2
+}
+
+// NewRegion creates a region, normalizing its winding.
+func NewRegion(outer Polygon2, holes ...Polygon2) Region {
+	return Region{Outer: outer, Holes: holes}.Normalize()
+}
+
+// Normalize returns this region with its outer polygon wound positively and its
+// holes wound negatively (see ConvexHull), the winding expected by boolean
+// operations and offsetting.
+func (r Region) Normalize() Region {
+	holes := make([]Polygon2, len(r.Holes))
+	for i, h := range r.Holes {
+		holes[i] = h.Wound(false)
+	}
+	return Region{Outer: r.Outer.Wound(true), Holes: holes}
+}
+
+// Contains returns whether this region contains the given point.
+func (r Region) Contains(x, y float64) bool {
+	if !r.Outer.Contains(x, y) {
+		return false
+	}
+	for _, h := range r.Holes {
+		if h.Contains(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// Area returns the area of this region.
+func (r Region) Area() float64 {
+	area := r.Outer.Area()
+	for _, h := range r.Holes {
+		area -= h.Area()
+	}
+	return area
+}
+
+// rings returns the outer polygon and holes of a set of normalized regions.
+func rings(rs []Region) [][]Point2 {
+	var out [][]Point2
+	for _, r := range rs {
+		r = r.Normalize()
+		out = append(out, r.Outer.Points)
+		for _, h := range r.Holes {
+			out = append(out, h.Points)
+		}
+	}
+	return out
+}
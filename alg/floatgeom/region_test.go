@@ -0,0 +1,66 @@
+package floatgeom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegionNormalize(t *testing.T) {
+	r := Region{Outer: square(0, 0, 10).Reverse(), Holes: []Polygon2{square(2, 2, 2), square(6, 6, 2).Reverse()}}
+	n := r.Normalize()
+	if n.Outer.SignedArea() <= 0 {
+		t.Fatalf("expected outer polygon wound positively, area %v", n.Outer.SignedArea())
+	}
+	for i, h := range n.Holes {
+		if h.SignedArea() >= 0 {
+			t.Fatalf("expected hole %d wound negatively, area %v", i, h.SignedArea())
+		}
+	}
+	if r.Outer.SignedArea() >= 0 || r.Holes[0].SignedArea() <= 0 {
+		t.Fatalf("normalize modified the original region")
+	}
+	if again := n.Normalize(); again.Outer.SignedArea() != n.Outer.SignedArea() || again.Holes[0].SignedArea() != n.Holes[0].SignedArea() {
+		t.Fatalf("normalizing twice changed the region")
+	}
+}
+
+func TestRegionContains(t *testing.T) {
+	r := NewRegion(square(0, 0, 10), square(2, 2, 2), square(6, 6, 2))
+	tests := []struct {
+		p    Point2
+		want bool
+	}{
+		{Point2{1, 1}, true},
+		{Point2{5, 5}, true},
+		{Point2{3, 3}, false},
+		{Point2{7, 7}, false},
+		{Point2{11, 5}, false},
+		{Point2{-1, -1}, false},
+	}
+	for _, tc := range tests {
+		if got := r.Contains(tc.p.X(), tc.p.Y()); got != tc.want {
+			t.Fatalf("contains %v: expected %v, got %v", tc.p, tc.want, got)
+		}
+	}
+}
+
+func TestRegionArea(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Region
+		want float64
+	}{
+		{"square", NewRegion(square(0, 0, 10)), 100},
+		{"reversed", Region{Outer: square(0, 0, 10).Reverse()}, 100},
+		{"holes", NewRegion(square(0, 0, 10), square(2, 2, 2), square(6, 6, 3)), 100 - 4 - 9},
+		{"unnormalized holes", Region{Outer: square(0, 0, 10), Holes: []Polygon2{square(2, 2, 2)}}, 96},
+		{"triangle", NewRegion(NewPolygon2(Point2{0, 0}, Point2{4, 0}, Point2{0, 3})), 6},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.r.Area(); math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("expected area %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package floatgeom
+
+// Voronoi returns the Voronoi cell of each point within bounds: the area closer
+// to that point than to any other. Cells are wound positively (see ConvexHull),
+// and are nil for points whose cell does not reach into bounds. Points sharing a
+// position share a cell.
+func Voronoi(bounds Rect2, pts ...Point2) [][]Point2 {
+	order := uniqueOrder(pts)
+	first := make(map[Point2]int, len(order))
+	for _, o := range order {
+		first[pts[o]] = o
+	}
+	neighbors := make(map[int]map[int]bool, len(order))
+	link := func(a, b int) {
+		if neighbors[a] == nil {
+			neighbors[a] = make(map[int]bool)
+		}
+		neighbors[a][b] = true
+	}
+	tris := Delaunay(pts...)
+	if len(tris) == 0 {
+		// the points are collinear, and neighbor only the points beside them
+		for i := 1; i < len(order); i++ {
+			link(order[i-1], order[i])
+			link(order[i], order[i-1])
+		}
+	}
+	for _, t := range tris {
+		for k := 0; k < 3; k++ {
+			link(t[k], t[(k+1)%3])
+			link(t[(k+1)%3], t[k])
+		}
+	}
+	box := []Point2{
+		bounds.Min,
+		{bounds.Max.X(), bounds.Min.Y()},
+		bounds.Max,
+		{bounds.Min.X(), bounds.Max.Y()},
+	}
+	cells := make([][]Point2, len(pts))
+	for i, p := range pts {
+		owner := first[p]
+		if owner != i {
+			continue
+		}
+		cell := box
+		for n := range neighbors[i] {
+			cell = clipHalfPlane(cell, p, pts[n])
+		}
+		if len(cell) >= 3 {
+			cells[i] = cell
+		}
+	}
+	for i, p := range pts {
+		cells[i] = cells[first[p]]
+	}
+	return cells
+}
+
+// clipHalfPlane returns the part of a convex polygon closer to p than to q.
+func clipHalfPlane(poly []Point2, p, q Point2) []Point2 {
+	mid := p.Add(q).DivConst(2)
+	dir := q.Sub(p)
+	side := func(v Point2) float64 {
+		return v.Sub(mid).Dot(dir)
+	}
+	out := make([]Point2, 0, len(poly)+1)
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		sa, sb := side(a), side(b)
+		if sa <= 0 {
+			out = append(out, a)
+		}
+		if (sa < 0 && sb > 0) || (sa > 0 && sb < 0) {
+			out = append(out, a.Add(b.Sub(a).MulConst(sa/(sa-sb))))
+		}
+	}
+	return out
+}
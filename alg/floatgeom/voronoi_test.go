@@ -0,0 +1,75 @@
+package floatgeom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVoronoi(t *testing.T) {
+	bounds := NewRect2(0, 0, 100, 100)
+	rng := rand.New(rand.NewSource(2))
+	pts := make([]Point2, 40)
+	for i := range pts {
+		pts[i] = Point2{rng.Float64() * 100, rng.Float64() * 100}
+	}
+	pts = append(pts, pts[3])
+	cells := Voronoi(bounds, pts...)
+	total := 0.0
+	for i, cell := range cells[:40] {
+		total += polyArea(cell)
+		// sample points in the cell should be closest to its site
+		c := Point2{}
+		for _, p := range cell {
+			c = c.Add(p)
+		}
+		c = c.DivConst(float64(len(cell)))
+		for j, p := range pts {
+			if c.Distance(p) < c.Distance(pts[i])-1e-9 {
+				t.Fatalf("cell %v's center is closer to point %v", i, j)
+			}
+		}
+	}
+	if math.Abs(total-100*100) > 1e-6 {
+		t.Fatalf("expected cells to tile bounds, covered %v", total)
+	}
+	if len(cells[40]) == 0 || &cells[40][0] != &cells[3][0] {
+		t.Fatal("expected duplicate point to share a cell")
+	}
+
+	cells = Voronoi(bounds, Point2{25, 50}, Point2{50, 50}, Point2{75, 50})
+	if a := polyArea(cells[1]); math.Abs(a-2500) > 1e-9 {
+		t.Fatalf("expected middle collinear cell of area 2500, got %v", a)
+	}
+	if cells := Voronoi(bounds, Point2{50, 50}, Point2{200, 200}); cells[1] != nil {
+		t.Fatalf("expected no cell for a point whose cell is outside bounds, got %v", cells[1])
+	}
+}
+
+func TestClipHalfPlane(t *testing.T) {
+	sq := square(0, 0, 10).Points
+	tests := []struct {
+		name string
+		p, q Point2
+		area float64
+	}{
+		{"halves", Point2{2, 5}, Point2{8, 5}, 50},
+		{"diagonal", Point2{0, 0}, Point2{10, 10}, 50},
+		{"quarter", Point2{1, 5}, Point2{6, 5}, 35},
+		{"all kept", Point2{5, 5}, Point2{50, 5}, 100},
+		{"all clipped", Point2{50, 5}, Point2{30, 5}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clipped := clipHalfPlane(sq, tc.p, tc.q)
+			if a := polyArea(clipped); math.Abs(a-tc.area) > 1e-9 {
+				t.Fatalf("expected area %v, got %v from %v", tc.area, a, clipped)
+			}
+			for _, v := range clipped {
+				if v.Distance(tc.p) > v.Distance(tc.q)+1e-9 {
+					t.Fatalf("kept point %v closer to %v than %v", v, tc.q, tc.p)
+				}
+			}
+		})
+	}
+}
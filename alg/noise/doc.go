@@ -0,0 +1,7 @@
+// Package noise provides seedable coherent noise: Perlin, simplex and Worley
+// noise, fractal combinations of them, and helpers to render noise to images or
+// sample it as a span.
+//
+// Noise functions are deterministic for a given seed, and unless documented
+// otherwise return values in [-1, 1].
+package noise
@@ -0,0 +1,119 @@
+package noise
+
+// A Fractal sums octaves of noise, each at a higher frequency and lower amplitude
+// than the last.
+type Fractal struct {
+	Octaves int
+	// Lacunarity is the frequency multiplier between octaves.
+	Lacunarity float64
+	// Gain is the amplitude multiplier between octaves.
+	Gain float64
+}
+
+// DefaultFractal sums five octaves, doubling frequency and halving amplitude with
+// each.
+var DefaultFractal = Fractal{
+	Octaves:    5,
+	Lacunarity: 2,
+	Gain:       .5,
+}
+
+// octaveOffset shifts each octave so that octaves do not share lattice points at
+// the origin.
+const octaveOffset = 31.416
+
+// sum combines octaves of noise, where sample returns noise for an octave at a
+// frequency and offset, normalized by the total amplitude.
+func (fr Fractal) sum(sample func(freq, off float64) float64) float64 {
+	total, amp, freq, max := 0.0, 1.0, 1.0, 0.0
+	for i := 0; i < fr.Octaves; i++ {
+		total += amp * sample(freq, float64(i)*octaveOffset)
+		max += amp
+		amp *= fr.Gain
+		freq *= fr.Lacunarity
+	}
+	if max == 0 {
+		return 0
+	}
+	return total / max
+}
+
+// FBM1 returns fractal Brownian motion of f.
+func (fr Fractal) FBM1(f Func1) Func1 {
+	return func(x float64) float64 {
+		return fr.sum(func(freq, off float64) float64 {
+			return f(x*freq + off)
+		})
+	}
+}
+
+// FBM2 returns fractal Brownian motion of f.
+func (fr Fractal) FBM2(f Func2) Func2 {
+	return func(x, y float64) float64 {
+		return fr.sum(func(freq, off float64) float64 {
+			return f(x*freq+off, y*freq+off)
+		})
+	}
+}
+
+// FBM3 returns fractal Brownian motion of f.
+func (fr Fractal) FBM3(f Func3) Func3 {
+	return func(x, y, z float64) float64 {
+		return fr.sum(func(freq, off float64) float64 {
+			return f(x*freq+off, y*freq+off, z*freq+off)
+		})
+	}
+}
+
+// ridge folds noise so that its zero crossings become sharp peaks.
+func ridge(n float64) float64 {
+	n = 1 - abs(n)
+	return n * n
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// Ridged2 returns ridged multifractal noise of f, with sharp crests where f
+// crosses zero, suited to mountain ranges.
+func (fr Fractal) Ridged2(f Func2) Func2 {
+	return func(x, y float64) float64 {
+		return fr.sum(func(freq, off float64) float64 {
+			return ridge(f(x*freq+off, y*freq+off))
+		})*2 - 1
+	}
+}
+
+// Ridged3 returns ridged multifractal noise of f. See Ridged2.
+func (fr Fractal) Ridged3(f Func3) Func3 {
+	return func(x, y, z float64) float64 {
+		return fr.sum(func(freq, off float64) float64 {
+			return ridge(f(x*freq+off, y*freq+off, z*freq+off))
+		})*2 - 1
+	}
+}
+
+// Warp2 returns f sampled at points displaced by warp, by up to strength in each
+// axis. The displacements along each axis are taken from distant areas of warp so
+// they are not correlated.
+func Warp2(f, warp Func2, strength float64) Func2 {
+	return func(x, y float64) float64 {
+		dx := warp(x, y)
+		dy := warp(x+5.2, y+1.3)
+		return f(x+dx*strength, y+dy*strength)
+	}
+}
+
+// Warp3 returns f sampled at points displaced by warp. See Warp2.
+func Warp3(f, warp Func3, strength float64) Func3 {
+	return func(x, y, z float64) float64 {
+		dx := warp(x, y, z)
+		dy := warp(x+5.2, y+1.3, z+2.8)
+		dz := warp(x+9.7, y+4.1, z+7.3)
+		return f(x+dx*strength, y+dy*strength, z+dz*strength)
+	}
+}
@@ -0,0 +1,60 @@
+package noise
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// TestGolden compares a grid of each kind of noise against recorded output, to
+// catch changes to the noise produced for a seed.
+func TestGolden(t *testing.T) {
+	p := NewPerlin(seeded(42))
+	s := NewSimplex(seeded(42))
+	w := NewWorley(seeded(42))
+	cases := map[string]Func2{
+		"perlin1":  func(x, _ float64) float64 { return p.Noise1(x) },
+		"perlin2":  p.Noise2,
+		"perlin3":  func(x, y float64) float64 { return p.Noise3(x, y, .5) },
+		"simplex2": s.Noise2,
+		"simplex3": func(x, y float64) float64 { return s.Noise3(x, y, .5) },
+		"worley2":  w.Noise2,
+		"worley3":  func(x, y float64) float64 { return w.Noise3(x, y, .5) },
+		"fbm":      DefaultFractal.FBM2(s.Noise2),
+		"ridged":   DefaultFractal.Ridged2(p.Noise2),
+		"warp":     Warp2(s.Noise2, p.Noise2, 1.5),
+	}
+	for name, f := range cases {
+		t.Run(name, func(t *testing.T) {
+			sb := strings.Builder{}
+			for y := 0; y < 8; y++ {
+				for x := 0; x < 8; x++ {
+					if x != 0 {
+						sb.WriteByte(' ')
+					}
+					fmt.Fprintf(&sb, "%.6f", f(float64(x)*.37, float64(y)*.37))
+				}
+				sb.WriteByte('\n')
+			}
+			file := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(file, []byte(sb.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != string(want) {
+				t.Fatalf("noise differs from %v:\n%v", file, sb.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package noise
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/oakmound/oak/v4/alg"
+)
+
+// A Func1 returns noise along a line.
+type Func1 func(x float64) float64
+
+// A Func2 returns noise across a plane.
+type Func2 func(x, y float64) float64
+
+// A Func3 returns noise through a volume.
+type Func3 func(x, y, z float64) float64
+
+// perm is a shuffled permutation of 0 through 255, repeated, used to hash
+// lattice coordinates.
+type perm [512]uint8
+
+func newPerm(rng alg.Float64Generator) *perm {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	p := new(perm)
+	for i := 0; i < 256; i++ {
+		p[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := int(rng.Float64() * float64(i+1))
+		p[i], p[j] = p[j], p[i]
+	}
+	for i := 0; i < 256; i++ {
+		p[i+256] = p[i]
+	}
+	return p
+}
+
+func (p *perm) hash1(x int) int {
+	return int(p[x&255])
+}
+
+func (p *perm) hash2(x, y int) int {
+	return int(p[int(p[x&255])+y&255])
+}
+
+func (p *perm) hash3(x, y, z int) int {
+	return int(p[int(p[int(p[x&255])+y&255])+z&255])
+}
+
+func floor(f float64) int {
+	return int(math.Floor(f))
+}
+
+func clamp(f float64) float64 {
+	return math.Max(-1, math.Min(1, f))
+}
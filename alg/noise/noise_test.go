@@ -0,0 +1,152 @@
+package noise
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/span"
+)
+
+func seeded(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// sources returns two dimensional noise from each generator, seeded with seed.
+func sources(seed int64) map[string]Func2 {
+	w := NewWorley(seeded(seed))
+	return map[string]Func2{
+		"perlin":  NewPerlin(seeded(seed)).Noise2,
+		"simplex": NewSimplex(seeded(seed)).Noise2,
+		"worley":  w.Noise2,
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	a, b, c := sources(1), sources(1), sources(2)
+	for name := range a {
+		differs := false
+		for i := 0; i < 100; i++ {
+			x, y := float64(i)*.37, float64(i)*.91
+			if a[name](x, y) != b[name](x, y) {
+				t.Fatalf("%v: same seed produced different noise", name)
+			}
+			if a[name](x, y) != c[name](x, y) {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Fatalf("%v: different seeds produced the same noise", name)
+		}
+	}
+}
+
+func TestRangeAndContinuity(t *testing.T) {
+	p := NewPerlin(seeded(1))
+	s := NewSimplex(seeded(1))
+	funcs := map[string]Func3{
+		"perlin1":  func(x, _, _ float64) float64 { return p.Noise1(x) },
+		"perlin2":  func(x, y, _ float64) float64 { return p.Noise2(x, y) },
+		"perlin3":  p.Noise3,
+		"simplex2": func(x, y, _ float64) float64 { return s.Noise2(x, y) },
+		"simplex3": s.Noise3,
+		"fbm":      DefaultFractal.FBM3(s.Noise3),
+		"ridged":   DefaultFractal.Ridged3(p.Noise3),
+		"warp":     Warp3(p.Noise3, s.Noise3, 2),
+	}
+	rng := seeded(3)
+	for name, f := range funcs {
+		min, max := math.Inf(1), math.Inf(-1)
+		for i := 0; i < 5000; i++ {
+			x, y, z := rng.Float64()*50-25, rng.Float64()*50-25, rng.Float64()*50-25
+			v := f(x, y, z)
+			min, max = math.Min(min, v), math.Max(max, v)
+			if d := math.Abs(f(x+1e-4, y+1e-4, z+1e-4) - v); d > .01 {
+				t.Fatalf("%v: discontinuity of %v at %v,%v,%v", name, d, x, y, z)
+			}
+		}
+		if min < -1 || max > 1 {
+			t.Fatalf("%v: values out of range: [%v, %v]", name, min, max)
+		}
+		if max-min < .5 {
+			t.Fatalf("%v: values barely vary: [%v, %v]", name, min, max)
+		}
+	}
+}
+
+func TestPerlinLattice(t *testing.T) {
+	p := NewPerlin(seeded(1))
+	for i := -5; i < 5; i++ {
+		f := float64(i)
+		if p.Noise1(f) != 0 || p.Noise2(f, f+2) != 0 || p.Noise3(f, f+1, f-3) != 0 {
+			t.Fatalf("expected zero noise on lattice point %v", i)
+		}
+	}
+}
+
+func TestWorley(t *testing.T) {
+	w := NewWorley(seeded(1))
+	rng := seeded(2)
+	for i := 0; i < 1000; i++ {
+		x, y := rng.Float64()*20, rng.Float64()*20
+		f1, f2, _ := w.Cell2(x, y)
+		if f1 < 0 || f2 < f1 {
+			t.Fatalf("expected 0 <= f1 <= f2, got %v %v", f1, f2)
+		}
+		w.Feature = F2MinusF1
+		if got := w.Noise2(x, y); math.Abs(got-(f2-f1)) > 1e-12 {
+			t.Fatalf("expected F2-F1 of %v, got %v", f2-f1, got)
+		}
+		w.Feature = F1
+		for _, m := range []Metric{Manhattan, Chebyshev} {
+			w.Metric = m
+			if v := w.Noise3(x, y, 1.5); v < 0 || v > 3 {
+				t.Fatalf("unexpected distance %v", v)
+			}
+		}
+		w.Metric = Euclidean
+	}
+}
+
+func TestImage(t *testing.T) {
+	p := NewPerlin(seeded(1))
+	img := Image(p.Noise2, 16, 8, .1, nil)
+	if b := img.Bounds(); b.Dx() != 16 || b.Dy() != 8 {
+		t.Fatalf("unexpected bounds %v", b)
+	}
+	// the origin is a lattice point, so is mid gray
+	if c := img.RGBAAt(0, 0); c.R < 126 || c.R > 128 || c.R != c.G || c.A != 255 {
+		t.Fatalf("expected gray at origin, got %v", c)
+	}
+	red := span.NewLinearColor(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 0, 0, 255})
+	img = Image(p.Noise2, 4, 4, .3, red)
+	if c := img.RGBAAt(3, 3); c.G != 0 || c.R == 0 {
+		t.Fatalf("expected red shade, got %v", c)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(NewPerlin(seeded(1)).Noise1, -5, 5, .05)
+	prev := s.Poll()
+	for i := 0; i < 200; i++ {
+		v := s.Poll()
+		if v < -5 || v > 5 {
+			t.Fatalf("polled %v outside of span", v)
+		}
+		if math.Abs(v-prev) > 1 {
+			t.Fatalf("expected smooth polls, went from %v to %v", prev, v)
+		}
+		prev = v
+	}
+	if s.Clamp(7) != 5 || s.Clamp(-7) != -5 || s.Clamp(1) != 1 {
+		t.Fatal("unexpected clamping")
+	}
+	if s.Percentile(0) != 0 {
+		t.Fatalf("expected midpoint at lattice point, got %v", s.Percentile(0))
+	}
+	doubled := s.MulSpan(2)
+	if doubled.Clamp(9) != 9 || s.Clamp(9) != 5 {
+		t.Fatal("expected MulSpan to scale a copy")
+	}
+}
@@ -0,0 +1,118 @@
+package noise
+
+import "github.com/oakmound/oak/v4/alg"
+
+// Perlin generates gradient noise on a square lattice, with Ken Perlin's
+// improved fade curve and gradients.
+type Perlin struct {
+	perm *perm
+}
+
+// NewPerlin creates Perlin noise shuffled by rng. If rng is nil, the noise is
+// shuffled by a randomly seeded generator.
+func NewPerlin(rng alg.Float64Generator) *Perlin {
+	return &Perlin{perm: newPerm(rng)}
+}
+
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+func grad1(h int, x float64) float64 {
+	g := float64(h&7) + 1
+	if h&8 != 0 {
+		g = -g
+	}
+	return g * x
+}
+
+func grad2(h int, x, y float64) float64 {
+	switch h & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+func grad3(h int, x, y, z float64) float64 {
+	h &= 15
+	u := x
+	if h >= 8 {
+		u = y
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// Noise1 returns Perlin noise at x.
+func (p *Perlin) Noise1(x float64) float64 {
+	xi := floor(x)
+	xf := x - float64(xi)
+	n := lerp(fade(xf),
+		grad1(p.perm.hash1(xi), xf),
+		grad1(p.perm.hash1(xi+1), xf-1),
+	)
+	// gradients range up to 8, and contribute at most half of that
+	return clamp(n / 4)
+}
+
+// Noise2 returns Perlin noise at x, y.
+func (p *Perlin) Noise2(x, y float64) float64 {
+	xi, yi := floor(x), floor(y)
+	xf, yf := x-float64(xi), y-float64(yi)
+	u, v := fade(xf), fade(yf)
+	n := lerp(v,
+		lerp(u, grad2(p.perm.hash2(xi, yi), xf, yf), grad2(p.perm.hash2(xi+1, yi), xf-1, yf)),
+		lerp(u, grad2(p.perm.hash2(xi, yi+1), xf, yf-1), grad2(p.perm.hash2(xi+1, yi+1), xf-1, yf-1)),
+	)
+	return clamp(n)
+}
+
+// Noise3 returns Perlin noise at x, y, z.
+func (p *Perlin) Noise3(x, y, z float64) float64 {
+	xi, yi, zi := floor(x), floor(y), floor(z)
+	xf, yf, zf := x-float64(xi), y-float64(yi), z-float64(zi)
+	u, v, w := fade(xf), fade(yf), fade(zf)
+	corner := func(dx, dy, dz int) float64 {
+		return grad3(p.perm.hash3(xi+dx, yi+dy, zi+dz), xf-float64(dx), yf-float64(dy), zf-float64(dz))
+	}
+	n := lerp(w,
+		lerp(v,
+			lerp(u, corner(0, 0, 0), corner(1, 0, 0)),
+			lerp(u, corner(0, 1, 0), corner(1, 1, 0)),
+		),
+		lerp(v,
+			lerp(u, corner(0, 0, 1), corner(1, 0, 1)),
+			lerp(u, corner(0, 1, 1), corner(1, 1, 1)),
+		),
+	)
+	return clamp(n)
+}
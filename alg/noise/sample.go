@@ -0,0 +1,82 @@
+package noise
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/span"
+)
+
+// Image renders f into a new image of size w by h, sampling f at each pixel's
+// position multiplied by scale. Values are mapped from [-1, 1] onto colors, or
+// onto a grayscale ramp if colors is nil.
+func Image(f Func2, w, h int, scale float64, colors span.Span[color.Color]) *image.RGBA {
+	if colors == nil {
+		colors = span.NewLinearColor(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			v := f(float64(x)*scale, float64(y)*scale)
+			rgba.Set(x, y, colors.Percentile((clamp(v)+1)/2))
+		}
+	}
+	return rgba
+}
+
+// A Sampler is a span of values in [Min, Max] polled from noise, so successive
+// polls vary smoothly rather than independently. It suits effects like screen
+// shake and flickering lights.
+type Sampler struct {
+	Noise    Func1
+	Min, Max float64
+	// Step is how far along Noise each Poll advances.
+	Step float64
+
+	pos float64
+}
+
+var _ span.Span[float64] = &Sampler{}
+
+// NewSampler creates a sampler polling f between min and max.
+func NewSampler(f Func1, min, max, step float64) *Sampler {
+	return &Sampler{
+		Noise: f,
+		Min:   min,
+		Max:   max,
+		Step:  step,
+	}
+}
+
+func (s *Sampler) at(x float64) float64 {
+	return s.Min + (clamp(s.Noise(x))+1)/2*(s.Max-s.Min)
+}
+
+// Poll returns the noise at the sampler's position, then advances it by Step.
+// Poll is not safe for concurrent use.
+func (s *Sampler) Poll() float64 {
+	v := s.at(s.pos)
+	s.pos += s.Step
+	return v
+}
+
+// Clamp returns v limited to [Min, Max].
+func (s *Sampler) Clamp(v float64) float64 {
+	lo, hi := math.Min(s.Min, s.Max), math.Max(s.Min, s.Max)
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// Percentile returns the noise at position f, mapped into [Min, Max]. Unlike
+// other spans, Percentile is not monotonic.
+func (s *Sampler) Percentile(f float64) float64 {
+	return s.at(f)
+}
+
+// MulSpan returns a copy of this sampler with Min and Max multiplied by i.
+func (s *Sampler) MulSpan(i float64) span.Span[float64] {
+	s2 := *s
+	s2.Min *= i
+	s2.Max *= i
+	return &s2
+}
@@ -0,0 +1,101 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg"
+)
+
+const (
+	skew2   = 0.36602540378443864676 // (sqrt(3)-1)/2
+	unskew2 = 0.21132486540518711775 // (3-sqrt(3))/6
+
+	// radius2 and radius3 are the squared radii of each lattice point's influence,
+	// as used by OpenSimplex2's smooth variant.
+	radius2 = 2.0 / 3
+	radius3 = 0.75
+
+	// norm2 and norm3 scale summed contributions to [-1, 1].
+	norm2 = 18.0
+	norm3 = 12.6
+)
+
+var (
+	gradients2 [24][2]float64
+	gradients3 = [12][3]float64{
+		{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+		{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+		{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+	}
+)
+
+func init() {
+	for i := range gradients2 {
+		theta := (float64(i) + .5) * 2 * math.Pi / float64(len(gradients2))
+		gradients2[i] = [2]float64{math.Cos(theta), math.Sin(theta)}
+	}
+	for i, g := range gradients3 {
+		for j := range g {
+			gradients3[i][j] = g[j] / math.Sqrt2
+		}
+	}
+}
+
+// Simplex generates gradient noise on the lattices used by OpenSimplex2: a
+// triangular lattice in two dimensions and a body centered cubic lattice in
+// three. It has fewer directional artifacts than Perlin noise. Its output follows
+// OpenSimplex2's construction but does not match its reference implementation.
+type Simplex struct {
+	perm *perm
+}
+
+// NewSimplex creates simplex noise shuffled by rng. If rng is nil, the noise is
+// shuffled by a randomly seeded generator.
+func NewSimplex(rng alg.Float64Generator) *Simplex {
+	return &Simplex{perm: newPerm(rng)}
+}
+
+// Noise2 returns simplex noise at x, y.
+func (s *Simplex) Noise2(x, y float64) float64 {
+	sk := (x + y) * skew2
+	i, j := floor(x+sk), floor(y+sk)
+	n := 0.0
+	for di := -1; di <= 2; di++ {
+		for dj := -1; dj <= 2; dj++ {
+			li, lj := i+di, j+dj
+			un := float64(li+lj) * unskew2
+			dx, dy := x-(float64(li)-un), y-(float64(lj)-un)
+			a := radius2 - dx*dx - dy*dy
+			if a <= 0 {
+				continue
+			}
+			g := gradients2[s.perm.hash2(li, lj)%len(gradients2)]
+			a *= a
+			n += a * a * (g[0]*dx + g[1]*dy)
+		}
+	}
+	return clamp(n * norm2)
+}
+
+// Noise3 returns simplex noise at x, y, z.
+func (s *Simplex) Noise3(x, y, z float64) float64 {
+	n := 0.0
+	for lattice := 0; lattice < 2; lattice++ {
+		off := float64(lattice) / 2
+		i, j, k := floor(x-off), floor(y-off), floor(z-off)
+		for c := 0; c < 8; c++ {
+			li, lj, lk := i+c&1, j+c>>1&1, k+c>>2&1
+			dx := x - (float64(li) + off)
+			dy := y - (float64(lj) + off)
+			dz := z - (float64(lk) + off)
+			a := radius3 - dx*dx - dy*dy - dz*dz
+			if a <= 0 {
+				continue
+			}
+			g := gradients3[s.perm.hash3(li+lattice*128, lj, lk)%len(gradients3)]
+			a *= a
+			n += a * a * (g[0]*dx + g[1]*dy + g[2]*dz)
+		}
+	}
+	return clamp(n * norm3)
+}
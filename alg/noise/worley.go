@@ -0,0 +1,123 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg"
+)
+
+// A Feature selects which distances Worley noise returns.
+type Feature int
+
+const (
+	// F1 is the distance to the nearest feature point.
+	F1 Feature = iota
+	// F2 is the distance to the second nearest feature point.
+	F2
+	// F2MinusF1 is the difference between F2 and F1, which is near zero along the
+	// borders between cells.
+	F2MinusF1
+)
+
+// A Metric measures the distance between two points.
+type Metric int
+
+const (
+	// Euclidean distance is measured in a straight line, producing round cells.
+	Euclidean Metric = iota
+	// Manhattan distance is the sum of distances along each axis, producing
+	// diamond shaped cells.
+	Manhattan
+	// Chebyshev distance is the greatest distance along any axis, producing
+	// square cells.
+	Chebyshev
+)
+
+func (m Metric) distance(dx, dy, dz float64) float64 {
+	dx, dy, dz = math.Abs(dx), math.Abs(dy), math.Abs(dz)
+	switch m {
+	case Manhattan:
+		return dx + dy + dz
+	case Chebyshev:
+		return math.Max(dx, math.Max(dy, dz))
+	default:
+		return math.Sqrt(dx*dx + dy*dy + dz*dz)
+	}
+}
+
+// Worley generates cellular noise from one feature point placed randomly in each
+// lattice cell. Unlike other noise in this package it returns distances, which
+// are at least 0 and rarely exceed 1 for F1 or 1.5 for F2.
+type Worley struct {
+	Feature Feature
+	Metric  Metric
+
+	perm *perm
+}
+
+// NewWorley creates Worley noise returning F1 Euclidean distances, shuffled by
+// rng. If rng is nil, the noise is shuffled by a randomly seeded generator.
+func NewWorley(rng alg.Float64Generator) *Worley {
+	return &Worley{perm: newPerm(rng)}
+}
+
+func (w *Worley) feature(f1, f2 float64) float64 {
+	switch w.Feature {
+	case F2:
+		return f2
+	case F2MinusF1:
+		return f2 - f1
+	default:
+		return f1
+	}
+}
+
+// Noise2 returns Worley noise at x, y.
+func (w *Worley) Noise2(x, y float64) float64 {
+	f1, f2, _ := w.Cell2(x, y)
+	return w.feature(f1, f2)
+}
+
+// Cell2 returns the distances to the nearest and second nearest feature points
+// to x, y, and an identifier for the cell of the nearest point, from 0 to 255.
+func (w *Worley) Cell2(x, y float64) (f1, f2 float64, id int) {
+	xi, yi := floor(x), floor(y)
+	f1, f2 = math.Inf(1), math.Inf(1)
+	for i := xi - 1; i <= xi+1; i++ {
+		for j := yi - 1; j <= yi+1; j++ {
+			h := w.perm.hash2(i, j)
+			px := float64(i) + float64(h)/256
+			py := float64(j) + float64(w.perm.hash3(i, j, 97))/256
+			d := w.Metric.distance(x-px, y-py, 0)
+			if d < f1 {
+				f1, f2, id = d, f1, h
+			} else if d < f2 {
+				f2 = d
+			}
+		}
+	}
+	return f1, f2, id
+}
+
+// Noise3 returns Worley noise at x, y, z.
+func (w *Worley) Noise3(x, y, z float64) float64 {
+	xi, yi, zi := floor(x), floor(y), floor(z)
+	f1, f2 := math.Inf(1), math.Inf(1)
+	for i := xi - 1; i <= xi+1; i++ {
+		for j := yi - 1; j <= yi+1; j++ {
+			for k := zi - 1; k <= zi+1; k++ {
+				h := w.perm.hash3(i, j, k)
+				px := float64(i) + float64(h)/256
+				py := float64(j) + float64(w.perm.hash3(i+97, j, k))/256
+				pz := float64(k) + float64(w.perm.hash3(i+193, j, k))/256
+				d := w.Metric.distance(x-px, y-py, z-pz)
+				if d < f1 {
+					f1, f2 = d, f1
+				} else if d < f2 {
+					f2 = d
+				}
+			}
+		}
+	}
+	return w.feature(f1, f2)
+}
@@ -0,0 +1,85 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+type openNode struct {
+	index    int
+	priority float64
+}
+
+type openList []openNode
+
+func (o openList) Len() int            { return len(o) }
+func (o openList) Less(i, j int) bool  { return o[i].priority < o[j].priority }
+func (o openList) Swap(i, j int)       { o[i], o[j] = o[j], o[i] }
+func (o *openList) Push(x interface{}) { *o = append(*o, x.(openNode)) }
+func (o *openList) Pop() interface{} {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return n
+}
+
+// AStar returns the cheapest path of cells from start to goal, including both,
+// and whether one exists.
+func AStar(g *Grid, start, goal intgeom.Point2, conn Connectivity) ([]intgeom.Point2, bool) {
+	if !g.Walkable(start) || !g.Walkable(goal) {
+		return nil, false
+	}
+	moves := conn.moves()
+	costs := make([]float64, len(g.costs))
+	for i := range costs {
+		costs[i] = math.Inf(1)
+	}
+	parents := make([]int, len(g.costs))
+	closed := make([]bool, len(g.costs))
+	startIdx, goalIdx := g.index(start), g.index(goal)
+	costs[startIdx] = 0
+	parents[startIdx] = -1
+	open := &openList{{index: startIdx, priority: conn.heuristic(start, goal)}}
+	for open.Len() != 0 {
+		cur := heap.Pop(open).(openNode).index
+		if closed[cur] {
+			continue
+		}
+		if cur == goalIdx {
+			return g.trace(parents, cur), true
+		}
+		closed[cur] = true
+		p := g.point(cur)
+		for _, d := range moves {
+			step, ok := g.canStep(p, d)
+			if !ok {
+				continue
+			}
+			next := p.Add(d)
+			ni := g.index(next)
+			if closed[ni] {
+				continue
+			}
+			if c := costs[cur] + step; c < costs[ni] {
+				costs[ni] = c
+				parents[ni] = cur
+				heap.Push(open, openNode{index: ni, priority: c + conn.heuristic(next, goal)})
+			}
+		}
+	}
+	return nil, false
+}
+
+// trace follows parents back from end, returning the path to end.
+func (g *Grid) trace(parents []int, end int) []intgeom.Point2 {
+	var path []intgeom.Point2
+	for i := end; i != -1; i = parents[i] {
+		path = append(path, g.point(i))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+package path
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/collision/ray"
+)
+
+// GridFromTree creates a grid covering bounds with square cells of cellSize, in
+// which cells overlapping spaces of the tree are blocked. If labels are given,
+// only spaces with one of those labels block cells. Spaces only touching the edge
+// of a cell do not block it.
+func GridFromTree(tree *collision.Tree, bounds floatgeom.Rect2, cellSize float64, labels ...collision.Label) *Grid {
+	w := int(math.Ceil(bounds.W() / cellSize))
+	h := int(math.Ceil(bounds.H() / cellSize))
+	g := NewGrid(w, h)
+	g.Origin = bounds.Min
+	g.CellSize = cellSize
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			min := g.Origin.Add(floatgeom.Point2{float64(x) * cellSize, float64(y) * cellSize})
+			cell := collision.NewRect(min.X(), min.Y(), cellSize, cellSize)
+			for _, s := range tree.SearchIntersect(cell) {
+				if s.MatchesLabels(labels...) {
+					g.SetWalkable(intgeom.Point2{x, y}, false)
+					break
+				}
+			}
+		}
+	}
+	return g
+}
+
+// Smooth removes points from a path which can be skipped, keeping a point only if
+// the caster hits something on a ray from the previous kept point to the point
+// after it. The caster's tree, filters, and limits determine what blocks a ray.
+func Smooth(path []floatgeom.Point2, caster *ray.Caster) []floatgeom.Point2 {
+	if len(path) < 3 {
+		return append([]floatgeom.Point2{}, path...)
+	}
+	clear := func(a, b floatgeom.Point2) bool {
+		c := caster.Copy()
+		c.CastDistance = a.Distance(b)
+		return len(c.CastTo(a, b)) == 0
+	}
+	out := []floatgeom.Point2{path[0]}
+	anchor := path[0]
+	for i := 1; i < len(path)-1; i++ {
+		if !clear(anchor, path[i+1]) {
+			anchor = path[i]
+			out = append(out, anchor)
+		}
+	}
+	return append(out, path[len(path)-1])
+}
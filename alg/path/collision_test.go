@@ -0,0 +1,52 @@
+package path
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/collision/ray"
+)
+
+func TestGridFromTreeAndSmooth(t *testing.T) {
+	tree := collision.NewTree()
+	tree.Add(
+		collision.NewLabeledSpace(20, 0, 10, 30, 1),
+		collision.NewLabeledSpace(40, 40, 5, 5, 2),
+	)
+	bounds := floatgeom.NewRect2(0, 0, 60, 50)
+	g := GridFromTree(tree, bounds, 10, 1)
+	want := "" +
+		"..#...\n" +
+		"..#...\n" +
+		"..#...\n" +
+		"......\n" +
+		"......\n"
+	if got := gridString(g); got != want {
+		t.Fatalf("expected grid:\n%s\ngot:\n%s", want, got)
+	}
+	if g := GridFromTree(tree, bounds, 10); g.Walkable(intgeom.Point2{4, 4}) {
+		t.Fatal("unlabeled grid did not block cell of every space")
+	}
+
+	start, goal := g.Cell(floatgeom.Point2{5, 5}), g.Cell(floatgeom.Point2{55, 5})
+	cells, ok := AStar(g, start, goal, EightWay)
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	world := g.WorldPath(cells)
+	smooth := Smooth(world, ray.NewCaster(ray.Tree(tree), ray.AcceptLabels(1)))
+	if len(smooth) >= len(world) || len(smooth) < 3 {
+		t.Fatalf("expected smoothing to remove points but keep a turn, got %v from %v", smooth, world)
+	}
+	if smooth[0] != world[0] || smooth[len(smooth)-1] != world[len(world)-1] {
+		t.Fatalf("smoothing moved endpoints: %v", smooth)
+	}
+	for i := 1; i < len(smooth); i++ {
+		c := ray.NewCaster(ray.Tree(tree), ray.AcceptLabels(1), ray.Distance(smooth[i-1].Distance(smooth[i])))
+		if hits := c.CastTo(smooth[i-1], smooth[i]); len(hits) != 0 {
+			t.Fatalf("smoothed segment %v to %v passes through an obstacle", smooth[i-1], smooth[i])
+		}
+	}
+}
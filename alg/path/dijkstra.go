@@ -0,0 +1,151 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// A DistanceMap holds the cost of the cheapest path from every cell of a grid to
+// the nearest of a set of goals. Agents can find their way to the goals by moving
+// to whichever neighboring cell is closest.
+type DistanceMap struct {
+	Grid         *Grid
+	Connectivity Connectivity
+
+	costs []float64
+}
+
+// NewDistanceMap builds a distance map over a grid to the given goals. The map is
+// not updated if the grid changes.
+func NewDistanceMap(g *Grid, conn Connectivity, goals ...intgeom.Point2) *DistanceMap {
+	dm := &DistanceMap{
+		Grid:         g,
+		Connectivity: conn,
+		costs:        make([]float64, len(g.costs)),
+	}
+	for i := range dm.costs {
+		dm.costs[i] = math.Inf(1)
+	}
+	open := &openList{}
+	for _, goal := range goals {
+		if g.Walkable(goal) {
+			dm.costs[g.index(goal)] = 0
+			heap.Push(open, openNode{index: g.index(goal)})
+		}
+	}
+	moves := conn.moves()
+	for open.Len() != 0 {
+		n := heap.Pop(open).(openNode)
+		if n.priority > dm.costs[n.index] {
+			continue
+		}
+		p := g.point(n.index)
+		for _, d := range moves {
+			// paths are walked from next to p, so the cost is of entering p
+			next := p.Sub(d)
+			if !g.Walkable(next) {
+				continue
+			}
+			step, ok := g.canStep(next, d)
+			if !ok {
+				continue
+			}
+			ni := g.index(next)
+			if c := n.priority + step; c < dm.costs[ni] {
+				dm.costs[ni] = c
+				heap.Push(open, openNode{index: ni, priority: c})
+			}
+		}
+	}
+	return dm
+}
+
+// Distance returns the cost of the cheapest path from a cell to a goal, and
+// whether any goal can be reached from it.
+func (dm *DistanceMap) Distance(p intgeom.Point2) (float64, bool) {
+	if !dm.Grid.InBounds(p) {
+		return math.Inf(1), false
+	}
+	c := dm.costs[dm.Grid.index(p)]
+	return c, !math.IsInf(c, 1)
+}
+
+// Next returns the cell to move to from p to approach the nearest goal, and
+// whether there is one. Goals, and cells which can not reach a goal, have no
+// next cell.
+func (dm *DistanceMap) Next(p intgeom.Point2) (intgeom.Point2, bool) {
+	here, ok := dm.Distance(p)
+	if !ok || here == 0 {
+		return p, false
+	}
+	best, bestCost := p, here
+	for _, d := range dm.Connectivity.moves() {
+		step, ok := dm.Grid.canStep(p, d)
+		if !ok {
+			continue
+		}
+		c, _ := dm.Distance(p.Add(d))
+		if c+step <= here+1e-9 && c < bestCost {
+			best, bestCost = p.Add(d), c
+		}
+	}
+	return best, best != p
+}
+
+// Path returns the path of cells from p to the nearest goal, including both, and
+// whether a goal can be reached.
+func (dm *DistanceMap) Path(p intgeom.Point2) ([]intgeom.Point2, bool) {
+	if _, ok := dm.Distance(p); !ok {
+		return nil, false
+	}
+	path := []intgeom.Point2{p}
+	for {
+		next, ok := dm.Next(p)
+		if !ok {
+			return path, true
+		}
+		path = append(path, next)
+		p = next
+	}
+}
+
+// A FlowField holds, for every cell of a grid, the direction to move in to reach
+// the nearest goal of a distance map.
+type FlowField struct {
+	Grid *Grid
+
+	dirs []floatgeom.Point2
+}
+
+// FlowField computes the flow field of this distance map.
+func (dm *DistanceMap) FlowField() *FlowField {
+	ff := &FlowField{
+		Grid: dm.Grid,
+		dirs: make([]floatgeom.Point2, len(dm.costs)),
+	}
+	for i := range ff.dirs {
+		p := dm.Grid.point(i)
+		if next, ok := dm.Next(p); ok {
+			d := next.Sub(p)
+			ff.dirs[i] = floatgeom.Point2{float64(d.X()), float64(d.Y())}.Normalize()
+		}
+	}
+	return ff
+}
+
+// Direction returns the unit direction to move in from a cell, or (0,0) if the
+// cell is a goal, can not reach one, or is outside the grid.
+func (ff *FlowField) Direction(p intgeom.Point2) floatgeom.Point2 {
+	if !ff.Grid.InBounds(p) {
+		return floatgeom.Point2{}
+	}
+	return ff.dirs[ff.Grid.index(p)]
+}
+
+// WorldDirection returns the direction to move in from a world position.
+func (ff *FlowField) WorldDirection(world floatgeom.Point2) floatgeom.Point2 {
+	return ff.Direction(ff.Grid.Cell(world))
+}
@@ -0,0 +1,5 @@
+// Package path provides pathfinding over grids: A*, jump point search, and
+// Dijkstra maps with flow fields for many agents sharing goals. Grids can be
+// built from the spaces of a collision tree, and paths through the world can be
+// smoothed with ray casts.
+package path
@@ -0,0 +1,158 @@
+package path
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// Blocked is the cost of cells which can not be entered.
+const Blocked = -1
+
+// A Connectivity is the set of moves allowed from a cell.
+type Connectivity int
+
+const (
+	// FourWay allows moves up, down, left, and right.
+	FourWay Connectivity = iota
+	// EightWay also allows diagonal moves, so long as the move does not cut the
+	// corner of a blocked cell.
+	EightWay
+)
+
+var (
+	orthogonal = []intgeom.Point2{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	diagonal   = []intgeom.Point2{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}}
+)
+
+// A Grid is a rectangle of cells, each with a cost to enter. Moving orthogonally
+// into a cell costs the cell's cost, and moving diagonally costs that times the
+// square root of two. Costs below one may cause A* to return paths which are not
+// the cheapest.
+//
+// Grids map to the world with an Origin, the world position of cell (0,0)'s top
+// left corner, and a CellSize.
+type Grid struct {
+	Width, Height int
+
+	Origin   floatgeom.Point2
+	CellSize float64
+
+	costs []float64
+}
+
+// NewGrid creates a grid of cells with a cost of one, with a cell size of one
+// at the world's origin.
+func NewGrid(w, h int) *Grid {
+	g := &Grid{
+		Width:    w,
+		Height:   h,
+		CellSize: 1,
+		costs:    make([]float64, w*h),
+	}
+	for i := range g.costs {
+		g.costs[i] = 1
+	}
+	return g
+}
+
+// InBounds returns whether p is a cell of the grid.
+func (g *Grid) InBounds(p intgeom.Point2) bool {
+	return p.X() >= 0 && p.Y() >= 0 && p.X() < g.Width && p.Y() < g.Height
+}
+
+func (g *Grid) index(p intgeom.Point2) int {
+	return p.Y()*g.Width + p.X()
+}
+
+func (g *Grid) point(i int) intgeom.Point2 {
+	return intgeom.Point2{i % g.Width, i / g.Width}
+}
+
+// Cost returns the cost of entering a cell. Cells outside the grid are Blocked.
+func (g *Grid) Cost(p intgeom.Point2) float64 {
+	if !g.InBounds(p) {
+		return Blocked
+	}
+	return g.costs[g.index(p)]
+}
+
+// SetCost sets the cost of entering a cell. Costs below zero block the cell.
+func (g *Grid) SetCost(p intgeom.Point2, cost float64) {
+	if g.InBounds(p) {
+		if cost < 0 {
+			cost = Blocked
+		}
+		g.costs[g.index(p)] = cost
+	}
+}
+
+// Walkable returns whether a cell can be entered.
+func (g *Grid) Walkable(p intgeom.Point2) bool {
+	return g.Cost(p) >= 0
+}
+
+// SetWalkable sets a cell to be Blocked, or to cost one.
+func (g *Grid) SetWalkable(p intgeom.Point2, walkable bool) {
+	if walkable {
+		g.SetCost(p, 1)
+	} else {
+		g.SetCost(p, Blocked)
+	}
+}
+
+// Cell returns the cell containing a world position.
+func (g *Grid) Cell(world floatgeom.Point2) intgeom.Point2 {
+	local := world.Sub(g.Origin).DivConst(g.CellSize)
+	return intgeom.Point2{int(math.Floor(local.X())), int(math.Floor(local.Y()))}
+}
+
+// Center returns the world position of the center of a cell.
+func (g *Grid) Center(p intgeom.Point2) floatgeom.Point2 {
+	return g.Origin.Add(floatgeom.Point2{
+		(float64(p.X()) + .5) * g.CellSize,
+		(float64(p.Y()) + .5) * g.CellSize,
+	})
+}
+
+// WorldPath converts a path of cells to the world positions of their centers.
+func (g *Grid) WorldPath(cells []intgeom.Point2) []floatgeom.Point2 {
+	out := make([]floatgeom.Point2, len(cells))
+	for i, c := range cells {
+		out[i] = g.Center(c)
+	}
+	return out
+}
+
+// canStep returns whether a move of d from p is allowed, and its cost.
+func (g *Grid) canStep(p, d intgeom.Point2) (float64, bool) {
+	cost := g.Cost(p.Add(d))
+	if cost < 0 {
+		return 0, false
+	}
+	if d.X() != 0 && d.Y() != 0 {
+		if !g.Walkable(intgeom.Point2{p.X() + d.X(), p.Y()}) || !g.Walkable(intgeom.Point2{p.X(), p.Y() + d.Y()}) {
+			return 0, false
+		}
+		return cost * math.Sqrt2, true
+	}
+	return cost, true
+}
+
+func (c Connectivity) moves() []intgeom.Point2 {
+	if c == EightWay {
+		return append(append([]intgeom.Point2{}, orthogonal...), diagonal...)
+	}
+	return orthogonal
+}
+
+// heuristic estimates the cost between two cells assuming each cell costs one.
+func (c Connectivity) heuristic(a, b intgeom.Point2) float64 {
+	dx := math.Abs(float64(a.X() - b.X()))
+	dy := math.Abs(float64(a.Y() - b.Y()))
+	if c == EightWay {
+		return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+	}
+	return dx + dy
+}
@@ -0,0 +1,191 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// JPS returns a shortest path of cells from start to goal, including both, and
+// whether one exists, using jump point search. JPS treats every walkable cell as
+// costing one and moves eight ways without cutting corners; on such grids it
+// finds paths as short as AStar's while exploring far fewer cells.
+func JPS(g *Grid, start, goal intgeom.Point2) ([]intgeom.Point2, bool) {
+	if !g.Walkable(start) || !g.Walkable(goal) {
+		return nil, false
+	}
+	costs := make([]float64, len(g.costs))
+	for i := range costs {
+		costs[i] = math.Inf(1)
+	}
+	parents := make([]int, len(g.costs))
+	closed := make([]bool, len(g.costs))
+	startIdx, goalIdx := g.index(start), g.index(goal)
+	costs[startIdx] = 0
+	parents[startIdx] = -1
+	open := &openList{{index: startIdx, priority: EightWay.heuristic(start, goal)}}
+	for open.Len() != 0 {
+		cur := heap.Pop(open).(openNode).index
+		if closed[cur] {
+			continue
+		}
+		if cur == goalIdx {
+			return expand(g.trace(parents, cur)), true
+		}
+		closed[cur] = true
+		p := g.point(cur)
+		var parent *intgeom.Point2
+		if parents[cur] != -1 {
+			pp := g.point(parents[cur])
+			parent = &pp
+		}
+		for _, n := range g.jpsNeighbors(p, parent) {
+			jp, ok := g.jump(n, p, goal)
+			if !ok {
+				continue
+			}
+			ji := g.index(jp)
+			if closed[ji] {
+				continue
+			}
+			if c := costs[cur] + EightWay.heuristic(p, jp); c < costs[ji] {
+				costs[ji] = c
+				parents[ji] = cur
+				heap.Push(open, openNode{index: ji, priority: c + EightWay.heuristic(jp, goal)})
+			}
+		}
+	}
+	return nil, false
+}
+
+// jpsNeighbors returns the neighbors of p worth searching when arriving from
+// parent, or every neighbor if parent is nil.
+func (g *Grid) jpsNeighbors(p intgeom.Point2, parent *intgeom.Point2) []intgeom.Point2 {
+	var out []intgeom.Point2
+	if parent == nil {
+		for _, d := range EightWay.moves() {
+			if _, ok := g.canStep(p, d); ok {
+				out = append(out, p.Add(d))
+			}
+		}
+		return out
+	}
+	walkable := func(dx, dy int) bool {
+		return g.Walkable(intgeom.Point2{p.X() + dx, p.Y() + dy})
+	}
+	add := func(dx, dy int) {
+		out = append(out, intgeom.Point2{p.X() + dx, p.Y() + dy})
+	}
+	dx, dy := sign(p.X()-parent.X()), sign(p.Y()-parent.Y())
+	switch {
+	case dx != 0 && dy != 0:
+		if walkable(0, dy) {
+			add(0, dy)
+		}
+		if walkable(dx, 0) {
+			add(dx, 0)
+		}
+		if walkable(0, dy) && walkable(dx, 0) && walkable(dx, dy) {
+			add(dx, dy)
+		}
+	case dx != 0:
+		next, up, down := walkable(dx, 0), walkable(0, -1), walkable(0, 1)
+		if next {
+			add(dx, 0)
+			if up && walkable(dx, -1) {
+				add(dx, -1)
+			}
+			if down && walkable(dx, 1) {
+				add(dx, 1)
+			}
+		}
+		if up {
+			add(0, -1)
+		}
+		if down {
+			add(0, 1)
+		}
+	default:
+		next, left, right := walkable(0, dy), walkable(-1, 0), walkable(1, 0)
+		if next {
+			add(0, dy)
+			if left && walkable(-1, dy) {
+				add(-1, dy)
+			}
+			if right && walkable(1, dy) {
+				add(1, dy)
+			}
+		}
+		if left {
+			add(-1, 0)
+		}
+		if right {
+			add(1, 0)
+		}
+	}
+	return out
+}
+
+// jump moves from p in the direction it was reached from parent until it finds
+// a jump point, returning false if it is blocked first.
+func (g *Grid) jump(p, parent, goal intgeom.Point2) (intgeom.Point2, bool) {
+	dx, dy := p.X()-parent.X(), p.Y()-parent.Y()
+	walkable := func(x, y int) bool {
+		return g.Walkable(intgeom.Point2{x, y})
+	}
+	for {
+		x, y := p.X(), p.Y()
+		if !walkable(x, y) {
+			return p, false
+		}
+		if p == goal {
+			return p, true
+		}
+		if dx != 0 && dy != 0 {
+			if _, ok := g.jump(intgeom.Point2{x + dx, y}, p, goal); ok {
+				return p, true
+			}
+			if _, ok := g.jump(intgeom.Point2{x, y + dy}, p, goal); ok {
+				return p, true
+			}
+		} else if dx != 0 {
+			if (walkable(x, y-1) && !walkable(x-dx, y-1)) || (walkable(x, y+1) && !walkable(x-dx, y+1)) {
+				return p, true
+			}
+		} else if (walkable(x-1, y) && !walkable(x-1, y-dy)) || (walkable(x+1, y) && !walkable(x+1, y-dy)) {
+			return p, true
+		}
+		if !walkable(x+dx, y) || !walkable(x, y+dy) {
+			return p, false
+		}
+		p = intgeom.Point2{x + dx, y + dy}
+	}
+}
+
+// expand fills in the cells between consecutive jump points.
+func expand(jumps []intgeom.Point2) []intgeom.Point2 {
+	if len(jumps) == 0 {
+		return jumps
+	}
+	out := []intgeom.Point2{jumps[0]}
+	for i := 1; i < len(jumps); i++ {
+		p, to := jumps[i-1], jumps[i]
+		d := intgeom.Point2{sign(to.X() - p.X()), sign(to.Y() - p.Y())}
+		for p != to {
+			p = p.Add(d)
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func sign(i int) int {
+	switch {
+	case i > 0:
+		return 1
+	case i < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,9 @@
+// Package navmesh builds navigation meshes from polygons and finds paths across
+// them.
+//
+// A mesh covers the area inside a boundary polygon and outside any obstacles with
+// triangles, from a constrained Delaunay triangulation (see
+// floatgeom.ConstrainedDelaunay) whose constraints are the edges of that area.
+// Paths are found with A* across adjacent triangles and pulled tight through the
+// shared edges of those triangles with the funnel algorithm.
+package navmesh
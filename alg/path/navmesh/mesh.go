@@ -0,0 +1,144 @@
+package navmesh
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// A Mesh is a set of triangles covering walkable space.
+type Mesh struct {
+	Vertices []floatgeom.Point2
+	// Triangles holds indices into Vertices. Edge i of a triangle runs from its
+	// vertex i to its vertex (i+1)%3.
+	Triangles [][3]int
+
+	// neighbors holds, for each triangle edge, the index of the triangle across
+	// it or -1.
+	neighbors [][3]int
+}
+
+// New builds a mesh covering the area inside boundary and outside each obstacle.
+// If radius is positive, the boundary is shrunk and the obstacles are grown by it
+// so paths across the mesh keep agents of that radius clear of walls. Obstacles
+// may overlap one another and the boundary, and passages narrower than twice
+// radius are closed.
+func New(boundary floatgeom.Polygon2, obstacles []floatgeom.Polygon2, radius float64) (*Mesh, error) {
+	if len(boundary.Points) < 3 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "boundary"}
+	}
+	var blocked []floatgeom.Region
+	for i, o := range obstacles {
+		if len(o.Points) < 3 {
+			return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "obstacles[" + strconv.Itoa(i) + "]"}
+		}
+		blocked = floatgeom.Union(blocked, o.Offset(radius, floatgeom.MiterJoin))
+	}
+	walkable := floatgeom.Difference(boundary.Offset(-radius, floatgeom.MiterJoin), blocked)
+	if len(walkable) == 0 {
+		return nil, oakerr.InvalidInput{InputName: "boundary"}
+	}
+	var shapes [][]floatgeom.Point2
+	for _, r := range walkable {
+		shapes = append(shapes, r.Outer.Points)
+		for _, h := range r.Holes {
+			shapes = append(shapes, h.Points)
+		}
+	}
+	b := boundary.Bounding
+	t := &triangulator{eps: math.Max(b.W(), b.H()) * 1e-9}
+	var segs []segment
+	for _, shape := range shapes {
+		first := len(segs)
+		prev := -1
+		for _, p := range shape {
+			i := t.addPoint(p)
+			if prev != -1 {
+				segs = append(segs, segment{prev, i})
+			}
+			prev = i
+		}
+		segs = append(segs, segment{prev, segs[first][0]})
+	}
+	tris := floatgeom.ConstrainedDelaunay(t.pts, t.split(segs))
+
+	// the triangulation covers the convex hull of the walkable area, including
+	// obstacles and concavities
+	m := &Mesh{Vertices: t.pts}
+	for _, tri := range tris {
+		c := t.pts[tri[0]].Add(t.pts[tri[1]], t.pts[tri[2]]).DivConst(3)
+		for _, r := range walkable {
+			if r.Contains(c.X(), c.Y()) {
+				m.Triangles = append(m.Triangles, tri)
+				break
+			}
+		}
+	}
+	if len(m.Triangles) == 0 {
+		return nil, oakerr.InvalidInput{InputName: "boundary"}
+	}
+	m.link()
+	return m, nil
+}
+
+// FromSpaces builds a mesh covering the area inside boundary and outside each of
+// the given spaces. See New.
+func FromSpaces(boundary floatgeom.Polygon2, spaces []*collision.Space, radius float64) (*Mesh, error) {
+	obstacles := make([]floatgeom.Polygon2, len(spaces))
+	for i, s := range spaces {
+		x, y, w, h := s.X(), s.Y(), s.W(), s.H()
+		obstacles[i] = floatgeom.NewPolygon2(
+			floatgeom.Point2{x, y},
+			floatgeom.Point2{x + w, y},
+			floatgeom.Point2{x + w, y + h},
+			floatgeom.Point2{x, y + h},
+		)
+	}
+	return New(boundary, obstacles, radius)
+}
+
+func (m *Mesh) link() {
+	type edge struct{ tri, side int }
+	edges := make(map[segment]edge)
+	m.neighbors = make([][3]int, len(m.Triangles))
+	for i, tri := range m.Triangles {
+		for k := 0; k < 3; k++ {
+			m.neighbors[i][k] = -1
+			u, v := tri[k], tri[(k+1)%3]
+			if e, ok := edges[segment{v, u}]; ok {
+				m.neighbors[i][k] = e.tri
+				m.neighbors[e.tri][e.side] = i
+				continue
+			}
+			edges[segment{u, v}] = edge{i, k}
+		}
+	}
+}
+
+// Triangle returns the points of the i'th triangle.
+func (m *Mesh) Triangle(i int) [3]floatgeom.Point2 {
+	tri := m.Triangles[i]
+	return [3]floatgeom.Point2{m.Vertices[tri[0]], m.Vertices[tri[1]], m.Vertices[tri[2]]}
+}
+
+// Neighbors returns the indices of the triangles sharing each edge of the i'th
+// triangle, or -1 for edges on the mesh's border.
+func (m *Mesh) Neighbors(i int) [3]int {
+	return m.neighbors[i]
+}
+
+// Locate returns the index of the triangle containing p.
+func (m *Mesh) Locate(p floatgeom.Point2) (int, bool) {
+	for i := range m.Triangles {
+		t := m.Triangle(i)
+		if t[1].Sub(t[0]).Cross(p.Sub(t[0])) >= 0 &&
+			t[2].Sub(t[1]).Cross(p.Sub(t[1])) >= 0 &&
+			t[0].Sub(t[2]).Cross(p.Sub(t[2])) >= 0 {
+			return i, true
+		}
+	}
+	return -1, false
+}
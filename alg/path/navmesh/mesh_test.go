@@ -0,0 +1,232 @@
+package navmesh
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+func rect(x, y, w, h float64) floatgeom.Polygon2 {
+	return floatgeom.NewPolygon2(
+		floatgeom.Point2{x, y},
+		floatgeom.Point2{x + w, y},
+		floatgeom.Point2{x + w, y + h},
+		floatgeom.Point2{x, y + h},
+	)
+}
+
+func meshArea(m *Mesh) float64 {
+	area := 0.0
+	for i := range m.Triangles {
+		t := m.Triangle(i)
+		area += t[1].Sub(t[0]).Cross(t[2].Sub(t[0])) / 2
+	}
+	return area
+}
+
+func pathLength(path []floatgeom.Point2) float64 {
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		length += path[i-1].Distance(path[i])
+	}
+	return length
+}
+
+func TestNewCoversWalkableArea(t *testing.T) {
+	m, err := New(rect(0, 0, 100, 100), []floatgeom.Polygon2{
+		rect(20, 20, 20, 20),
+		// overlapping obstacles
+		rect(60, 60, 20, 20),
+		rect(70, 50, 20, 20),
+		// an obstacle crossing the boundary
+		rect(-10, 90, 30, 30),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 100*100 - 400 - (400 + 400 - 100) - 200
+	if got := meshArea(m); math.Abs(got-float64(want)) > 1e-6 {
+		t.Fatalf("expected area %v, got %v", want, got)
+	}
+	for i := range m.Triangles {
+		tri := m.Triangle(i)
+		if tri[1].Sub(tri[0]).Cross(tri[2].Sub(tri[0])) <= 0 {
+			t.Fatalf("triangle %v is not wound positively", tri)
+		}
+	}
+}
+
+func TestNewConcave(t *testing.T) {
+	// a star's edges cut across the triangles of its unconstrained triangulation
+	var pts []floatgeom.Point2
+	for i := 0; i < 14; i++ {
+		r := 50.0
+		if i%2 == 1 {
+			r = 20
+		}
+		pts = append(pts, floatgeom.RadianPoint(math.Pi*float64(i)/7).MulConst(r).Add(floatgeom.Point2{50, 50}))
+	}
+	star := floatgeom.NewPolygon2(pts[0], pts[1], pts[2], pts[3:]...)
+	m, err := New(star, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := meshArea(m), star.Area(); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("expected area %v, got %v", want, got)
+	}
+	for i := range m.Triangles {
+		tri := m.Triangle(i)
+		c := tri[0].Add(tri[1], tri[2]).DivConst(3)
+		if !star.Contains(c.X(), c.Y()) {
+			t.Fatalf("triangle %v is outside the boundary", tri)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New(floatgeom.Polygon2{}, nil, 0); err == nil {
+		t.Fatal("expected error for empty boundary")
+	}
+	if _, err := New(rect(0, 0, 10, 10), []floatgeom.Polygon2{rect(-5, -5, 20, 20)}, 0); err == nil {
+		t.Fatal("expected error for fully blocked boundary")
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	// a U shaped corridor: the path must go down, across and back up
+	m, err := New(rect(0, 0, 100, 100), []floatgeom.Polygon2{rect(40, 0, 20, 80)}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := floatgeom.Point2{10, 10}, floatgeom.Point2{90, 10}
+	path, ok := m.FindPath(from, to)
+	if !ok {
+		t.Fatal("expected path")
+	}
+	want := []floatgeom.Point2{from, {40, 80}, {60, 80}, to}
+	if len(path) != len(want) {
+		t.Fatalf("expected path %v, got %v", want, path)
+	}
+	for i := range want {
+		if path[i].Distance(want[i]) > 1e-6 {
+			t.Fatalf("expected path %v, got %v", want, path)
+		}
+	}
+	if path, ok := m.FindPath(from, floatgeom.Point2{30, 50}); !ok || len(path) != 2 {
+		t.Fatalf("expected straight path in open space, got %v", path)
+	}
+	if _, ok := m.FindPath(from, floatgeom.Point2{50, 50}); ok {
+		t.Fatal("expected no path into an obstacle")
+	}
+	if _, ok := m.FindPath(from, floatgeom.Point2{150, 50}); ok {
+		t.Fatal("expected no path off the mesh")
+	}
+}
+
+func TestFindPathRadius(t *testing.T) {
+	m, err := New(rect(0, 0, 100, 100), []floatgeom.Polygon2{rect(40, 0, 20, 80)}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, ok := m.FindPath(floatgeom.Point2{10, 10}, floatgeom.Point2{90, 10})
+	if !ok {
+		t.Fatal("expected path")
+	}
+	for _, p := range path[1 : len(path)-1] {
+		if p.Y() < 85-1e-6 {
+			t.Fatalf("expected path corners to keep clear of the obstacle, got %v", path)
+		}
+	}
+	if _, ok := m.FindPath(floatgeom.Point2{2, 10}, floatgeom.Point2{90, 10}); ok {
+		t.Fatal("expected no path from within radius of the boundary")
+	}
+}
+
+func TestNewNarrowPassage(t *testing.T) {
+	// a boundary of two rooms joined by a passage 6 wide
+	boundary := floatgeom.NewPolygon2(
+		floatgeom.Point2{0, 0}, floatgeom.Point2{40, 0}, floatgeom.Point2{40, 17}, floatgeom.Point2{60, 17},
+		floatgeom.Point2{60, 0}, floatgeom.Point2{100, 0}, floatgeom.Point2{100, 40}, floatgeom.Point2{60, 40},
+		floatgeom.Point2{60, 23}, floatgeom.Point2{40, 23}, floatgeom.Point2{40, 40}, floatgeom.Point2{0, 40},
+	)
+	tests := []struct {
+		radius   float64
+		connects bool
+	}{
+		{2, true},
+		{4, false},
+	}
+	for _, tc := range tests {
+		m, err := New(boundary, nil, tc.radius)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m.FindPath(floatgeom.Point2{20, 20}, floatgeom.Point2{80, 20}); ok != tc.connects {
+			t.Fatalf("radius %v: expected path %v", tc.radius, tc.connects)
+		}
+		if !onMesh(m, floatgeom.Point2{20, 20}) || !onMesh(m, floatgeom.Point2{80, 20}) {
+			t.Fatalf("radius %v: expected both rooms to be walkable", tc.radius)
+		}
+	}
+}
+
+func TestFindPathRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	var obstacles []floatgeom.Polygon2
+	for i := 0; i < 12; i++ {
+		obstacles = append(obstacles, rect(rng.Float64()*180, rng.Float64()*180, 5+rng.Float64()*20, 5+rng.Float64()*20))
+	}
+	m, err := New(rect(0, 0, 200, 200), obstacles, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for i := 0; i < 50; i++ {
+		from := floatgeom.Point2{rng.Float64() * 200, rng.Float64() * 200}
+		to := floatgeom.Point2{rng.Float64() * 200, rng.Float64() * 200}
+		path, ok := m.FindPath(from, to)
+		if !ok {
+			continue
+		}
+		found++
+		if pathLength(path) < from.Distance(to)-1e-6 {
+			t.Fatalf("path %v shorter than a straight line", path)
+		}
+		// every point along the path should be on the mesh
+		for j := 1; j < len(path); j++ {
+			for k := 0.0; k <= 1; k += .05 {
+				p := path[j-1].Add(path[j].Sub(path[j-1]).MulConst(k))
+				if !onMesh(m, p) {
+					t.Fatalf("path %v leaves the mesh at %v", path, p)
+				}
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatal("expected some paths to be found")
+	}
+}
+
+func onMesh(m *Mesh, p floatgeom.Point2) bool {
+	for _, d := range []floatgeom.Point2{{0, 0}, {1e-6, 0}, {-1e-6, 0}, {0, 1e-6}, {0, -1e-6}} {
+		if _, ok := m.Locate(p.Add(d)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFromSpaces(t *testing.T) {
+	m, err := FromSpaces(rect(0, 0, 100, 100), []*collision.Space{
+		collision.NewUnassignedSpace(40, 0, 20, 80),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := meshArea(m); math.Abs(got-(10000-1600)) > 1e-6 {
+		t.Fatalf("expected area %v, got %v", 10000-1600, got)
+	}
+}
@@ -0,0 +1,162 @@
+package navmesh
+
+import (
+	"container/heap"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+type node struct {
+	tri   int
+	f     float64
+	index int
+}
+
+type openList []*node
+
+func (o openList) Len() int           { return len(o) }
+func (o openList) Less(i, j int) bool { return o[i].f < o[j].f }
+func (o openList) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].index = i
+	o[j].index = j
+}
+func (o *openList) Push(x interface{}) {
+	n := x.(*node)
+	n.index = len(*o)
+	*o = append(*o, n)
+}
+func (o *openList) Pop() interface{} {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return n
+}
+
+// Corridor returns the triangles crossed walking from the triangle containing
+// from to the triangle containing to, or false if either point is off the mesh or
+// no route connects them. Routes are found with A* between the midpoints of the
+// edges crossed.
+func (m *Mesh) Corridor(from, to floatgeom.Point2) ([]int, bool) {
+	start, ok := m.Locate(from)
+	if !ok {
+		return nil, false
+	}
+	goal, ok := m.Locate(to)
+	if !ok {
+		return nil, false
+	}
+	pos := map[int]floatgeom.Point2{start: from}
+	cost := map[int]float64{start: 0}
+	parent := map[int]int{start: -1}
+	nodes := map[int]*node{start: {tri: start, f: from.Distance(to)}}
+	open := &openList{nodes[start]}
+	closed := make(map[int]bool)
+	for open.Len() != 0 {
+		cur := heap.Pop(open).(*node)
+		if cur.tri == goal {
+			var tris []int
+			for t := goal; t != -1; t = parent[t] {
+				tris = append(tris, t)
+			}
+			for i, j := 0, len(tris)-1; i < j; i, j = i+1, j-1 {
+				tris[i], tris[j] = tris[j], tris[i]
+			}
+			return tris, true
+		}
+		closed[cur.tri] = true
+		for side, next := range m.neighbors[cur.tri] {
+			if next == -1 || closed[next] {
+				continue
+			}
+			tri := m.Triangles[cur.tri]
+			mid := m.Vertices[tri[side]].Add(m.Vertices[tri[(side+1)%3]]).DivConst(2)
+			if next == goal {
+				mid = to
+			}
+			g := cost[cur.tri] + pos[cur.tri].Distance(mid)
+			if c, ok := cost[next]; ok && c <= g {
+				continue
+			}
+			cost[next] = g
+			pos[next] = mid
+			parent[next] = cur.tri
+			if n, ok := nodes[next]; ok {
+				n.f = g + mid.Distance(to)
+				heap.Fix(open, n.index)
+				continue
+			}
+			nodes[next] = &node{tri: next, f: g + mid.Distance(to)}
+			heap.Push(open, nodes[next])
+		}
+	}
+	return nil, false
+}
+
+// FindPath returns a path from one point to another across the mesh, including
+// both points, or false if no path exists. The path is the shortest through the
+// corridor of triangles found by Corridor.
+func (m *Mesh) FindPath(from, to floatgeom.Point2) ([]floatgeom.Point2, bool) {
+	tris, ok := m.Corridor(from, to)
+	if !ok {
+		return nil, false
+	}
+	portals := make([][2]floatgeom.Point2, 0, len(tris)+1)
+	portals = append(portals, [2]floatgeom.Point2{from, from})
+	for i := 0; i < len(tris)-1; i++ {
+		for side, next := range m.neighbors[tris[i]] {
+			if next != tris[i+1] {
+				continue
+			}
+			tri := m.Triangles[tris[i]]
+			// each triangle's third point is left of its edges, so
+			// walking out of it across an edge, the edge's end is on the left
+			portals = append(portals, [2]floatgeom.Point2{
+				m.Vertices[tri[(side+1)%3]], m.Vertices[tri[side]],
+			})
+			break
+		}
+	}
+	portals = append(portals, [2]floatgeom.Point2{to, to})
+	return funnel(portals), true
+}
+
+// funnel pulls a path taut through a series of left and right portal points.
+func funnel(portals [][2]floatgeom.Point2) []floatgeom.Point2 {
+	apex := portals[0][0]
+	left, right := apex, apex
+	apexIndex, leftIndex, rightIndex := 0, 0, 0
+	path := []floatgeom.Point2{apex}
+	for i := 1; i < len(portals); i++ {
+		l, r := portals[i][0], portals[i][1]
+		if right.Sub(apex).Cross(r.Sub(apex)) >= 0 {
+			if apex == right || left.Sub(apex).Cross(r.Sub(apex)) < 0 {
+				right, rightIndex = r, i
+			} else {
+				path = append(path, left)
+				apex, apexIndex = left, leftIndex
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+		if left.Sub(apex).Cross(l.Sub(apex)) <= 0 {
+			if apex == left || right.Sub(apex).Cross(l.Sub(apex)) > 0 {
+				left, leftIndex = l, i
+			} else {
+				path = append(path, right)
+				apex, apexIndex = right, rightIndex
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+	end := portals[len(portals)-1][0]
+	if path[len(path)-1] != end {
+		path = append(path, end)
+	}
+	return path
+}
@@ -0,0 +1,74 @@
+package navmesh
+
+import (
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+type segment [2]int
+
+// triangulator collects the points and edges of polygons to be triangulated.
+type triangulator struct {
+	pts []floatgeom.Point2
+	eps float64
+}
+
+// addPoint adds a point, returning the index of an existing point within eps of
+// it if there is one.
+func (t *triangulator) addPoint(p floatgeom.Point2) int {
+	for i, q := range t.pts {
+		if math.Abs(p.X()-q.X()) <= t.eps && math.Abs(p.Y()-q.Y()) <= t.eps {
+			return i
+		}
+	}
+	t.pts = append(t.pts, p)
+	return len(t.pts) - 1
+}
+
+// split divides segments where points lie on them, so that segments only meet at
+// their endpoints. Segments must not otherwise cross, as the edges of the
+// polygons produced by floatgeom's boolean operations do not.
+func (t *triangulator) split(segs []segment) [][2]int {
+	out := make([][2]int, 0, len(segs))
+	seen := make(map[segment]bool)
+	for _, s := range segs {
+		a, b := t.pts[s[0]], t.pts[s[1]]
+		dir := b.Sub(a)
+		length := dir.Magnitude()
+		if length <= t.eps {
+			continue
+		}
+		type stop struct {
+			t float64
+			i int
+		}
+		stops := []stop{{0, s[0]}, {1, s[1]}}
+		for i, p := range t.pts {
+			if i == s[0] || i == s[1] {
+				continue
+			}
+			along := p.Sub(a).Dot(dir) / (length * length)
+			if along <= 0 || along >= 1 {
+				continue
+			}
+			if math.Abs(dir.Cross(p.Sub(a)))/length <= t.eps {
+				stops = append(stops, stop{along, i})
+			}
+		}
+		sort.Slice(stops, func(i, j int) bool { return stops[i].t < stops[j].t })
+		for k := 1; k < len(stops); k++ {
+			u, v := stops[k-1].i, stops[k].i
+			if u > v {
+				u, v = v, u
+			}
+			if u == v || seen[segment{u, v}] {
+				continue
+			}
+			seen[segment{u, v}] = true
+			out = append(out, [2]int{u, v})
+		}
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package path
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// parseGrid builds a grid from rows of '.' for walkable cells, '#' for blocked
+// cells, and digits for cells of that cost.
+func parseGrid(rows ...string) *Grid {
+	g := NewGrid(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, c := range row {
+			p := intgeom.Point2{x, y}
+			switch {
+			case c == '#':
+				g.SetWalkable(p, false)
+			case c >= '0' && c <= '9':
+				g.SetCost(p, float64(c-'0'))
+			}
+		}
+	}
+	return g
+}
+
+func pathCost(g *Grid, path []intgeom.Point2) float64 {
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		step, ok := g.canStep(path[i-1], path[i].Sub(path[i-1]))
+		if !ok {
+			return math.Inf(1)
+		}
+		total += step
+	}
+	return total
+}
+
+func validPath(t *testing.T, g *Grid, path []intgeom.Point2, start, goal intgeom.Point2) {
+	t.Helper()
+	if len(path) == 0 || path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path %v does not run from %v to %v", path, start, goal)
+	}
+	if math.IsInf(pathCost(g, path), 1) {
+		t.Fatalf("path %v takes an illegal step", path)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	g := parseGrid(
+		"......",
+		".####.",
+		"....#.",
+		"###.#.",
+		"......",
+	)
+	start, goal := intgeom.Point2{0, 2}, intgeom.Point2{5, 4}
+	path, ok := AStar(g, start, goal, FourWay)
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	validPath(t, g, path, start, goal)
+	if len(path) != 8 {
+		t.Fatalf("expected four way path of 8 cells, got %v", path)
+	}
+	path, ok = AStar(g, start, goal, EightWay)
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	validPath(t, g, path, start, goal)
+	// every diagonal shortcut here would cut a corner
+	if c := pathCost(g, path); c != 7 {
+		t.Fatalf("expected eight way path cost of 7, got %v for %v", c, path)
+	}
+	open := NewGrid(5, 5)
+	path, _ = AStar(open, intgeom.Point2{0, 0}, intgeom.Point2{4, 4}, EightWay)
+	if c := pathCost(open, path); math.Abs(c-4*math.Sqrt2) > 1e-9 {
+		t.Fatalf("expected diagonal path cost of 4√2, got %v for %v", c, path)
+	}
+	if _, ok := AStar(g, start, intgeom.Point2{1, 1}, FourWay); ok {
+		t.Fatal("found path to a blocked cell")
+	}
+	if _, ok := AStar(parseGrid(".#", "#."), intgeom.Point2{0, 0}, intgeom.Point2{1, 1}, EightWay); ok {
+		t.Fatal("found path through a diagonal corner")
+	}
+}
+
+func TestAStarWeights(t *testing.T) {
+	g := parseGrid(
+		".....",
+		".999.",
+		".....",
+	)
+	start, goal := intgeom.Point2{0, 1}, intgeom.Point2{4, 1}
+	path, _ := AStar(g, start, goal, FourWay)
+	validPath(t, g, path, start, goal)
+	if c := pathCost(g, path); c != 6 {
+		t.Fatalf("expected path around expensive cells costing 6, got %v for %v", c, path)
+	}
+}
+
+func TestJPSMatchesAStar(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 50; trial++ {
+		g := NewGrid(30, 20)
+		for i := 0; i < 180; i++ {
+			g.SetWalkable(intgeom.Point2{rng.Intn(30), rng.Intn(20)}, false)
+		}
+		start, goal := intgeom.Point2{rng.Intn(30), rng.Intn(20)}, intgeom.Point2{rng.Intn(30), rng.Intn(20)}
+		g.SetWalkable(start, true)
+		g.SetWalkable(goal, true)
+		want, okA := AStar(g, start, goal, EightWay)
+		got, okJ := JPS(g, start, goal)
+		if okA != okJ {
+			t.Fatalf("trial %d: astar found path %v, jps %v", trial, okA, okJ)
+		}
+		if !okA {
+			continue
+		}
+		validPath(t, g, got, start, goal)
+		if a, j := pathCost(g, want), pathCost(g, got); math.Abs(a-j) > 1e-9 {
+			t.Fatalf("trial %d: jps path cost %v, astar %v", trial, j, a)
+		}
+	}
+}
+
+func TestDistanceMap(t *testing.T) {
+	g := parseGrid(
+		"......",
+		".####.",
+		"......",
+		"#####.",
+		"#.....",
+	)
+	goals := []intgeom.Point2{{0, 0}, {1, 4}}
+	dm := NewDistanceMap(g, FourWay, goals...)
+	if d, ok := dm.Distance(intgeom.Point2{4, 4}); !ok || d != 3 {
+		t.Fatalf("expected distance 3 to the nearer goal, got %v", d)
+	}
+	if d, _ := dm.Distance(intgeom.Point2{0, 2}); d != 2 {
+		t.Fatalf("expected distance 2, got %v", d)
+	}
+	if _, ok := dm.Distance(intgeom.Point2{0, 3}); ok {
+		t.Fatal("blocked cell reported as reaching a goal")
+	}
+	path, ok := dm.Path(intgeom.Point2{5, 2})
+	if !ok {
+		t.Fatal("expected a path")
+	}
+	if end := path[len(path)-1]; end != goals[1] || len(path) != 7 {
+		t.Fatalf("expected path of 7 cells to %v, got %v", goals[1], path)
+	}
+	ff := dm.FlowField()
+	if d := ff.Direction(intgeom.Point2{2, 4}); d.X() != -1 || d.Y() != 0 {
+		t.Fatalf("expected flow left toward goal, got %v", d)
+	}
+	if d := ff.Direction(goals[0]); d.X() != 0 || d.Y() != 0 {
+		t.Fatalf("expected no flow at a goal, got %v", d)
+	}
+	g.Origin[0] = 100
+	g.CellSize = 10
+	if d := ff.WorldDirection(g.Center(intgeom.Point2{2, 4})); d.X() != -1 {
+		t.Fatalf("expected flow left from world position, got %v", d)
+	}
+}
+
+func gridString(g *Grid) string {
+	sb := strings.Builder{}
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if g.Walkable(intgeom.Point2{x, y}) {
+				sb.WriteByte('.')
+			} else {
+				sb.WriteByte('#')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,98 @@
+package procgen
+
+import (
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// BSP generates dungeons of rectangular rooms joined by corridors, by splitting
+// the grid in two recursively and placing a room in each final partition.
+type BSP struct {
+	// MinLeaf is the smallest width or height of a partition.
+	MinLeaf int
+	// MinRoom is the smallest width or height of a room.
+	MinRoom int
+	// Padding is the least number of cells between a room and the edge of its
+	// partition.
+	Padding int
+}
+
+// DefaultBSP splits partitions down to eight cells, placing rooms at least three
+// cells wide.
+var DefaultBSP = BSP{
+	MinLeaf: 8,
+	MinRoom: 3,
+	Padding: 1,
+}
+
+// Generate returns a grid of floor rooms and corridors surrounded by walls, and
+// the rooms placed. Sibling partitions are joined by a corridor between one room
+// in each, so every room is reachable. If rng is nil, a randomly seeded generator
+// is used.
+func (b BSP) Generate(rng alg.Float64Generator, w, h int) (*Grid, []intgeom.Rect2) {
+	rng = defaultRNG(rng)
+	g := NewGrid(w, h, Wall)
+	rooms := b.split(rng, g, intgeom.NewRect2WH(0, 0, w, h))
+	return g, rooms
+}
+
+// split partitions area, whose Max is exclusive, returning the rooms within it.
+func (b BSP) split(rng alg.Float64Generator, g *Grid, area intgeom.Rect2) []intgeom.Rect2 {
+	w, h := area.W(), area.H()
+	canX, canY := w >= b.MinLeaf*2, h >= b.MinLeaf*2
+	if !canX && !canY {
+		return b.room(rng, g, area)
+	}
+	vertical := canX
+	if canX && canY {
+		switch {
+		case float64(w) > float64(h)*1.25:
+			vertical = true
+		case float64(h) > float64(w)*1.25:
+			vertical = false
+		default:
+			vertical = rng.Float64() < .5
+		}
+	}
+	var a, c intgeom.Rect2
+	if vertical {
+		at := rangeIn(rng, b.MinLeaf, w-b.MinLeaf)
+		a = intgeom.NewRect2WH(area.Min.X(), area.Min.Y(), at, h)
+		c = intgeom.NewRect2WH(area.Min.X()+at, area.Min.Y(), w-at, h)
+	} else {
+		at := rangeIn(rng, b.MinLeaf, h-b.MinLeaf)
+		a = intgeom.NewRect2WH(area.Min.X(), area.Min.Y(), w, at)
+		c = intgeom.NewRect2WH(area.Min.X(), area.Min.Y()+at, w, h-at)
+	}
+	left, right := b.split(rng, g, a), b.split(rng, g, c)
+	if len(left) != 0 && len(right) != 0 {
+		corridor(rng, g, left[intn(rng, len(left))].Center(), right[intn(rng, len(right))].Center())
+	}
+	return append(left, right...)
+}
+
+// room places a room within area, whose Max is exclusive. The room's Max is
+// inclusive.
+func (b BSP) room(rng alg.Float64Generator, g *Grid, area intgeom.Rect2) []intgeom.Rect2 {
+	maxW, maxH := area.W()-b.Padding*2, area.H()-b.Padding*2
+	if maxW < b.MinRoom || maxH < b.MinRoom {
+		return nil
+	}
+	rw, rh := rangeIn(rng, b.MinRoom, maxW), rangeIn(rng, b.MinRoom, maxH)
+	x := area.Min.X() + b.Padding + intn(rng, maxW-rw+1)
+	y := area.Min.Y() + b.Padding + intn(rng, maxH-rh+1)
+	room := intgeom.NewRect2(x, y, x+rw-1, y+rh-1)
+	g.Fill(room, Floor)
+	return []intgeom.Rect2{room}
+}
+
+// corridor carves an L shaped corridor between two cells, turning at one of the
+// two corners between them.
+func corridor(rng alg.Float64Generator, g *Grid, a, b intgeom.Point2) {
+	corner := intgeom.Point2{b.X(), a.Y()}
+	if rng.Float64() < .5 {
+		corner = intgeom.Point2{a.X(), b.Y()}
+	}
+	g.Fill(intgeom.NewRect2(a.X(), a.Y(), corner.X(), corner.Y()), Floor)
+	g.Fill(intgeom.NewRect2(corner.X(), corner.Y(), b.X(), b.Y()), Floor)
+}
@@ -0,0 +1,72 @@
+package procgen
+
+import (
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// Cave generates caves with a cellular automaton: cells start as random walls and
+// floors, then each step a cell becomes a wall if enough of its eight neighbors
+// are walls. Cells beyond the grid count as walls, so caves are enclosed.
+type Cave struct {
+	// FillChance is the chance each cell starts as a wall.
+	FillChance float64
+	Steps      int
+	// BirthLimit is how many wall neighbors turn a floor into a wall, and
+	// SurviveLimit how many keep a wall a wall.
+	BirthLimit   int
+	SurviveLimit int
+	// KeepLargest fills every floor region but the largest with walls.
+	KeepLargest bool
+}
+
+// DefaultCave produces open, connected caves.
+var DefaultCave = Cave{
+	FillChance:   .45,
+	Steps:        5,
+	BirthLimit:   5,
+	SurviveLimit: 4,
+	KeepLargest:  true,
+}
+
+// Generate returns a cave grid of walls and floors. If rng is nil, a randomly
+// seeded generator is used.
+func (c Cave) Generate(rng alg.Float64Generator, w, h int) *Grid {
+	rng = defaultRNG(rng)
+	g := NewGrid(w, h, Floor)
+	for i := range g.Tiles {
+		if rng.Float64() < c.FillChance {
+			g.Tiles[i] = Wall
+		}
+	}
+	next := NewGrid(w, h, Floor)
+	for s := 0; s < c.Steps; s++ {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				p := intgeom.Point2{x, y}
+				walls := 0
+				for dx := -1; dx <= 1; dx++ {
+					for dy := -1; dy <= 1; dy++ {
+						if (dx != 0 || dy != 0) && g.At(intgeom.Point2{x + dx, y + dy}) == Wall {
+							walls++
+						}
+					}
+				}
+				limit := c.BirthLimit
+				if g.At(p) == Wall {
+					limit = c.SurviveLimit
+				}
+				if walls >= limit {
+					next.Set(p, Wall)
+				} else {
+					next.Set(p, Floor)
+				}
+			}
+		}
+		g, next = next, g
+	}
+	if c.KeepLargest {
+		g.KeepLargest(Floor, Wall)
+	}
+	return g
+}
@@ -0,0 +1,6 @@
+// Package procgen generates levels and places objects procedurally.
+//
+// Generators fill a Grid of tiles, which can be converted to collision spaces or
+// a pathfinding grid. Every generator draws its randomness from an
+// alg.Float64Generator, so a level can be reproduced from its seed.
+package procgen
@@ -0,0 +1,206 @@
+package procgen
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/alg/path"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+// A Tile is the content of a grid cell. Generators use Wall and Floor; other
+// values may be defined by callers, e.g. for Wave Function Collapse tilesets.
+type Tile int
+
+// Tiles used by generators.
+const (
+	Wall Tile = iota
+	Floor
+)
+
+var orthogonal = []intgeom.Point2{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// A Grid is a rectangle of tiles.
+type Grid struct {
+	Width, Height int
+	Tiles         []Tile
+}
+
+// NewGrid creates a grid filled with one tile.
+func NewGrid(w, h int, fill Tile) *Grid {
+	g := &Grid{
+		Width:  w,
+		Height: h,
+		Tiles:  make([]Tile, w*h),
+	}
+	for i := range g.Tiles {
+		g.Tiles[i] = fill
+	}
+	return g
+}
+
+// InBounds returns whether p is a cell of the grid.
+func (g *Grid) InBounds(p intgeom.Point2) bool {
+	return p.X() >= 0 && p.Y() >= 0 && p.X() < g.Width && p.Y() < g.Height
+}
+
+// At returns the tile at p. Cells outside the grid are walls.
+func (g *Grid) At(p intgeom.Point2) Tile {
+	if !g.InBounds(p) {
+		return Wall
+	}
+	return g.Tiles[p.Y()*g.Width+p.X()]
+}
+
+// Set sets the tile at p. Cells outside the grid are ignored.
+func (g *Grid) Set(p intgeom.Point2, t Tile) {
+	if g.InBounds(p) {
+		g.Tiles[p.Y()*g.Width+p.X()] = t
+	}
+}
+
+// Fill sets the tile of every cell in r, including its maximum row and column.
+func (g *Grid) Fill(r intgeom.Rect2, t Tile) {
+	for x := r.Min.X(); x <= r.Max.X(); x++ {
+		for y := r.Min.Y(); y <= r.Max.Y(); y++ {
+			g.Set(intgeom.Point2{x, y}, t)
+		}
+	}
+}
+
+// Count returns how many cells hold a tile.
+func (g *Grid) Count(t Tile) int {
+	n := 0
+	for _, t2 := range g.Tiles {
+		if t2 == t {
+			n++
+		}
+	}
+	return n
+}
+
+// Regions returns each orthogonally connected group of cells holding a tile,
+// largest first.
+func (g *Grid) Regions(t Tile) [][]intgeom.Point2 {
+	seen := make([]bool, len(g.Tiles))
+	var regions [][]intgeom.Point2
+	for i, t2 := range g.Tiles {
+		if t2 != t || seen[i] {
+			continue
+		}
+		seen[i] = true
+		region := []intgeom.Point2{{i % g.Width, i / g.Width}}
+		for j := 0; j < len(region); j++ {
+			for _, d := range orthogonal {
+				p := region[j].Add(d)
+				if g.At(p) != t || !g.InBounds(p) || seen[p.Y()*g.Width+p.X()] {
+					continue
+				}
+				seen[p.Y()*g.Width+p.X()] = true
+				region = append(region, p)
+			}
+		}
+		regions = append(regions, region)
+	}
+	sort.SliceStable(regions, func(i, j int) bool {
+		return len(regions[i]) > len(regions[j])
+	})
+	return regions
+}
+
+// KeepLargest replaces every region of a tile but the largest with another tile,
+// e.g. to remove cave pockets unreachable from the main cave.
+func (g *Grid) KeepLargest(t, replace Tile) {
+	regions := g.Regions(t)
+	if len(regions) < 2 {
+		return
+	}
+	for _, r := range regions[1:] {
+		for _, p := range r {
+			g.Set(p, replace)
+		}
+	}
+}
+
+// Spaces returns collision spaces covering every cell holding a tile, merging
+// cells into as few rectangles as a greedy scan finds. Cell (0,0)'s top left
+// corner is placed at origin.
+func (g *Grid) Spaces(t Tile, origin floatgeom.Point2, cellSize float64, label collision.Label) []*collision.Space {
+	used := make([]bool, len(g.Tiles))
+	free := func(x, y int) bool {
+		p := intgeom.Point2{x, y}
+		return g.InBounds(p) && !used[y*g.Width+x] && g.At(p) == t
+	}
+	var spaces []*collision.Space
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if !free(x, y) {
+				continue
+			}
+			w := 1
+			for free(x+w, y) {
+				w++
+			}
+			h := 1
+		grow:
+			for {
+				for i := 0; i < w; i++ {
+					if !free(x+i, y+h) {
+						break grow
+					}
+				}
+				h++
+			}
+			for i := 0; i < w; i++ {
+				for j := 0; j < h; j++ {
+					used[(y+j)*g.Width+x+i] = true
+				}
+			}
+			spaces = append(spaces, collision.NewLabeledSpace(
+				origin.X()+float64(x)*cellSize, origin.Y()+float64(y)*cellSize,
+				float64(w)*cellSize, float64(h)*cellSize, label))
+		}
+	}
+	return spaces
+}
+
+// PathGrid returns a pathfinding grid where cells holding any of the walkable
+// tiles have a cost of one and all other cells are blocked.
+func (g *Grid) PathGrid(walkable ...Tile) *path.Grid {
+	pg := path.NewGrid(g.Width, g.Height)
+	for i, t := range g.Tiles {
+		ok := false
+		for _, w := range walkable {
+			ok = ok || t == w
+		}
+		pg.SetWalkable(intgeom.Point2{i % g.Width, i / g.Width}, ok)
+	}
+	return pg
+}
+
+// intn returns a number in [0, n) from rng.
+func intn(rng alg.Float64Generator, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	i := int(rng.Float64() * float64(n))
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
+
+// rangeIn returns a number in [lo, hi] from rng.
+func rangeIn(rng alg.Float64Generator, lo, hi int) int {
+	return lo + intn(rng, hi-lo+1)
+}
+
+func defaultRNG(rng alg.Float64Generator) alg.Float64Generator {
+	if rng == nil {
+		return rand.New(rand.NewSource(rand.Int63()))
+	}
+	return rng
+}
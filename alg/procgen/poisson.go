@@ -0,0 +1,107 @@
+package procgen
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// poissonTries is how many candidates are tried around each point before it is
+// considered surrounded.
+const poissonTries = 30
+
+// PoissonDisc returns points within bounds, none closer than radius to another,
+// using Bridson's algorithm. If accept is not nil, only points it accepts are
+// returned, e.g. to place objects only on floor tiles. After growing from a random
+// point, every empty cell of the sampling grid is tried as a new seed, so separate
+// accepted areas are each filled and few if any more points could be added. If
+// rng is nil, a randomly seeded generator is used.
+func PoissonDisc(rng alg.Float64Generator, bounds floatgeom.Rect2, radius float64, accept func(floatgeom.Point2) bool) []floatgeom.Point2 {
+	rng = defaultRNG(rng)
+	if radius <= 0 {
+		return nil
+	}
+	cell := radius / math.Sqrt2
+	cols := int(math.Ceil(bounds.W()/cell)) + 1
+	rows := int(math.Ceil(bounds.H()/cell)) + 1
+	grid := make([]int, cols*rows)
+	for i := range grid {
+		grid[i] = -1
+	}
+	cellOf := func(p floatgeom.Point2) (int, int) {
+		return int((p.X() - bounds.Min.X()) / cell), int((p.Y() - bounds.Min.Y()) / cell)
+	}
+	var pts []floatgeom.Point2
+	valid := func(p floatgeom.Point2) bool {
+		if p.X() < bounds.Min.X() || p.Y() < bounds.Min.Y() || p.X() >= bounds.Max.X() || p.Y() >= bounds.Max.Y() {
+			return false
+		}
+		if accept != nil && !accept(p) {
+			return false
+		}
+		cx, cy := cellOf(p)
+		for x := cx - 2; x <= cx+2; x++ {
+			for y := cy - 2; y <= cy+2; y++ {
+				if x < 0 || y < 0 || x >= cols || y >= rows {
+					continue
+				}
+				if i := grid[y*cols+x]; i != -1 && pts[i].Distance(p) < radius {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	add := func(p floatgeom.Point2) {
+		cx, cy := cellOf(p)
+		grid[cy*cols+cx] = len(pts)
+		pts = append(pts, p)
+	}
+	grow := func(p floatgeom.Point2) {
+		add(p)
+		active := []int{len(pts) - 1}
+		for len(active) != 0 {
+			ai := intn(rng, len(active))
+			center := pts[active[ai]]
+			found := false
+			for k := 0; k < poissonTries; k++ {
+				dist := radius * (1 + rng.Float64())
+				c := center.Add(floatgeom.RadianPoint(rng.Float64() * 2 * math.Pi).MulConst(dist))
+				if valid(c) {
+					add(c)
+					active = append(active, len(pts)-1)
+					found = true
+					break
+				}
+			}
+			if !found {
+				active[ai] = active[len(active)-1]
+				active = active[:len(active)-1]
+			}
+		}
+	}
+	start := floatgeom.Point2{
+		bounds.Min.X() + rng.Float64()*bounds.W(),
+		bounds.Min.Y() + rng.Float64()*bounds.H(),
+	}
+	if valid(start) {
+		grow(start)
+	}
+	// Seed from every empty cell, so areas the first seed could not reach are
+	// filled, as are gaps it left behind
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			for k := 0; k < poissonTries && grid[y*cols+x] == -1; k++ {
+				p := floatgeom.Point2{
+					bounds.Min.X() + (float64(x)+rng.Float64())*cell,
+					bounds.Min.Y() + (float64(y)+rng.Float64())*cell,
+				}
+				if valid(p) {
+					grow(p)
+				}
+			}
+		}
+	}
+	return pts
+}
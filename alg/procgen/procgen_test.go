@@ -0,0 +1,255 @@
+package procgen
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/alg/path"
+)
+
+func seeded(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+func checkEnclosed(t *testing.T, g *Grid) {
+	t.Helper()
+	for x := 0; x < g.Width; x++ {
+		for _, y := range []int{0, g.Height - 1} {
+			if g.At(intgeom.Point2{x, y}) != Wall {
+				t.Fatalf("expected wall on border at %v,%v", x, y)
+			}
+		}
+	}
+	for y := 0; y < g.Height; y++ {
+		for _, x := range []int{0, g.Width - 1} {
+			if g.At(intgeom.Point2{x, y}) != Wall {
+				t.Fatalf("expected wall on border at %v,%v", x, y)
+			}
+		}
+	}
+}
+
+func checkConnected(t *testing.T, g *Grid) {
+	t.Helper()
+	if regions := g.Regions(Floor); len(regions) != 1 {
+		t.Fatalf("expected one connected floor region, got %v", len(regions))
+	}
+}
+
+func TestBSP(t *testing.T) {
+	g, rooms := DefaultBSP.Generate(seeded(1), 60, 40)
+	g2, _ := DefaultBSP.Generate(seeded(1), 60, 40)
+	if !reflect.DeepEqual(g, g2) {
+		t.Fatal("expected the same seed to generate the same dungeon")
+	}
+	if len(rooms) < 6 {
+		t.Fatalf("expected several rooms, got %v", len(rooms))
+	}
+	for i, r := range rooms {
+		if r.W()+1 < DefaultBSP.MinRoom || r.H()+1 < DefaultBSP.MinRoom {
+			t.Fatalf("room %v smaller than minimum", r)
+		}
+		for _, r2 := range rooms[i+1:] {
+			if r.Intersects(r2) {
+				t.Fatalf("rooms %v and %v overlap", r, r2)
+			}
+		}
+	}
+	checkEnclosed(t, g)
+	checkConnected(t, g)
+}
+
+func TestCave(t *testing.T) {
+	g := DefaultCave.Generate(seeded(2), 60, 40)
+	if g.Count(Floor) < 60*40/4 {
+		t.Fatalf("expected a sizeable cave, got %v floor cells", g.Count(Floor))
+	}
+	checkConnected(t, g)
+	if !reflect.DeepEqual(g, DefaultCave.Generate(seeded(2), 60, 40)) {
+		t.Fatal("expected the same seed to generate the same cave")
+	}
+	if reflect.DeepEqual(g, DefaultCave.Generate(seeded(3), 60, 40)) {
+		t.Fatal("expected different seeds to generate different caves")
+	}
+}
+
+func TestDrunkardWalk(t *testing.T) {
+	g := DefaultDrunkardWalk.Generate(seeded(1), 50, 30)
+	want := int(DefaultDrunkardWalk.Coverage * 48 * 28)
+	if got := g.Count(Floor); got != want {
+		t.Fatalf("expected %v floor cells, got %v", want, got)
+	}
+	checkEnclosed(t, g)
+	checkConnected(t, g)
+	limited := DrunkardWalk{Coverage: 1, MaxSteps: 10}.Generate(seeded(1), 50, 30)
+	if got := limited.Count(Floor); got > 11 {
+		t.Fatalf("expected at most 11 floor cells with 10 steps, got %v", got)
+	}
+}
+
+const (
+	water Tile = iota + 10
+	sand
+	grass
+)
+
+func coastTileset(t *testing.T) *Tileset {
+	ts := NewTileset()
+	for _, tile := range []Tile{water, sand, grass} {
+		if err := ts.Add(tile, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.Add(water, 1); err == nil {
+		t.Fatal("expected error adding a tile twice")
+	}
+	// water may only border sand, and sand grass
+	for _, err := range []error{
+		ts.AllowAll(water, sand),
+		ts.AllowAll(sand, grass),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ts
+}
+
+func TestCollapse(t *testing.T) {
+	ts := coastTileset(t)
+	fixed := map[intgeom.Point2]Tile{{0, 0}: water, {19, 19}: grass}
+	g, err := ts.Collapse(seeded(1), 20, 20, fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.At(intgeom.Point2{0, 0}) != water || g.At(intgeom.Point2{19, 19}) != grass {
+		t.Fatal("expected fixed tiles to be kept")
+	}
+	for i, tile := range g.Tiles {
+		p := intgeom.Point2{i % g.Width, i / g.Width}
+		for _, d := range directions {
+			np := p.Add(intgeom.Point2(d))
+			if !g.InBounds(np) {
+				continue
+			}
+			if n := g.At(np); (tile == water && n == grass) || (tile == grass && n == water) {
+				t.Fatalf("water beside grass at %v", p)
+			}
+		}
+	}
+	if err := ts.Allow(water, intgeom.UpLeft, sand); err == nil {
+		t.Fatal("expected error for diagonal direction")
+	}
+	if err := ts.Allow(water, intgeom.Up, 99); err == nil {
+		t.Fatal("expected error for unknown tile")
+	}
+}
+
+func TestCollapseContradiction(t *testing.T) {
+	ts := coastTileset(t)
+	ts.Attempts = 2
+	// water and grass can not be adjacent
+	_, err := ts.Collapse(seeded(1), 2, 1, map[intgeom.Point2]Tile{{0, 0}: water, {1, 0}: grass})
+	if !errors.Is(err, ErrContradiction) {
+		t.Fatalf("expected contradiction, got %v", err)
+	}
+}
+
+func TestPoissonDisc(t *testing.T) {
+	bounds := floatgeom.NewRect2(0, 0, 100, 100)
+	pts := PoissonDisc(seeded(1), bounds, 5, nil)
+	// a packed disc sampling covers roughly two thirds of the densest packing
+	if len(pts) < 200 {
+		t.Fatalf("expected dense sampling, got %v points", len(pts))
+	}
+	for i, p := range pts {
+		if !bounds.Contains(p) {
+			t.Fatalf("point %v out of bounds", p)
+		}
+		for _, q := range pts[i+1:] {
+			if p.Distance(q) < 5 {
+				t.Fatalf("points %v and %v closer than radius", p, q)
+			}
+		}
+	}
+	// only on floors of a generated cave
+	g := DefaultCave.Generate(seeded(2), 40, 40)
+	onFloor := func(p floatgeom.Point2) bool {
+		return g.At(intgeom.Point2{int(p.X()), int(p.Y())}) == Floor
+	}
+	for _, p := range PoissonDisc(seeded(3), floatgeom.NewRect2(0, 0, 40, 40), 2, onFloor) {
+		if !onFloor(p) {
+			t.Fatalf("point %v not on a floor", p)
+		}
+	}
+}
+
+func TestPoissonDiscSeparateAreas(t *testing.T) {
+	// many small islands, each too small for points to spread between them
+	onIsland := func(p floatgeom.Point2) bool {
+		return math.Mod(p.X(), 20) < 3 && math.Mod(p.Y(), 20) < 3
+	}
+	pts := PoissonDisc(seeded(5), floatgeom.NewRect2(0, 0, 200, 200), 5, onIsland)
+	islands := make(map[intgeom.Point2]bool)
+	for _, p := range pts {
+		if !onIsland(p) {
+			t.Fatalf("point %v not on an island", p)
+		}
+		islands[intgeom.Point2{int(p.X()) / 20, int(p.Y()) / 20}] = true
+	}
+	if len(islands) != 100 {
+		t.Fatalf("expected a point on each of 100 islands, got %v", len(islands))
+	}
+	// few if any points could be added to a packed sampling
+	pts = PoissonDisc(seeded(6), floatgeom.NewRect2(0, 0, 100, 100), 5, nil)
+	free := 0
+	for x := 0.0; x < 100; x++ {
+		for y := 0.0; y < 100; y++ {
+			p := floatgeom.Point2{x, y}
+			free++
+			for _, q := range pts {
+				if p.Distance(q) < 5 {
+					free--
+					break
+				}
+			}
+		}
+	}
+	if free > 10 {
+		t.Fatalf("expected few gaps in sampling, %v points could have been added", free)
+	}
+}
+
+func TestGridConversions(t *testing.T) {
+	g, _ := DefaultBSP.Generate(seeded(4), 40, 30)
+	spaces := g.Spaces(Wall, floatgeom.Point2{10, 20}, 16, 3)
+	area := 0.0
+	for _, s := range spaces {
+		area += s.W() * s.H()
+		if s.Label != 3 {
+			t.Fatalf("expected label 3, got %v", s.Label)
+		}
+		// the top left cell of each space should be a wall
+		cell := intgeom.Point2{int((s.X() - 10) / 16), int((s.Y() - 20) / 16)}
+		if g.At(cell) != Wall {
+			t.Fatalf("space %v covers floor", s)
+		}
+	}
+	if want := float64(g.Count(Wall)) * 16 * 16; area != want {
+		t.Fatalf("expected spaces to cover area %v, got %v", want, area)
+	}
+	if len(spaces) >= g.Count(Wall)/4 {
+		t.Fatalf("expected walls to merge into few spaces, got %v", len(spaces))
+	}
+	pg := g.PathGrid(Floor)
+	regions := g.Regions(Floor)
+	from, to := regions[0][0], regions[0][len(regions[0])-1]
+	if _, ok := path.AStar(pg, from, to, path.FourWay); !ok {
+		t.Fatal("expected path between floor cells")
+	}
+}
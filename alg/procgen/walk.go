@@ -0,0 +1,63 @@
+package procgen
+
+import (
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// DrunkardWalk generates winding caves by carving floor along random walks.
+type DrunkardWalk struct {
+	// Coverage is the portion of the grid to carve into floor.
+	Coverage float64
+	// WalkLength is how many steps each walker takes before a new walker starts
+	// from a random carved cell. If zero, a single walker carves the whole cave.
+	WalkLength int
+	// MaxSteps limits the total steps taken, in case Coverage can not be reached.
+	// If zero, it is twenty times the number of cells.
+	MaxSteps int
+}
+
+// DefaultDrunkardWalk carves forty percent of the grid with walks of a hundred
+// steps.
+var DefaultDrunkardWalk = DrunkardWalk{
+	Coverage:   .4,
+	WalkLength: 100,
+}
+
+// Generate returns a grid with connected floors carved from walls, starting from
+// its center. The outermost cells are never carved. If rng is nil, a randomly
+// seeded generator is used.
+func (d DrunkardWalk) Generate(rng alg.Float64Generator, w, h int) *Grid {
+	rng = defaultRNG(rng)
+	g := NewGrid(w, h, Wall)
+	if w < 3 || h < 3 {
+		return g
+	}
+	inner := intgeom.NewRect2(1, 1, w-2, h-2)
+	target := int(d.Coverage * float64((w-2)*(h-2)))
+	maxSteps := d.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = w * h * 20
+	}
+	pos := intgeom.Point2{w / 2, h / 2}
+	g.Set(pos, Floor)
+	carved := []intgeom.Point2{pos}
+	walked := 0
+	for step := 0; step < maxSteps && len(carved) < target; step++ {
+		if d.WalkLength != 0 && walked == d.WalkLength {
+			pos = carved[intn(rng, len(carved))]
+			walked = 0
+		}
+		next := pos.Add(orthogonal[intn(rng, len(orthogonal))])
+		if !inner.Contains(next) {
+			continue
+		}
+		pos = next
+		walked++
+		if g.At(pos) != Floor {
+			g.Set(pos, Floor)
+			carved = append(carved, pos)
+		}
+	}
+	return g
+}
@@ -0,0 +1,272 @@
+package procgen
+
+import (
+	"errors"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// ErrContradiction is returned when Wave Function Collapse leaves a cell with no
+// tile that fits its neighbors on every attempt.
+var ErrContradiction = errors.New("wave function collapse reached a contradiction")
+
+// directions are the orthogonal neighbors considered by a Tileset, in the order
+// of its adjacency rules.
+var directions = []intgeom.Dir2{intgeom.Up, intgeom.Right, intgeom.Down, intgeom.Left}
+
+func directionIndex(d intgeom.Dir2) int {
+	for i, d2 := range directions {
+		if d == d2 {
+			return i
+		}
+	}
+	return -1
+}
+
+// A Tileset defines which tiles may be placed beside one another, for generating
+// grids with Wave Function Collapse.
+type Tileset struct {
+	// Attempts is how many times Collapse restarts after a contradiction before
+	// failing.
+	Attempts int
+
+	tiles   []Tile
+	weights []float64
+	index   map[Tile]int
+	// allowed[d][a][b] is whether tile b may be in direction d from tile a
+	allowed [4][][]bool
+}
+
+// NewTileset creates an empty tileset.
+func NewTileset() *Tileset {
+	return &Tileset{
+		Attempts: 10,
+		index:    make(map[Tile]int),
+	}
+}
+
+// Add adds a tile, weighted by how often it should be chosen relative to other
+// tiles. Adding an existing tile returns an ExistingElement error.
+func (ts *Tileset) Add(t Tile, weight float64) error {
+	if _, ok := ts.index[t]; ok {
+		return oakerr.ExistingElement{InputName: "t", InputType: "tile"}
+	}
+	if weight <= 0 {
+		return oakerr.InvalidInput{InputName: "weight"}
+	}
+	ts.index[t] = len(ts.tiles)
+	ts.tiles = append(ts.tiles, t)
+	ts.weights = append(ts.weights, weight)
+	for d := range ts.allowed {
+		for i := range ts.allowed[d] {
+			ts.allowed[d][i] = append(ts.allowed[d][i], false)
+		}
+		ts.allowed[d] = append(ts.allowed[d], make([]bool, len(ts.tiles)))
+	}
+	return nil
+}
+
+// Allow allows tile b to be placed in direction dir from tile a, and so a to be
+// placed in the opposite direction from b. dir must be Up, Down, Left or Right.
+func (ts *Tileset) Allow(a Tile, dir intgeom.Dir2, b Tile) error {
+	ai, ok := ts.index[a]
+	if !ok {
+		return oakerr.NotFound{InputName: "a"}
+	}
+	bi, ok := ts.index[b]
+	if !ok {
+		return oakerr.NotFound{InputName: "b"}
+	}
+	d := directionIndex(dir)
+	if d == -1 {
+		return oakerr.InvalidInput{InputName: "dir"}
+	}
+	ts.allowed[d][ai][bi] = true
+	ts.allowed[(d+2)%4][bi][ai] = true
+	return nil
+}
+
+// AllowAll allows each pair of the given tiles, including each tile with itself,
+// to be placed beside one another in every direction.
+func (ts *Tileset) AllowAll(tiles ...Tile) error {
+	for _, a := range tiles {
+		for _, b := range tiles {
+			for _, d := range directions {
+				if err := ts.Allow(a, d, b); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Collapse generates a grid in which every pair of adjacent tiles is allowed, by
+// repeatedly placing a tile in the cell with the fewest options and removing
+// options which no longer fit from its neighbors. Cells in fixed start with the
+// given tiles. If rng is nil, a randomly seeded generator is used.
+//
+// If every attempt reaches a cell with no options, ErrContradiction is returned.
+func (ts *Tileset) Collapse(rng alg.Float64Generator, w, h int, fixed map[intgeom.Point2]Tile) (*Grid, error) {
+	if len(ts.tiles) == 0 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 1, InputName: "tiles"}
+	}
+	rng = defaultRNG(rng)
+	for attempt := 0; attempt < ts.Attempts || attempt == 0; attempt++ {
+		wv := &wave{ts: ts, w: w, h: h, rng: rng}
+		if g, ok := wv.run(fixed); ok {
+			return g, nil
+		}
+	}
+	return nil, ErrContradiction
+}
+
+// wave tracks which tiles remain possible in each cell during Collapse.
+type wave struct {
+	ts       *Tileset
+	w, h     int
+	rng      alg.Float64Generator
+	possible [][]bool
+	counts   []int
+}
+
+func (wv *wave) run(fixed map[intgeom.Point2]Tile) (*Grid, bool) {
+	n := len(wv.ts.tiles)
+	wv.possible = make([][]bool, wv.w*wv.h)
+	wv.counts = make([]int, wv.w*wv.h)
+	for i := range wv.possible {
+		wv.possible[i] = make([]bool, n)
+		for t := range wv.possible[i] {
+			wv.possible[i][t] = true
+		}
+		wv.counts[i] = n
+	}
+	for p, t := range fixed {
+		ti, ok := wv.ts.index[t]
+		if !ok || p.X() < 0 || p.Y() < 0 || p.X() >= wv.w || p.Y() >= wv.h {
+			continue
+		}
+		if !wv.collapse(p.Y()*wv.w+p.X(), ti) {
+			return nil, false
+		}
+	}
+	for {
+		cell := wv.lowestEntropy()
+		if cell == -1 {
+			break
+		}
+		if !wv.collapse(cell, wv.choose(cell)) {
+			return nil, false
+		}
+	}
+	g := NewGrid(wv.w, wv.h, 0)
+	for i, poss := range wv.possible {
+		for t, ok := range poss {
+			if ok {
+				g.Tiles[i] = wv.ts.tiles[t]
+				break
+			}
+		}
+	}
+	return g, true
+}
+
+// lowestEntropy returns the undecided cell with the least entropy, breaking ties
+// randomly, or -1 if every cell is decided.
+func (wv *wave) lowestEntropy() int {
+	best, bestEntropy := -1, math.Inf(1)
+	for i, poss := range wv.possible {
+		if wv.counts[i] <= 1 {
+			continue
+		}
+		sum, sumLog := 0.0, 0.0
+		for t, ok := range poss {
+			if ok {
+				w := wv.ts.weights[t]
+				sum += w
+				sumLog += w * math.Log(w)
+			}
+		}
+		entropy := math.Log(sum) - sumLog/sum + wv.rng.Float64()*1e-6
+		if entropy < bestEntropy {
+			best, bestEntropy = i, entropy
+		}
+	}
+	return best
+}
+
+// choose picks a possible tile for a cell by weight.
+func (wv *wave) choose(cell int) int {
+	total := 0.0
+	for t, ok := range wv.possible[cell] {
+		if ok {
+			total += wv.ts.weights[t]
+		}
+	}
+	r := wv.rng.Float64() * total
+	last := -1
+	for t, ok := range wv.possible[cell] {
+		if !ok {
+			continue
+		}
+		last = t
+		r -= wv.ts.weights[t]
+		if r < 0 {
+			return t
+		}
+	}
+	return last
+}
+
+// collapse decides a cell's tile and propagates the decision, returning false
+// on a contradiction.
+func (wv *wave) collapse(cell, tile int) bool {
+	if !wv.possible[cell][tile] {
+		return false
+	}
+	for t := range wv.possible[cell] {
+		wv.possible[cell][t] = t == tile
+	}
+	wv.counts[cell] = 1
+	stack := []int{cell}
+	for len(stack) != 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		p := intgeom.Point2{c % wv.w, c / wv.w}
+		for d, dir := range directions {
+			np := p.Add(intgeom.Point2(dir))
+			if np.X() < 0 || np.Y() < 0 || np.X() >= wv.w || np.Y() >= wv.h {
+				continue
+			}
+			nc := np.Y()*wv.w + np.X()
+			changed := false
+			for b, ok := range wv.possible[nc] {
+				if !ok {
+					continue
+				}
+				supported := false
+				for a, aok := range wv.possible[c] {
+					if aok && wv.ts.allowed[d][a][b] {
+						supported = true
+						break
+					}
+				}
+				if !supported {
+					wv.possible[nc][b] = false
+					wv.counts[nc]--
+					changed = true
+				}
+			}
+			if wv.counts[nc] == 0 {
+				return false
+			}
+			if changed {
+				stack = append(stack, nc)
+			}
+		}
+	}
+	return true
+}
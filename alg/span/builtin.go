@@ -1,6 +1,7 @@
 package span
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/oakmound/oak/v4/alg/span/internal/random"
@@ -12,6 +13,33 @@ type Spanable interface {
 	constraints.Float | constraints.Integer
 }
 
+// fromFloat converts f to T. For integer types, f is clamped to the range of T
+// rather than wrapping around.
+func fromFloat[T Spanable](f float64) T {
+	var zero T
+	half := 0.5
+	if T(half) != zero {
+		// T is a floating point type.
+		return T(f)
+	}
+	// Doubling one overflows to zero after as many steps as T has bits.
+	bits := 0.0
+	for v := T(1); v != 0; v *= 2 {
+		bits++
+	}
+	lo, hi := 0.0, math.Exp2(bits)
+	if zero-1 < zero {
+		lo, hi = -math.Exp2(bits-1), math.Exp2(bits-1)
+	}
+	if f < lo {
+		return T(lo)
+	}
+	if f >= hi {
+		return T(math.Nextafter(hi, 0))
+	}
+	return T(f)
+}
+
 // NewConstant returns a span where the minimum and maximum are both i. Poll, Percentile, and Clamp will always return i.
 func NewConstant[T Spanable](i T) Span[T] {
 	return constant[T]{i}
@@ -0,0 +1,161 @@
+package span
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg/ease"
+	"github.com/oakmound/oak/v4/alg/span/internal/random"
+)
+
+// NewEased returns a span from min to max whose Percentile follows the easing
+// function e. Poll is weighted the same way: an easing which moves slowly near its
+// start will poll values near min more often. Clamp limits values to lie between
+// min and max, even if e overshoots them.
+func NewEased[T Spanable](min, max T, e ease.Func) Span[T] {
+	if min == max {
+		return constant[T]{min}
+	}
+	if e == nil {
+		return NewLinear(min, max)
+	}
+	return eased[T]{
+		from: min,
+		to:   max,
+		ease: e,
+		rng:  random.Rand(),
+	}
+}
+
+type eased[T Spanable] struct {
+	from, to T
+	ease     ease.Func
+	rng      *rand.Rand
+}
+
+func (e eased[T]) Poll() T {
+	return e.Percentile(e.rng.Float64())
+}
+
+func (e eased[T]) MulSpan(i float64) Span[T] {
+	e.from = fromFloat[T](float64(e.from) * i)
+	e.to = fromFloat[T](float64(e.to) * i)
+	return e
+}
+
+func (e eased[T]) Clamp(v T) T {
+	lo, hi := e.from, e.to
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	if v < lo {
+		return lo
+	} else if v > hi {
+		return hi
+	}
+	return v
+}
+
+func (e eased[T]) Percentile(f float64) T {
+	return fromFloat[T](ease.Lerp(float64(e.from), float64(e.to), e.ease(f)))
+}
+
+// A Key is a value at some percentile through a multi-stop span.
+type Key[T any] struct {
+	// At is the percentile of this key, from 0 to 1.
+	At    float64
+	Value T
+	// Ease shapes the transition from this key to the next. If nil, the
+	// transition is linear.
+	Ease ease.Func
+}
+
+// segment finds the keys surrounding the percentile f and how far f is between
+// them, eased. Keys must be sorted.
+func segment[T any](keys []Key[T], f float64) (a, b Key[T], u float64) {
+	if f <= keys[0].At {
+		return keys[0], keys[0], 0
+	}
+	last := keys[len(keys)-1]
+	if f >= last.At {
+		return last, last, 0
+	}
+	i := sort.Search(len(keys), func(i int) bool {
+		return keys[i].At > f
+	})
+	a, b = keys[i-1], keys[i]
+	u = (f - a.At) / (b.At - a.At)
+	if a.Ease != nil {
+		u = a.Ease(u)
+	}
+	return a, b, u
+}
+
+func sortKeys[T any](keys []Key[T]) []Key[T] {
+	keys = append([]Key[T]{}, keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].At < keys[j].At
+	})
+	return keys
+}
+
+// NewKeyframes returns a span which passes through each key's value at that key's
+// percentile, interpolating between them. Percentiles before the first key or
+// after the last hold those keys' values. Poll picks a percentile uniformly, and
+// Clamp limits values to lie between the smallest and largest key values. With no
+// keys, the span is a constant zero.
+func NewKeyframes[T Spanable](keys ...Key[T]) Span[T] {
+	if len(keys) == 0 {
+		var zero T
+		return constant[T]{zero}
+	}
+	if len(keys) == 1 {
+		return constant[T]{keys[0].Value}
+	}
+	return keyframes[T]{
+		keys: sortKeys(keys),
+		rng:  random.Rand(),
+	}
+}
+
+type keyframes[T Spanable] struct {
+	keys []Key[T]
+	rng  *rand.Rand
+}
+
+func (k keyframes[T]) Poll() T {
+	return k.Percentile(k.rng.Float64())
+}
+
+func (k keyframes[T]) MulSpan(i float64) Span[T] {
+	keys := make([]Key[T], len(k.keys))
+	for j, key := range k.keys {
+		key.Value = fromFloat[T](float64(key.Value) * i)
+		keys[j] = key
+	}
+	k.keys = keys
+	return k
+}
+
+func (k keyframes[T]) Clamp(v T) T {
+	lo, hi := k.keys[0].Value, k.keys[0].Value
+	for _, key := range k.keys[1:] {
+		if key.Value < lo {
+			lo = key.Value
+		}
+		if key.Value > hi {
+			hi = key.Value
+		}
+	}
+	if v < lo {
+		return lo
+	} else if v > hi {
+		return hi
+	}
+	return v
+}
+
+func (k keyframes[T]) Percentile(f float64) T {
+	a, b, u := segment(k.keys, f)
+	return fromFloat[T](ease.Lerp(float64(a.Value), float64(b.Value), u))
+}
@@ -0,0 +1,119 @@
+package span
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/ease"
+)
+
+func TestEased(t *testing.T) {
+	if _, ok := NewEased(1, 1, ease.InQuad).(constant[int]); !ok {
+		t.Fatalf("NewEased with no variance did not create constant")
+	}
+	if _, ok := NewEased(1.0, 2.0, nil).(linear[float64]); !ok {
+		t.Fatalf("NewEased with no easing did not create linear")
+	}
+	sp := NewEased(10.0, 20.0, ease.InQuad)
+	if v := sp.Percentile(.5); v != 12.5 {
+		t.Fatalf("eased percentile was %v, expected 12.5", v)
+	}
+	back := NewEased(20.0, 10.0, ease.OutBack)
+	if v := back.Percentile(.8); v >= 10 {
+		t.Fatalf("overshooting percentile was %v, expected below 10", v)
+	}
+	if v := back.Clamp(5); v != 10 {
+		t.Fatalf("clamp gave %v, expected 10", v)
+	}
+	unsigned := NewEased[uint8](200, 100, ease.Linear)
+	if v := unsigned.Percentile(.5); v != 150 {
+		t.Fatalf("unsigned descending percentile was %v, expected 150", v)
+	}
+	for i := 0; i < 100; i++ {
+		if v := sp.Poll(); v < 10 || v > 20 {
+			t.Fatalf("poll out of range: %v", v)
+		}
+	}
+	if v := sp.MulSpan(2).Percentile(1); v != 40 {
+		t.Fatalf("multiplied span ended at %v, expected 40", v)
+	}
+}
+
+func TestKeyframes(t *testing.T) {
+	if v := NewKeyframes[int]().Poll(); v != 0 {
+		t.Fatalf("empty keyframes polled %v", v)
+	}
+	if v := NewKeyframes(Key[int]{At: .3, Value: 7}).Percentile(.9); v != 7 {
+		t.Fatalf("single keyframe gave %v", v)
+	}
+	sp := NewKeyframes(
+		Key[float64]{At: 1, Value: 0},
+		Key[float64]{At: 0, Value: 0},
+		Key[float64]{At: .5, Value: 100, Ease: ease.InQuad},
+	)
+	tests := []struct {
+		f, want float64
+	}{
+		{-1, 0},
+		{.25, 50},
+		{.5, 100},
+		{.75, 75},
+		{2, 0},
+	}
+	for _, tc := range tests {
+		if v := sp.Percentile(tc.f); math.Abs(v-tc.want) > 1e-9 {
+			t.Errorf("percentile %v was %v, expected %v", tc.f, v, tc.want)
+		}
+	}
+	if sp.Clamp(150) != 100 || sp.Clamp(-5) != 0 || sp.Clamp(30) != 30 {
+		t.Errorf("keyframes clamped incorrectly")
+	}
+	if v := sp.MulSpan(.5).Percentile(.5); v != 50 {
+		t.Errorf("multiplied peak was %v, expected 50", v)
+	}
+}
+
+func TestEasedOvershootUnsigned(t *testing.T) {
+	tests := []struct {
+		name string
+		e    ease.Func
+		f    float64
+		want uint8
+	}{
+		{"InBack", ease.InBack, .1, 0},
+		{"OutBack", ease.OutBack, .9, 255},
+		{"InElastic", ease.InElastic, .9, 0},
+		{"OutElastic", ease.OutElastic, .1, 255},
+	}
+	for _, tc := range tests {
+		sp := NewEased[uint8](0, 255, tc.e)
+		if v := sp.Percentile(tc.f); v != tc.want {
+			t.Errorf("%s at %v was %v, expected %v", tc.name, tc.f, v, tc.want)
+		}
+		keys := NewKeyframes(Key[uint8]{At: 0, Value: 0, Ease: tc.e}, Key[uint8]{At: 1, Value: 255})
+		if v := keys.Percentile(tc.f); v != tc.want {
+			t.Errorf("keyframed %s at %v was %v, expected %v", tc.name, tc.f, v, tc.want)
+		}
+	}
+	if v := NewEased[int8](0, 127, ease.OutBack).Percentile(.8); v != 127 {
+		t.Errorf("int8 overshoot was %v, expected 127", v)
+	}
+	if v := NewEased[uint8](10, 100, ease.Linear).MulSpan(10).Percentile(1); v != 255 {
+		t.Errorf("multiplied past range gave %v, expected 255", v)
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	if v := fromFloat[uint64](1e30); v != math.MaxUint64-2047 {
+		t.Errorf("uint64 saturated to %v", v)
+	}
+	if v := fromFloat[int64](-1e30); v != math.MinInt64 {
+		t.Errorf("int64 saturated to %v", v)
+	}
+	if v := fromFloat[int16](40000); v != math.MaxInt16 {
+		t.Errorf("int16 saturated to %v", v)
+	}
+	if v := fromFloat[float32](-2.5); v != -2.5 {
+		t.Errorf("float32 converted to %v", v)
+	}
+}
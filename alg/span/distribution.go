@@ -0,0 +1,163 @@
+package span
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg/span/internal/random"
+)
+
+// normalBound is how many standard deviations from the mean a normal span extends.
+const normalBound = 3
+
+// NewNormal returns a span following a normal distribution with the given mean and
+// standard deviation, truncated at three standard deviations from the mean. Poll
+// favors values near the mean, Percentile follows the distribution's cumulative
+// curve, and Clamp limits values to the truncated range.
+func NewNormal[T Spanable](mean, stdDev T) Span[T] {
+	sd := math.Abs(float64(stdDev))
+	if sd == 0 {
+		return constant[T]{mean}
+	}
+	return normal[T]{
+		mean:   float64(mean),
+		stdDev: sd,
+		rng:    random.Rand(),
+	}
+}
+
+type normal[T Spanable] struct {
+	mean, stdDev float64
+	rng          *rand.Rand
+}
+
+func (n normal[T]) Poll() T {
+	for {
+		z := n.rng.NormFloat64()
+		if math.Abs(z) <= normalBound {
+			return fromFloat[T](n.mean + z*n.stdDev)
+		}
+	}
+}
+
+func (n normal[T]) MulSpan(i float64) Span[T] {
+	n.mean *= i
+	n.stdDev = math.Abs(n.stdDev * i)
+	return n
+}
+
+func (n normal[T]) Clamp(v T) T {
+	lo := fromFloat[T](n.mean - normalBound*n.stdDev)
+	hi := fromFloat[T](n.mean + normalBound*n.stdDev)
+	if v < lo {
+		return lo
+	} else if v > hi {
+		return hi
+	}
+	return v
+}
+
+func (n normal[T]) Percentile(f float64) T {
+	f = math.Max(0, math.Min(1, f))
+	// Map f onto the portion of the cumulative curve within the bounds.
+	lo := cdf(-normalBound)
+	p := lo + f*(cdf(normalBound)-lo)
+	z := math.Sqrt2 * math.Erfinv(2*p-1)
+	return fromFloat[T](n.mean + z*n.stdDev)
+}
+
+// cdf is the cumulative distribution function of the standard normal distribution.
+func cdf(z float64) float64 {
+	return (1 + math.Erf(z/math.Sqrt2)) / 2
+}
+
+// A Choice is a value with a weight, for a weighted span.
+type Choice[T any] struct {
+	Value  T
+	Weight float64
+}
+
+// NewWeighted returns a span of discrete values, where Poll returns each value
+// with a chance proportional to its weight. Percentile walks through the values
+// from smallest to largest, spending the share of percentiles each value's weight
+// earns. Clamp limits values to lie between the smallest and largest choices.
+// Choices without positive weight are ignored; with none left the span is a
+// constant zero.
+func NewWeighted[T Spanable](choices ...Choice[T]) Span[T] {
+	kept := make([]Choice[T], 0, len(choices))
+	for _, c := range choices {
+		if c.Weight > 0 {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		var zero T
+		return constant[T]{zero}
+	}
+	if len(kept) == 1 {
+		return constant[T]{kept[0].Value}
+	}
+	return newWeighted(kept, random.Rand())
+}
+
+func newWeighted[T Spanable](choices []Choice[T], rng *rand.Rand) weighted[T] {
+	sort.SliceStable(choices, func(i, j int) bool {
+		return choices[i].Value < choices[j].Value
+	})
+	w := weighted[T]{
+		values:     make([]T, len(choices)),
+		cumulative: make([]float64, len(choices)),
+		rng:        rng,
+	}
+	total := 0.0
+	for i, c := range choices {
+		total += c.Weight
+		w.values[i] = c.Value
+		w.cumulative[i] = total
+	}
+	for i := range w.cumulative {
+		w.cumulative[i] /= total
+	}
+	return w
+}
+
+type weighted[T Spanable] struct {
+	values []T
+	// cumulative holds the running share of weight up to and including each value.
+	cumulative []float64
+	rng        *rand.Rand
+}
+
+func (w weighted[T]) Poll() T {
+	return w.Percentile(w.rng.Float64())
+}
+
+func (w weighted[T]) MulSpan(i float64) Span[T] {
+	choices := make([]Choice[T], len(w.values))
+	last := 0.0
+	for j, v := range w.values {
+		choices[j] = Choice[T]{Value: fromFloat[T](float64(v) * i), Weight: w.cumulative[j] - last}
+		last = w.cumulative[j]
+	}
+	return newWeighted(choices, w.rng)
+}
+
+func (w weighted[T]) Clamp(v T) T {
+	if v < w.values[0] {
+		return w.values[0]
+	} else if last := w.values[len(w.values)-1]; v > last {
+		return last
+	}
+	return v
+}
+
+func (w weighted[T]) Percentile(f float64) T {
+	i := sort.Search(len(w.cumulative), func(i int) bool {
+		return w.cumulative[i] > f
+	})
+	if i == len(w.values) {
+		i--
+	}
+	return w.values[i]
+}
@@ -0,0 +1,88 @@
+package span
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormal(t *testing.T) {
+	if _, ok := NewNormal(5, 0).(constant[int]); !ok {
+		t.Fatalf("NewNormal with no deviation did not create constant")
+	}
+	sp := NewNormal(100.0, 10.0)
+	if v := sp.Percentile(.5); math.Abs(v-100) > 1e-9 {
+		t.Fatalf("median was %v", v)
+	}
+	if v := sp.Percentile(0); math.Abs(v-70) > 1e-6 {
+		t.Fatalf("minimum was %v, expected 70", v)
+	}
+	if v := sp.Percentile(1); math.Abs(v-130) > 1e-6 {
+		t.Fatalf("maximum was %v, expected 130", v)
+	}
+	if v := sp.Percentile(.8413); math.Abs(v-110) > .1 {
+		t.Fatalf("one deviation percentile was %v, expected near 110", v)
+	}
+	if sp.Clamp(0) != 70 || sp.Clamp(200) != 130 {
+		t.Fatalf("normal clamped incorrectly")
+	}
+	const n = 20000
+	sum, sumSq := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		v := sp.Poll()
+		if v < 70 || v > 130 {
+			t.Fatalf("poll out of range: %v", v)
+		}
+		sum += v
+		sumSq += v * v
+	}
+	mean := sum / n
+	sd := math.Sqrt(sumSq/n - mean*mean)
+	if math.Abs(mean-100) > .5 || math.Abs(sd-10) > .5 {
+		t.Fatalf("polled mean %v and deviation %v, expected 100 and about 10", mean, sd)
+	}
+	if v := NewNormal[uint8](5, 10).Clamp(0); v != 0 {
+		t.Fatalf("unsigned normal clamped to %v", v)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	if v := NewWeighted(Choice[int]{Value: 3, Weight: 0}).Poll(); v != 0 {
+		t.Fatalf("weightless span polled %v", v)
+	}
+	sp := NewWeighted(
+		Choice[int]{Value: 30, Weight: 1},
+		Choice[int]{Value: 10, Weight: 2},
+		Choice[int]{Value: 20, Weight: 1},
+		Choice[int]{Value: 99, Weight: -1},
+	)
+	tests := []struct {
+		f    float64
+		want int
+	}{
+		{0, 10},
+		{.49, 10},
+		{.5, 20},
+		{.74, 20},
+		{.75, 30},
+		{1, 30},
+	}
+	for _, tc := range tests {
+		if v := sp.Percentile(tc.f); v != tc.want {
+			t.Errorf("percentile %v was %v, expected %v", tc.f, v, tc.want)
+		}
+	}
+	counts := map[int]int{}
+	const n = 20000
+	for i := 0; i < n; i++ {
+		counts[sp.Poll()]++
+	}
+	if len(counts) != 3 || math.Abs(float64(counts[10])/n-.5) > .02 {
+		t.Fatalf("unexpected poll distribution %v", counts)
+	}
+	if sp.Clamp(0) != 10 || sp.Clamp(50) != 30 || sp.Clamp(15) != 15 {
+		t.Fatalf("weighted clamped incorrectly")
+	}
+	if v := sp.MulSpan(2).Percentile(.6); v != 40 {
+		t.Fatalf("multiplied percentile was %v, expected 40", v)
+	}
+}
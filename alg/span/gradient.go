@@ -0,0 +1,268 @@
+package span
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/oakmound/oak/v4/alg/ease"
+	"github.com/oakmound/oak/v4/alg/span/internal/random"
+)
+
+// A ColorSpace determines how colors are interpolated.
+type ColorSpace int
+
+// ColorSpaces
+const (
+	// RGB interpolates red, green, blue and alpha channels directly, as
+	// NewLinearColor does.
+	RGB ColorSpace = iota
+	// HSV interpolates hue, saturation and value, taking the shorter way around the
+	// hue wheel.
+	HSV
+	// OKLab interpolates in a perceptual color space, so lightness and hue change
+	// evenly through the span.
+	OKLab
+)
+
+// NewHSVColor returns a span between two colors, interpolated in HSV space.
+func NewHSVColor(minColor, maxColor color.Color) Span[color.Color] {
+	return NewGradient(HSV, Key[color.Color]{At: 0, Value: minColor}, Key[color.Color]{At: 1, Value: maxColor})
+}
+
+// NewOKLabColor returns a span between two colors, interpolated in OKLab space.
+func NewOKLabColor(minColor, maxColor color.Color) Span[color.Color] {
+	return NewGradient(OKLab, Key[color.Color]{At: 0, Value: minColor}, Key[color.Color]{At: 1, Value: maxColor})
+}
+
+// NewGradient returns a span passing through each key's color at that key's
+// percentile, interpolating between them in the given color space. Poll picks a
+// percentile uniformly. Clamp limits each component of a color, in the gradient's
+// color space, to the range its keys cover; hue is not clamped. With no keys, the
+// gradient is transparent.
+func NewGradient(space ColorSpace, keys ...Key[color.Color]) Span[color.Color] {
+	if len(keys) == 0 {
+		keys = []Key[color.Color]{{Value: color.RGBA{}}}
+	}
+	keys = sortKeys(keys)
+	g := gradient{
+		space: space,
+		keys:  make([]Key[[4]float64], len(keys)),
+		rng:   random.Rand(),
+	}
+	for i, k := range keys {
+		g.keys[i] = Key[[4]float64]{At: k.At, Value: g.to(k.Value), Ease: k.Ease}
+	}
+	return g
+}
+
+type gradient struct {
+	space ColorSpace
+	keys  []Key[[4]float64]
+	rng   *rand.Rand
+}
+
+func (g gradient) Poll() color.Color {
+	return g.Percentile(g.rng.Float64())
+}
+
+// MulSpan multiplies every channel of each key's color, as NewLinearColor's
+// MulSpan does.
+func (g gradient) MulSpan(i float64) Span[color.Color] {
+	keys := make([]Key[[4]float64], len(g.keys))
+	for j, k := range g.keys {
+		r, gr, b, a := g.from(k.Value).RGBA()
+		mul := func(c uint32) uint16 {
+			return uint16(math.Max(0, math.Min(65535, float64(c)*i)))
+		}
+		k.Value = g.to(color.RGBA64{mul(r), mul(gr), mul(b), mul(a)})
+		keys[j] = k
+	}
+	g.keys = keys
+	return g
+}
+
+func (g gradient) Clamp(c color.Color) color.Color {
+	v := g.to(c)
+	for ch := range v {
+		if g.space == HSV && ch == 0 {
+			continue
+		}
+		lo, hi := g.keys[0].Value[ch], g.keys[0].Value[ch]
+		for _, k := range g.keys[1:] {
+			lo = math.Min(lo, k.Value[ch])
+			hi = math.Max(hi, k.Value[ch])
+		}
+		v[ch] = math.Max(lo, math.Min(hi, v[ch]))
+	}
+	return g.from(v)
+}
+
+func (g gradient) Percentile(f float64) color.Color {
+	a, b, u := segment(g.keys, f)
+	var v [4]float64
+	for ch := range v {
+		v[ch] = ease.Lerp(a.Value[ch], b.Value[ch], u)
+	}
+	if g.space == HSV {
+		v[0] = lerpHue(a.Value, b.Value, u)
+	}
+	return g.from(v)
+}
+
+// to converts a color into this gradient's color space.
+func (g gradient) to(c color.Color) [4]float64 {
+	switch g.space {
+	case HSV:
+		return toHSV(c)
+	case OKLab:
+		return toOKLab(c)
+	default:
+		r, gr, b, a := c.RGBA()
+		return [4]float64{float64(r), float64(gr), float64(b), float64(a)}
+	}
+}
+
+// from converts a color out of this gradient's color space.
+func (g gradient) from(v [4]float64) color.Color {
+	switch g.space {
+	case HSV:
+		return fromHSV(v)
+	case OKLab:
+		return fromOKLab(v)
+	default:
+		return color.RGBA64{channel16(v[0]), channel16(v[1]), channel16(v[2]), channel16(v[3])}
+	}
+}
+
+func channel16(c float64) uint16 {
+	return uint16(math.Round(math.Max(0, math.Min(65535, c))))
+}
+
+// unit returns the non-premultiplied channels of c, scaled from 0 to 1.
+func unit(c color.Color) (r, g, b, a float64) {
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return float64(n.R) / 65535, float64(n.G) / 65535, float64(n.B) / 65535, float64(n.A) / 65535
+}
+
+func fromUnit(r, g, b, a float64) color.Color {
+	return color.NRGBA64{channel16(r * 65535), channel16(g * 65535), channel16(b * 65535), channel16(a * 65535)}
+}
+
+// toHSV returns hue in degrees, then saturation, value and alpha from 0 to 1.
+func toHSV(c color.Color) [4]float64 {
+	r, g, b, a := unit(c)
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	d := max - min
+	h, s := 0.0, 0.0
+	if max > 0 {
+		s = d / max
+	}
+	if d > 0 {
+		switch max {
+		case r:
+			h = math.Mod((g-b)/d, 6)
+		case g:
+			h = (b-r)/d + 2
+		default:
+			h = (r-g)/d + 4
+		}
+		h *= 60
+		if h < 0 {
+			h += 360
+		}
+	}
+	return [4]float64{h, s, max, a}
+}
+
+func fromHSV(v [4]float64) color.Color {
+	h, s, val := math.Mod(v[0], 360), v[1], v[2]
+	if h < 0 {
+		h += 360
+	}
+	c := val * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := val - c
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return fromUnit(r+m, g+m, b+m, v[3])
+}
+
+// lerpHue interpolates hue the shorter way around the hue wheel. Colors without
+// saturation have no meaningful hue, and take the other color's.
+func lerpHue(a, b [4]float64, u float64) float64 {
+	ha, hb := a[0], b[0]
+	if a[1] == 0 {
+		ha = hb
+	} else if b[1] == 0 {
+		hb = ha
+	}
+	d := hb - ha
+	if d > 180 {
+		d -= 360
+	} else if d < -180 {
+		d += 360
+	}
+	h := math.Mod(ha+d*u, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func toLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func fromLinear(c float64) float64 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// toOKLab returns lightness, green-red and blue-yellow components, then alpha.
+func toOKLab(c color.Color) [4]float64 {
+	r, g, b, a := unit(c)
+	r, g, b = toLinear(r), toLinear(g), toLinear(b)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return [4]float64{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+		a,
+	}
+}
+
+func fromOKLab(v [4]float64) color.Color {
+	l := v[0] + 0.3963377774*v[1] + 0.2158037573*v[2]
+	m := v[0] - 0.1055613458*v[1] - 0.0638541728*v[2]
+	s := v[0] - 0.0894841775*v[1] - 1.2914855480*v[2]
+	l, m, s = l*l*l, m*m*m, s*s*s
+	r := 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g := -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b := -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	clamp := func(c float64) float64 {
+		return math.Max(0, math.Min(1, c))
+	}
+	return fromUnit(fromLinear(clamp(r)), fromLinear(clamp(g)), fromLinear(clamp(b)), v[3])
+}
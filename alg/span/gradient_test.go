@@ -0,0 +1,105 @@
+package span
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func closeColor(a, b color.Color, tolerance uint32) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	diff := func(x, y uint32) uint32 {
+		if x > y {
+			return x - y
+		}
+		return y - x
+	}
+	return diff(r1, r2) <= tolerance && diff(g1, g2) <= tolerance &&
+		diff(b1, b2) <= tolerance && diff(a1, a2) <= tolerance
+}
+
+func TestColorSpaceRoundTrip(t *testing.T) {
+	colors := []color.Color{
+		color.RGBA{255, 0, 0, 255},
+		color.RGBA{12, 200, 99, 255},
+		color.RGBA{0, 0, 0, 255},
+		color.RGBA{255, 255, 255, 255},
+		color.NRGBA{40, 80, 160, 128},
+	}
+	for _, c := range colors {
+		if got := fromHSV(toHSV(c)); !closeColor(got, c, 2) {
+			t.Errorf("hsv round trip of %v gave %v", c, got)
+		}
+		if got := fromOKLab(toOKLab(c)); !closeColor(got, c, 32) {
+			t.Errorf("oklab round trip of %v gave %v", c, got)
+		}
+	}
+	if lab := toOKLab(color.White); math.Abs(lab[0]-1) > 1e-4 || math.Abs(lab[1]) > 1e-4 || math.Abs(lab[2]) > 1e-4 {
+		t.Errorf("white in oklab was %v", lab)
+	}
+}
+
+func TestHSVColor(t *testing.T) {
+	// Red to blue the short way around the hue wheel passes through magenta.
+	sp := NewHSVColor(color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+	if got := sp.Percentile(.5); !closeColor(got, color.RGBA{255, 0, 255, 255}, 257) {
+		t.Errorf("hsv midpoint was %v", got)
+	}
+	// Grays take the other color's hue, rather than passing through red.
+	sp = NewHSVColor(color.RGBA{128, 128, 128, 255}, color.RGBA{0, 255, 0, 255})
+	if got := sp.Percentile(.5); !closeColor(got, color.RGBA{96, 191, 96, 255}, 257) {
+		t.Errorf("gray to green midpoint was %v", got)
+	}
+	if got := sp.Clamp(color.NRGBA{0, 255, 0, 100}); !closeColor(got, color.RGBA{0, 255, 0, 255}, 257) {
+		t.Errorf("clamped to %v", got)
+	}
+}
+
+func TestOKLabColor(t *testing.T) {
+	sp := NewOKLabColor(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
+	mid := sp.Percentile(.5)
+	r, g, b, _ := mid.RGBA()
+	if r != g || g != b {
+		t.Errorf("black to white midpoint %v was not gray", mid)
+	}
+	// OKLab lightness of one half is sRGB gray 99, darker than the rgb midpoint.
+	if r>>8 != 99 {
+		t.Errorf("oklab midpoint %v was not perceptually centered", mid)
+	}
+	if got := sp.Percentile(1); !closeColor(got, color.White, 257) {
+		t.Errorf("end was %v", got)
+	}
+	for i := 0; i < 100; i++ {
+		sp.Poll()
+	}
+}
+
+func TestGradient(t *testing.T) {
+	sp := NewGradient(RGB,
+		Key[color.Color]{At: 0, Value: color.RGBA{255, 0, 0, 255}},
+		Key[color.Color]{At: .5, Value: color.RGBA{0, 255, 0, 255}},
+		Key[color.Color]{At: 1, Value: color.RGBA{0, 0, 255, 255}},
+	)
+	tests := []struct {
+		f    float64
+		want color.Color
+	}{
+		{0, color.RGBA{255, 0, 0, 255}},
+		{.25, color.RGBA{128, 128, 0, 255}},
+		{.5, color.RGBA{0, 255, 0, 255}},
+		{.75, color.RGBA{0, 128, 128, 255}},
+		{1.5, color.RGBA{0, 0, 255, 255}},
+	}
+	for _, tc := range tests {
+		if got := sp.Percentile(tc.f); !closeColor(got, tc.want, 257) {
+			t.Errorf("percentile %v was %v, expected %v", tc.f, got, tc.want)
+		}
+	}
+	if got := sp.MulSpan(.5).Percentile(.5); !closeColor(got, color.RGBA{0, 128, 0, 128}, 257) {
+		t.Errorf("multiplied gradient was %v", got)
+	}
+	if got := NewGradient(OKLab).Poll(); !closeColor(got, color.RGBA{}, 0) {
+		t.Errorf("empty gradient polled %v", got)
+	}
+}
@@ -0,0 +1,50 @@
+package collision
+
+import "github.com/oakmound/oak/v4/alg/floatgeom"
+
+// A Broadphase is a spatial index of Spaces which a Tree uses to narrow down
+// which spaces could be colliding. The default Broadphase is an Rtree, which
+// performs well on mixed workloads. A SpatialHash may perform better when many
+// similarly sized spaces move every frame.
+//
+// Broadphases are not expected to be safe for concurrent use; Tree provides locking.
+type Broadphase interface {
+	// Insert adds a space to the index.
+	Insert(*Space)
+	// Delete removes a space from the index, returning whether it was present.
+	Delete(*Space) bool
+	// Update moves a space already in the index to a new location, returning
+	// whether it was present. If it was not, the space is not modified.
+	Update(s *Space, loc floatgeom.Rect3) bool
+	// SearchIntersect returns all spaces that intersect the given rectangle.
+	SearchIntersect(floatgeom.Rect3) []*Space
+	// NearestNeighbor returns the closest space to the given point, or nil.
+	NearestNeighbor(floatgeom.Point3) *Space
+	// NearestNeighbors returns up to k spaces closest to the given point,
+	// ordered by increasing distance.
+	NearestNeighbors(k int, p floatgeom.Point3) []*Space
+	// Size returns the number of spaces in the index.
+	Size() int
+	// Clear removes all spaces from the index.
+	Clear()
+}
+
+var (
+	_ Broadphase = &Rtree{}
+	_ Broadphase = &SpatialHash{}
+)
+
+// Update removes obj from the tree, changes its location, and reinserts it.
+func (tree *Rtree) Update(obj *Space, loc floatgeom.Rect3) bool {
+	if !tree.Delete(obj) {
+		return false
+	}
+	obj.Location = loc
+	tree.Insert(obj)
+	return true
+}
+
+// Clear resets the tree to be empty, retaining its branching factors.
+func (tree *Rtree) Clear() {
+	*tree = *newTree(tree.MinChildren, tree.MaxChildren)
+}
@@ -0,0 +1,202 @@
+package collision
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A Manifold describes how two overlapping spaces touch.
+type Manifold struct {
+	// Normal is the unit direction the first space would need to move in
+	// to stop overlapping the second space.
+	Normal floatgeom.Point2
+	// Depth is how far the first space would need to move along Normal to
+	// stop overlapping the second space.
+	Depth float64
+	// Points are the corners of the overlapping region on the edge the spaces
+	// are touching along.
+	Points []floatgeom.Point2
+}
+
+// NewManifold calculates the manifold of a overlapping b. If the two spaces are
+// not overlapping, the returned manifold will have zero depth and no points.
+func NewManifold(a, b *Space) Manifold {
+	ar := a.Location.ProjectZ()
+	br := b.Location.ProjectZ()
+	if !ar.Intersects(br) {
+		return Manifold{}
+	}
+	overlap := floatgeom.Rect2{
+		Min: ar.Min.GreaterOf(br.Min),
+		Max: ar.Max.LesserOf(br.Max),
+	}
+	ac := ar.Center()
+	bc := br.Center()
+	m := Manifold{}
+	if overlap.W() < overlap.H() {
+		m.Depth = overlap.W()
+		x := overlap.Max.X()
+		m.Normal = floatgeom.Point2{-1, 0}
+		if ac.X() > bc.X() {
+			x = overlap.Min.X()
+			m.Normal = floatgeom.Point2{1, 0}
+		}
+		m.Points = []floatgeom.Point2{{x, overlap.Min.Y()}, {x, overlap.Max.Y()}}
+	} else {
+		m.Depth = overlap.H()
+		y := overlap.Max.Y()
+		m.Normal = floatgeom.Point2{0, -1}
+		if ac.Y() > bc.Y() {
+			y = overlap.Min.Y()
+			m.Normal = floatgeom.Point2{0, 1}
+		}
+		m.Points = []floatgeom.Point2{{overlap.Min.X(), y}, {overlap.Max.X(), y}}
+	}
+	return m
+}
+
+// A Contact is sent to ContactEnter, ContactStay, and ContactExit bindings.
+// Space is the tracked space belonging to the triggered caller, and Other is the
+// space it is touching.
+type Contact struct {
+	Space *Space
+	Other *Space
+	// Manifold is the manifold of Space overlapping Other. On ContactExit this
+	// will be the last manifold observed while the spaces were touching.
+	Manifold
+}
+
+// ContactEnter/Stay/Exit: triggered on the caller of a tracked space, on the
+// first frame it touches another space, on every following frame it keeps
+// touching that space, and on the first frame it stops touching that space.
+var (
+	ContactEnter = event.RegisterEvent[Contact]()
+	ContactStay  = event.RegisterEvent[Contact]()
+	ContactExit  = event.RegisterEvent[Contact]()
+)
+
+type contactKey struct {
+	a, b *Space
+}
+
+// A ContactTracker tracks which spaces a set of spaces are touching in a Tree,
+// and triggers ContactEnter, ContactStay, and ContactExit events on the callers
+// of those tracked spaces. Spaces without a CID are tracked, but have no caller
+// to trigger events against; their contacts can still be queried.
+type ContactTracker struct {
+	Tree    *Tree
+	Handler event.Handler
+
+	mu       sync.Mutex
+	tracked  map[*Space]struct{}
+	contacts map[contactKey]Contact
+}
+
+// NewContactTracker creates a contact tracker on the given tree and handler. If tree
+// is nil, the DefaultTree will be used. If handler is nil, the DefaultBus will be used.
+func NewContactTracker(tree *Tree, handler event.Handler) *ContactTracker {
+	if tree == nil {
+		tree = DefaultTree
+	}
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	return &ContactTracker{
+		Tree:     tree,
+		Handler:  handler,
+		tracked:  make(map[*Space]struct{}),
+		contacts: make(map[contactKey]Contact),
+	}
+}
+
+// Track starts tracking contacts for the given spaces.
+func (ct *ContactTracker) Track(sps ...*Space) {
+	ct.mu.Lock()
+	for _, s := range sps {
+		if s != nil {
+			ct.tracked[s] = struct{}{}
+		}
+	}
+	ct.mu.Unlock()
+}
+
+// Untrack stops tracking contacts for the given spaces. Contacts these spaces had
+// are forgotten without triggering ContactExit.
+func (ct *ContactTracker) Untrack(sps ...*Space) {
+	ct.mu.Lock()
+	for _, s := range sps {
+		delete(ct.tracked, s)
+	}
+	for k := range ct.contacts {
+		if _, ok := ct.tracked[k.a]; !ok {
+			delete(ct.contacts, k)
+		}
+	}
+	ct.mu.Unlock()
+}
+
+// Contacts returns the contacts the given tracked space had as of the last Update.
+func (ct *ContactTracker) Contacts(s *Space) []Contact {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	out := []Contact{}
+	for k, c := range ct.contacts {
+		if k.a == s {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Update checks all tracked spaces for new, continuing, and ended contacts and
+// triggers the appropriate events.
+func (ct *ContactTracker) Update() {
+	ct.mu.Lock()
+	next := make(map[contactKey]Contact, len(ct.contacts))
+	type trigger struct {
+		ev event.EventID[Contact]
+		c  Contact
+	}
+	var triggers []trigger
+	for s := range ct.tracked {
+		for _, other := range ct.Tree.Hits(s) {
+			k := contactKey{s, other}
+			c := Contact{
+				Space:    s,
+				Other:    other,
+				Manifold: NewManifold(s, other),
+			}
+			next[k] = c
+			if _, ok := ct.contacts[k]; ok {
+				triggers = append(triggers, trigger{ContactStay, c})
+			} else {
+				triggers = append(triggers, trigger{ContactEnter, c})
+			}
+		}
+	}
+	for k, c := range ct.contacts {
+		if _, ok := next[k]; !ok {
+			triggers = append(triggers, trigger{ContactExit, c})
+		}
+	}
+	ct.contacts = next
+	ct.mu.Unlock()
+
+	for _, t := range triggers {
+		if t.c.Space.CID == event.Global {
+			continue
+		}
+		event.TriggerForCallerOn(ct.Handler, t.c.Space.CID, t.ev, t.c)
+	}
+}
+
+// Bind causes this tracker to Update at the start of every frame, until the
+// returned binding is unbound.
+func (ct *ContactTracker) Bind() event.Binding {
+	return event.GlobalBind(ct.Handler, event.Enter, func(event.EnterPayload) event.Response {
+		ct.Update()
+		return 0
+	})
+}
@@ -0,0 +1,115 @@
+package collision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/event"
+)
+
+func TestNewManifold(t *testing.T) {
+	a := NewUnassignedSpace(0, 0, 10, 10)
+	b := NewUnassignedSpace(8, 2, 10, 4)
+	m := NewManifold(a, b)
+	if m.Depth != 2 {
+		t.Fatalf("expected depth 2, got %v", m.Depth)
+	}
+	if m.Normal != (floatgeom.Point2{-1, 0}) {
+		t.Fatalf("expected left normal, got %v", m.Normal)
+	}
+	if len(m.Points) != 2 || m.Points[0] != (floatgeom.Point2{10, 2}) || m.Points[1] != (floatgeom.Point2{10, 6}) {
+		t.Fatalf("unexpected contact points %v", m.Points)
+	}
+	m = NewManifold(b, a)
+	if m.Normal != (floatgeom.Point2{1, 0}) {
+		t.Fatalf("expected right normal, got %v", m.Normal)
+	}
+	c := NewUnassignedSpace(2, 9, 4, 10)
+	m = NewManifold(a, c)
+	if m.Depth != 1 || m.Normal != (floatgeom.Point2{0, -1}) {
+		t.Fatalf("expected upward normal with depth 1, got %v", m)
+	}
+	if m := NewManifold(a, NewUnassignedSpace(50, 50, 1, 1)); m.Depth != 0 || len(m.Points) != 0 {
+		t.Fatalf("expected empty manifold for non-overlapping spaces, got %v", m)
+	}
+}
+
+type contactEntity struct {
+	event.CallerID
+}
+
+func (ce *contactEntity) CID() event.CallerID {
+	return ce.CallerID
+}
+
+func TestContactTracker(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	ce := &contactEntity{}
+	ce.CallerID = b.GetCallerMap().Register(ce)
+
+	tree := NewTree()
+	s := NewSpace(0, 0, 10, 10, ce.CallerID)
+	wall := NewLabeledSpace(8, 0, 10, 10, 1)
+	tree.Add(s)
+
+	evs := make(chan string, 10)
+	bind := func(name string, ev event.EventID[Contact]) event.Binding {
+		return event.Bind(b, ev, ce, func(_ *contactEntity, c Contact) event.Response {
+			if c.Other != wall {
+				t.Errorf("unexpected contact with %v", c.Other)
+			}
+			evs <- name
+			return 0
+		})
+	}
+	<-bind("enter", ContactEnter).Bound
+	<-bind("stay", ContactStay).Bound
+	<-bind("exit", ContactExit).Bound
+
+	ct := NewContactTracker(tree, b)
+	ct.Track(s, nil)
+
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-evs:
+			if got != want {
+				t.Fatalf("expected %v event, got %v", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v event", want)
+		}
+	}
+
+	ct.Update()
+	tree.Add(wall)
+	ct.Update()
+	expect("enter")
+	if cs := ct.Contacts(s); len(cs) != 1 || cs[0].Depth != 2 {
+		t.Fatalf("expected one contact with depth 2, got %v", cs)
+	}
+	ct.Update()
+	expect("stay")
+	tree.Remove(wall)
+	ct.Update()
+	expect("exit")
+	if len(ct.Contacts(s)) != 0 {
+		t.Fatalf("expected no contacts after exit")
+	}
+	tree.Add(wall)
+	ct.Untrack(s)
+	ct.Update()
+	select {
+	case ev := <-evs:
+		t.Fatalf("untracked space received %v event", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNewContactTrackerDefaults(t *testing.T) {
+	ct := NewContactTracker(nil, nil)
+	if ct.Tree != DefaultTree || ct.Handler != event.DefaultBus {
+		t.Fatalf("contact tracker did not use default tree and bus")
+	}
+}
@@ -77,3 +77,18 @@ func WithoutLabels(ls ...Label) Filter {
 		return true
 	})
 }
+
+// WithLayers will only return spaces which belong to at least one of the
+// input layers
+func WithLayers(l Layer) Filter {
+	return With(func(s *Space) bool {
+		return s.Layer.Has(l)
+	})
+}
+
+// WithoutLayers will return no spaces which belong to any of the input layers
+func WithoutLayers(l Layer) Filter {
+	return Without(func(s *Space) bool {
+		return s.Layer.Has(l)
+	})
+}
@@ -0,0 +1,106 @@
+package collision
+
+import "math/bits"
+
+// A Layer is a bitmask of collision categories. A Space's Layer describes the
+// categories it belongs to, and its Mask describes the categories it is willing
+// to collide with.
+//
+// The zero Layer is special: a Space with no Layer is unlayered and is accepted
+// by every other Space, so code that never sets a Layer behaves as it always has.
+// An unlayered Space may still set a Mask to limit which layered Spaces it accepts,
+// such as a ray that should only hit one layer.
+type Layer uint32
+
+// AllLayers is a Layer with every category bit set.
+const AllLayers Layer = 1<<32 - 1
+
+// LayerN returns the Layer consisting of only the nth category bit. n must be
+// in the range [0, 32).
+func LayerN(n int) Layer {
+	return 1 << uint(n)
+}
+
+// Has returns whether l shares any category bit with l2.
+func (l Layer) Has(l2 Layer) bool {
+	return l&l2 != 0
+}
+
+// A LayerMatrix defines, per category bit, which other categories it may
+// collide with. Matrix interactions are symmetric. A new LayerMatrix allows every
+// category to interact with every other category.
+type LayerMatrix struct {
+	rows [32]Layer
+}
+
+// NewLayerMatrix returns a LayerMatrix in which all layers interact.
+func NewLayerMatrix() *LayerMatrix {
+	m := &LayerMatrix{}
+	for i := range m.rows {
+		m.rows[i] = AllLayers
+	}
+	return m
+}
+
+// Set enables or disables interaction between every category in a and every
+// category in b.
+func (m *LayerMatrix) Set(a, b Layer, interacts bool) {
+	forEachBit(a, func(i int) {
+		forEachBit(b, func(j int) {
+			if interacts {
+				m.rows[i] |= LayerN(j)
+				m.rows[j] |= LayerN(i)
+			} else {
+				m.rows[i] &^= LayerN(j)
+				m.rows[j] &^= LayerN(i)
+			}
+		})
+	})
+}
+
+// Interacts returns whether any category in a is allowed to interact with any
+// category in b.
+func (m *LayerMatrix) Interacts(a, b Layer) bool {
+	interacts := false
+	forEachBit(a, func(i int) {
+		if m.rows[i].Has(b) {
+			interacts = true
+		}
+	})
+	return interacts
+}
+
+func forEachBit(l Layer, fn func(int)) {
+	for l != 0 {
+		i := bits.TrailingZeros32(uint32(l))
+		fn(i)
+		l &^= LayerN(i)
+	}
+}
+
+// DefaultLayerMatrix is consulted by Trees which do not have their own Layers
+// matrix set. If it is nil, only Space masks are used to filter collisions.
+var DefaultLayerMatrix *LayerMatrix
+
+// Interacts reports whether two spaces are allowed to collide, given their
+// Layers and Masks and the provided matrix. Unlayered spaces are accepted by every
+// mask and are not subject to the matrix, but their own non-zero Mask still limits
+// which layered spaces they accept. A zero Mask accepts every layer. m may be nil.
+func Interacts(m *LayerMatrix, a, b *Space) bool {
+	if a.Mask != 0 && b.Layer != 0 && !a.Mask.Has(b.Layer) {
+		return false
+	}
+	if b.Mask != 0 && a.Layer != 0 && !b.Mask.Has(a.Layer) {
+		return false
+	}
+	if m != nil && a.Layer != 0 && b.Layer != 0 {
+		return m.Interacts(a.Layer, b.Layer)
+	}
+	return true
+}
+
+// CanCollide returns whether this space's layer and mask allow it to collide with
+// another space, ignoring any layer matrix.
+func (s *Space) CanCollide(other *Space) bool {
+	return Interacts(nil, s, other)
+}
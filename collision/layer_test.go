@@ -0,0 +1,100 @@
+package collision
+
+import "testing"
+
+const (
+	testPlayerLayer Layer = 1 << iota
+	testEnemyLayer
+	testBulletLayer
+)
+
+func TestLayerMatrix(t *testing.T) {
+	m := NewLayerMatrix()
+	if !m.Interacts(testPlayerLayer, testBulletLayer) {
+		t.Fatalf("new matrix should allow all interactions")
+	}
+	m.Set(testPlayerLayer|testEnemyLayer, testBulletLayer, false)
+	if m.Interacts(testBulletLayer, testPlayerLayer) {
+		t.Fatalf("matrix interactions should be symmetric")
+	}
+	if m.Interacts(testEnemyLayer, testBulletLayer) {
+		t.Fatalf("enemy should not interact with bullet")
+	}
+	if !m.Interacts(testPlayerLayer|testBulletLayer, testBulletLayer) {
+		t.Fatalf("bullet should still interact with bullet")
+	}
+	m.Set(testEnemyLayer, testBulletLayer, true)
+	if !m.Interacts(testEnemyLayer, testBulletLayer) {
+		t.Fatalf("enemy should interact with bullet after re-enabling")
+	}
+}
+
+func TestInteracts(t *testing.T) {
+	unlayered := NewSpace(0, 0, 1, 1, 0)
+	player := NewLayeredSpace(0, 0, 1, 1, testPlayerLayer, testEnemyLayer, 0)
+	enemy := NewLayeredSpace(0, 0, 1, 1, testEnemyLayer, 0, 0)
+	bullet := NewLayeredSpace(0, 0, 1, 1, testBulletLayer, testEnemyLayer, 0)
+	if !unlayered.CanCollide(bullet) || !bullet.CanCollide(unlayered) {
+		t.Fatalf("unlayered spaces should collide with everything")
+	}
+	if !player.CanCollide(enemy) || !enemy.CanCollide(player) {
+		t.Fatalf("player and enemy should collide")
+	}
+	if player.CanCollide(bullet) || bullet.CanCollide(player) {
+		t.Fatalf("player should not collide with bullet")
+	}
+	m := NewLayerMatrix()
+	m.Set(testBulletLayer, testEnemyLayer, false)
+	if Interacts(m, bullet, enemy) {
+		t.Fatalf("matrix should prevent bullet and enemy from colliding")
+	}
+	probe := &Space{Mask: testEnemyLayer}
+	if !probe.CanCollide(enemy) || !enemy.CanCollide(probe) {
+		t.Fatalf("mask only space should collide with its masked layer")
+	}
+	if probe.CanCollide(player) || player.CanCollide(probe) {
+		t.Fatalf("mask only space should not collide with other layers")
+	}
+	if !probe.CanCollide(unlayered) {
+		t.Fatalf("mask only space should still collide with unlayered spaces")
+	}
+	if !Interacts(m, probe, enemy) {
+		t.Fatalf("matrix should not apply to unlayered spaces")
+	}
+}
+
+func TestTreeLayers(t *testing.T) {
+	tree := NewTree()
+	team1 := NewLayeredSpace(0, 0, 10, 10, testPlayerLayer, 0, 1)
+	team2 := NewLayeredSpace(0, 0, 10, 10, testEnemyLayer, 0, 2)
+	tree.Add(team1, team2)
+
+	shot := NewLayeredSpace(5, 5, 1, 1, testBulletLayer, AllLayers&^testPlayerLayer, 3)
+	hits := tree.Hits(shot)
+	if len(hits) != 1 || hits[0] != team2 {
+		t.Fatalf("expected shot to only hit team2, got %v", hits)
+	}
+	if len(tree.Hit(shot)) != 1 {
+		t.Fatalf("expected Hit to respect masks")
+	}
+
+	tree.Layers = NewLayerMatrix()
+	tree.Layers.Set(testBulletLayer, testEnemyLayer, false)
+	if len(tree.Hits(shot)) != 0 {
+		t.Fatalf("expected tree matrix to prevent shot from hitting team2")
+	}
+	if len(tree.Hits(NewSpace(5, 5, 1, 1, 0))) != 2 {
+		t.Fatalf("unlayered spaces should hit every layer")
+	}
+	maskOnly := NewSpace(5, 5, 1, 1, 0)
+	maskOnly.Mask = testPlayerLayer
+	if hits := tree.Hits(maskOnly); len(hits) != 1 || hits[0] != team1 {
+		t.Fatalf("expected mask only space to only hit team1, got %v", hits)
+	}
+	if len(tree.Hit(NewSpace(0, 0, 10, 10, 0), WithLayers(testEnemyLayer))) != 1 {
+		t.Fatalf("WithLayers did not filter to team2")
+	}
+	if len(tree.Hit(NewSpace(0, 0, 10, 10, 0), WithoutLayers(testEnemyLayer))) != 1 {
+		t.Fatalf("WithoutLayers did not filter to team1")
+	}
+}
@@ -39,6 +39,11 @@ type Caster struct {
 	CastDistance float64
 	Tree         *collision.Tree
 	CenterPoints bool
+	// Layer and Mask act as the collision layer and mask of the cast ray.
+	// Spaces the ray is not allowed to interact with, per its Tree, will not
+	// be hit. By default rays are unlayered and can hit everything.
+	Layer collision.Layer
+	Mask  collision.Layer
 }
 
 // A CastOption represents a transformation to a ray caster.
@@ -68,6 +73,7 @@ func (c *Caster) CastTo(origin, target floatgeom.Point2) []collision.Point {
 func (c *Caster) Cast(origin, angle floatgeom.Point2) []collision.Point {
 	points := make([]collision.Point, 0)
 	resultHash := make(map[*collision.Space]bool)
+	probe := &collision.Space{Layer: c.Layer, Mask: c.Mask}
 
 	x := origin.X()
 	y := origin.Y()
@@ -87,6 +93,10 @@ func (c *Caster) Cast(origin, angle floatgeom.Point2) []collision.Point {
 			if _, ok := resultHash[next]; !ok {
 				resultHash[next] = true
 
+				if !c.Tree.Interacts(probe, next) {
+					continue hitLoop
+				}
+
 				for _, f := range c.Filters {
 					if !f(next) {
 						continue hitLoop
@@ -132,6 +142,21 @@ func Tree(t *collision.Tree) CastOption {
 	}
 }
 
+// Layer sets the collision layer of a Caster's rays.
+func Layer(l collision.Layer) CastOption {
+	return func(c *Caster) {
+		c.Layer = l
+	}
+}
+
+// Mask sets the collision mask of a Caster's rays, limiting which layers
+// they can hit.
+func Mask(m collision.Layer) CastOption {
+	return func(c *Caster) {
+		c.Mask = m
+	}
+}
+
 // CenterPoints sets whether a Caster should center its collision points that
 // form its ray. This is by default false, and is only significant if said
 // points' dimensions are significantly large.
@@ -229,3 +229,23 @@ func TestNewCasterDefaultTree(t *testing.T) {
 		t.Fatal("nil caster tree should have been set to default tree")
 	}
 }
+
+func TestCasterLayers(t *testing.T) {
+	tree := collision.NewTree()
+	ally := collision.NewLayeredSpace(10, 0, 5, 5, collision.LayerN(0), 0, 1)
+	foe := collision.NewLayeredSpace(20, 0, 5, 5, collision.LayerN(1), 0, 2)
+	tree.Add(ally, foe)
+	c := NewCaster(Tree(tree), Distance(40), Layer(collision.LayerN(2)), Mask(collision.LayerN(1)))
+	pts := c.Cast(floatgeom.Point2{0, 1}, floatgeom.Point2{1, 0})
+	if len(pts) != 1 || pts[0].Zone != foe {
+		t.Fatalf("expected masked ray to only hit foe, got %v", pts)
+	}
+	pts = NewCaster(Tree(tree), Distance(40)).Cast(floatgeom.Point2{0, 1}, floatgeom.Point2{1, 0})
+	if len(pts) != 2 {
+		t.Fatalf("expected unlayered ray to hit both spaces, got %v", pts)
+	}
+	pts = NewCaster(Tree(tree), Distance(40), Mask(collision.LayerN(1))).Cast(floatgeom.Point2{0, 1}, floatgeom.Point2{1, 0})
+	if len(pts) != 1 || pts[0].Zone != foe {
+		t.Fatalf("expected mask only ray to only hit foe, got %v", pts)
+	}
+}
@@ -0,0 +1,146 @@
+package collision
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A SensorMode controls how often a Sensor reports occupants.
+type SensorMode int
+
+// SensorModes
+const (
+	// SensorRepeating sensors trigger events every time a space enters or exits them.
+	SensorRepeating SensorMode = iota
+	// SensorOnce sensors trigger a single SensorEnter event and then stop updating.
+	SensorOnce
+)
+
+// SensorEnter/Exit: triggered on a sensor's caller when a space begins or stops
+// overlapping the sensor. The payload is the entering or exiting space.
+var (
+	SensorEnter = event.RegisterEvent[*Space]()
+	SensorExit  = event.RegisterEvent[*Space]()
+)
+
+// A Sensor is a trigger volume: a region which tracks the spaces of a Tree
+// that overlap it. A Sensor's space is never added to its tree, so it will
+// never be hit by, block, or push other spaces; it can be moved by setting
+// its Location directly.
+type Sensor struct {
+	Space *Space
+	Tree  *Tree
+	// Labels, if non-empty, restricts the spaces this sensor detects to
+	// those with one of these labels.
+	Labels []Label
+	Mode   SensorMode
+
+	mu        sync.Mutex
+	occupants map[*Space]struct{}
+	done      bool
+}
+
+// NewSensor creates a sensor detecting spaces in the given tree. If tree is nil,
+// the DefaultTree is used.
+func NewSensor(s *Space, tree *Tree, mode SensorMode, labels ...Label) *Sensor {
+	if tree == nil {
+		tree = DefaultTree
+	}
+	return &Sensor{
+		Space:     s,
+		Tree:      tree,
+		Labels:    labels,
+		Mode:      mode,
+		occupants: make(map[*Space]struct{}),
+	}
+}
+
+// Occupants returns the spaces overlapping this sensor as of its last Update.
+func (sn *Sensor) Occupants() []*Space {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	out := make([]*Space, 0, len(sn.occupants))
+	for s := range sn.occupants {
+		out = append(out, s)
+	}
+	return out
+}
+
+// Occupied returns whether the given space was overlapping this sensor as of
+// its last Update.
+func (sn *Sensor) Occupied(s *Space) bool {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	_, ok := sn.occupants[s]
+	return ok
+}
+
+// Done returns whether this sensor has stopped updating, i.e. whether it is a
+// SensorOnce sensor which has already triggered.
+func (sn *Sensor) Done() bool {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	return sn.done
+}
+
+func (sn *Sensor) detects(s *Space) bool {
+	return s.MatchesLabels(sn.Labels...)
+}
+
+// Update checks which spaces overlap this sensor and triggers SensorEnter and
+// SensorExit events on the given handler for the sensor's caller.
+func (sn *Sensor) Update(handler event.Handler) {
+	sn.mu.Lock()
+	if sn.done {
+		sn.mu.Unlock()
+		return
+	}
+	next := make(map[*Space]struct{}, len(sn.occupants))
+	var entered, exited []*Space
+	for _, s := range sn.Tree.Hits(sn.Space) {
+		if !sn.detects(s) {
+			continue
+		}
+		next[s] = struct{}{}
+		if _, ok := sn.occupants[s]; !ok {
+			entered = append(entered, s)
+		}
+	}
+	for s := range sn.occupants {
+		if _, ok := next[s]; !ok {
+			exited = append(exited, s)
+		}
+	}
+	sn.occupants = next
+	if sn.Mode == SensorOnce && len(entered) > 0 {
+		entered = entered[:1]
+		exited = nil
+		sn.done = true
+	}
+	sn.mu.Unlock()
+
+	cid := sn.Space.CID
+	if cid == event.Global {
+		return
+	}
+	for _, s := range entered {
+		event.TriggerForCallerOn(handler, cid, SensorEnter, s)
+	}
+	for _, s := range exited {
+		event.TriggerForCallerOn(handler, cid, SensorExit, s)
+	}
+}
+
+// Bind causes this sensor to Update at the start of every frame, until it is
+// Done. The binding is made against the sensor space's caller, so unbinding that
+// caller will also stop the sensor.
+func (sn *Sensor) Bind(handler event.Handler) event.Binding {
+	return handler.UnsafeBind(event.Enter.UnsafeEventID, sn.Space.CID, func(event.CallerID, event.Handler, interface{}) event.Response {
+		sn.Update(handler)
+		if sn.Done() {
+			return event.ResponseUnbindThisBinding
+		}
+		return 0
+	})
+}
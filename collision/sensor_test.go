@@ -0,0 +1,92 @@
+package collision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+func TestSensor(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	ce := &contactEntity{}
+	ce.CallerID = b.GetCallerMap().Register(ce)
+
+	tree := NewTree()
+	door := NewSensor(NewSpace(0, 0, 10, 10, ce.CallerID), tree, SensorRepeating, 1)
+	player := NewLabeledSpace(20, 0, 5, 5, 1)
+	enemy := NewLabeledSpace(2, 2, 5, 5, 2)
+	tree.Add(player, enemy)
+
+	entered := make(chan *Space, 10)
+	exited := make(chan *Space, 10)
+	<-event.Bind(b, SensorEnter, ce, func(_ *contactEntity, s *Space) event.Response {
+		entered <- s
+		return 0
+	}).Bound
+	<-event.Bind(b, SensorExit, ce, func(_ *contactEntity, s *Space) event.Response {
+		exited <- s
+		return 0
+	}).Bound
+
+	door.Update(b)
+	if len(door.Occupants()) != 0 {
+		t.Fatalf("sensor detected unlabeled space")
+	}
+	if len(tree.Hits(NewSpace(0, 0, 10, 10, 0))) != 1 {
+		t.Fatalf("sensor space should not be added to its tree")
+	}
+	tree.UpdateSpace(5, 5, 5, 5, player)
+	door.Update(b)
+	if !door.Occupied(player) {
+		t.Fatalf("sensor did not detect player")
+	}
+	select {
+	case s := <-entered:
+		if s != player {
+			t.Fatalf("expected player to enter, got %v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for enter")
+	}
+	tree.UpdateSpace(50, 5, 5, 5, player)
+	door.Update(b)
+	select {
+	case s := <-exited:
+		if s != player {
+			t.Fatalf("expected player to exit, got %v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for exit")
+	}
+	if door.Occupied(player) {
+		t.Fatalf("player still occupied sensor after exiting")
+	}
+}
+
+func TestSensorOnce(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	ce := &contactEntity{}
+	ce.CallerID = b.GetCallerMap().Register(ce)
+
+	tree := NewTree()
+	trap := NewSensor(NewSpace(0, 0, 10, 10, ce.CallerID), tree, SensorOnce)
+	entered := make(chan *Space, 10)
+	<-event.Bind(b, SensorEnter, ce, func(_ *contactEntity, s *Space) event.Response {
+		entered <- s
+		return 0
+	}).Bound
+	<-trap.Bind(b).Bound
+
+	tree.Add(NewUnassignedSpace(1, 1, 1, 1), NewUnassignedSpace(2, 2, 1, 1))
+	for i := 0; i < 3; i++ {
+		<-event.TriggerOn(b, event.Enter, event.EnterPayload{})
+	}
+	time.Sleep(50 * time.Millisecond)
+	if len(entered) != 1 {
+		t.Fatalf("expected exactly one enter event, got %d", len(entered))
+	}
+	if !trap.Done() {
+		t.Fatalf("sensor should be done after triggering once")
+	}
+}
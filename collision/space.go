@@ -27,6 +27,12 @@ type Space struct {
 	// Type represents which ID space the above ID
 	// corresponds to.
 	Type int
+	// Layer is the set of collision categories this space belongs to.
+	// See Layer for how unlayered spaces are treated.
+	Layer Layer
+	// Mask is the set of collision categories this space will collide with.
+	// A zero Mask collides with all layers.
+	Mask Layer
 }
 
 // Bounds satisfies the rtreego.Spatial interface.
@@ -89,6 +95,22 @@ func (s *Space) Below(other *Space) float64 {
 	return s.Y() - other.Y()
 }
 
+// HasLabel returns whether this space's label is one of the given labels.
+func (s *Space) HasLabel(ls ...Label) bool {
+	for _, l := range ls {
+		if s.Label == l {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesLabels returns whether this space's label is one of the given labels, or
+// true if no labels are given.
+func (s *Space) MatchesLabels(ls ...Label) bool {
+	return len(ls) == 0 || s.HasLabel(ls...)
+}
+
 // Contains returns whether this space contains another
 func (s *Space) Contains(other *Space) bool {
 	//You contain another space if it is fully inside your space
@@ -184,7 +206,10 @@ func (s *Space) SubtractRect(x2, y2, w2, h2 float64) []*Space {
 
 	for _, r := range rects {
 		if r[2] > 0 && r[3] > 0 {
-			spaces = append(spaces, NewFullSpace(r[0], r[1], r[2], r[3], s.Label, s.CID))
+			sp := NewFullSpace(r[0], r[1], r[2], r[3], s.Label, s.CID)
+			sp.Layer = s.Layer
+			sp.Mask = s.Mask
+			spaces = append(spaces, sp)
 		}
 	}
 
@@ -215,10 +240,10 @@ func NewLabeledSpace(x, y, w, h float64, l Label) *Space {
 func NewFullSpace(x, y, w, h float64, l Label, cID event.CallerID) *Space {
 	rect := NewRect(x, y, w, h)
 	return &Space{
-		rect,
-		l,
-		cID,
-		IDTypeCID,
+		Location: rect,
+		Label:    l,
+		CID:      cID,
+		Type:     IDTypeCID,
 	}
 }
 
@@ -230,10 +255,10 @@ func NewRect2Space(rect floatgeom.Rect2, cID event.CallerID) *Space {
 // NewRectSpace creates a colliison space with the specified 3D rectangle
 func NewRectSpace(rect floatgeom.Rect3, l Label, cID event.CallerID) *Space {
 	return &Space{
-		rect,
-		l,
-		cID,
-		IDTypeCID,
+		Location: rect,
+		Label:    l,
+		CID:      cID,
+		Type:     IDTypeCID,
 	}
 }
 
@@ -255,6 +280,15 @@ func NewRect(x, y, w, h float64) floatgeom.Rect3 {
 	return floatgeom.NewRect3WH(x, y, 0, w, h, 1)
 }
 
+// NewLayeredSpace returns a space with an associated caller id, collision layer
+// and collision mask.
+func NewLayeredSpace(x, y, w, h float64, layer, mask Layer, cID event.CallerID) *Space {
+	sp := NewSpace(x, y, w, h, cID)
+	sp.Layer = layer
+	sp.Mask = mask
+	return sp
+}
+
 // SetZLayer sets a space's z layer.
 func (s *Space) SetZLayer(z float64) {
 	s.Location.Min[2] = z
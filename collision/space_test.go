@@ -134,3 +134,13 @@ func TestNewRect2Space(t *testing.T) {
 		t.Fatalf("mismatched H: %v vs %v", s1.H(), s2.H())
 	}
 }
+
+func TestSpaceLabels(t *testing.T) {
+	s := NewLabeledSpace(0, 0, 1, 1, 2)
+	if !s.HasLabel(1, 2) || s.HasLabel(1, 3) || s.HasLabel() {
+		t.Fatalf("HasLabel did not match the space's label")
+	}
+	if !s.MatchesLabels() || !s.MatchesLabels(2) || s.MatchesLabels(3) {
+		t.Fatalf("MatchesLabels did not accept empty or matching labels")
+	}
+}
@@ -0,0 +1,272 @@
+package collision
+
+import (
+	"errors"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+// A SpatialHash is a Broadphase which buckets spaces into a uniform grid of
+// square cells. Moving a space within the cells it already covers only updates
+// its location, making it well suited to scenes with many small, similarly sized
+// spaces moving every frame. Spaces much larger than a cell will be stored in
+// many cells and should be avoided.
+type SpatialHash struct {
+	cellSize float64
+	cells    map[intgeom.Point2][]*Space
+	ranges   map[*Space]intgeom.Rect2
+}
+
+// NewSpatialHash creates a SpatialHash with the given cell size. A good cell size
+// is around twice the size of the typical space stored in the hash.
+func NewSpatialHash(cellSize float64) (*SpatialHash, error) {
+	if cellSize <= 0 || math.IsInf(cellSize, 0) || math.IsNaN(cellSize) {
+		return nil, errors.New("cellSize must be positive and finite")
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[intgeom.Point2][]*Space),
+		ranges:   make(map[*Space]intgeom.Rect2),
+	}, nil
+}
+
+// CellSize returns the width and height of this hash's cells.
+func (sh *SpatialHash) CellSize() float64 {
+	return sh.cellSize
+}
+
+func (sh *SpatialHash) cell(x, y float64) intgeom.Point2 {
+	return intgeom.Point2{
+		int(math.Floor(x / sh.cellSize)),
+		int(math.Floor(y / sh.cellSize)),
+	}
+}
+
+// cellRange returns the inclusive range of cells that r covers.
+func (sh *SpatialHash) cellRange(r floatgeom.Rect3) intgeom.Rect2 {
+	return intgeom.Rect2{
+		Min: sh.cell(r.Min.X(), r.Min.Y()),
+		Max: sh.cell(r.Max.X(), r.Max.Y()),
+	}
+}
+
+// cellCount returns the number of cells r covers. It is computed in floating
+// point so that very large rectangles do not overflow.
+func (sh *SpatialHash) cellCount(r floatgeom.Rect3) float64 {
+	w := math.Floor(r.Max.X()/sh.cellSize) - math.Floor(r.Min.X()/sh.cellSize) + 1
+	h := math.Floor(r.Max.Y()/sh.cellSize) - math.Floor(r.Min.Y()/sh.cellSize) + 1
+	return w * h
+}
+
+// Size returns the number of spaces in the hash.
+func (sh *SpatialHash) Size() int {
+	return len(sh.ranges)
+}
+
+// Insert adds a space to the hash. Inserting a space already in the hash
+// updates its location.
+func (sh *SpatialHash) Insert(s *Space) {
+	if _, ok := sh.ranges[s]; ok {
+		sh.Update(s, s.Location)
+		return
+	}
+	cr := sh.cellRange(s.Location)
+	sh.ranges[s] = cr
+	sh.addToCells(s, cr)
+}
+
+func (sh *SpatialHash) addToCells(s *Space, cr intgeom.Rect2) {
+	for x := cr.Min.X(); x <= cr.Max.X(); x++ {
+		for y := cr.Min.Y(); y <= cr.Max.Y(); y++ {
+			k := intgeom.Point2{x, y}
+			sh.cells[k] = append(sh.cells[k], s)
+		}
+	}
+}
+
+func (sh *SpatialHash) removeFromCells(s *Space, cr intgeom.Rect2) {
+	for x := cr.Min.X(); x <= cr.Max.X(); x++ {
+		for y := cr.Min.Y(); y <= cr.Max.Y(); y++ {
+			k := intgeom.Point2{x, y}
+			cell := sh.cells[k]
+			for i, s2 := range cell {
+				if s2 == s {
+					last := len(cell) - 1
+					cell[i] = cell[last]
+					cell[last] = nil
+					cell = cell[:last]
+					break
+				}
+			}
+			if len(cell) == 0 {
+				delete(sh.cells, k)
+			} else {
+				sh.cells[k] = cell
+			}
+		}
+	}
+}
+
+// Delete removes a space from the hash, returning whether it was present.
+func (sh *SpatialHash) Delete(s *Space) bool {
+	cr, ok := sh.ranges[s]
+	if !ok {
+		return false
+	}
+	sh.removeFromCells(s, cr)
+	delete(sh.ranges, s)
+	return true
+}
+
+// Update moves a space in the hash to a new location. If the space covers the same
+// cells at its new location, no cells are modified.
+func (sh *SpatialHash) Update(s *Space, loc floatgeom.Rect3) bool {
+	cr, ok := sh.ranges[s]
+	if !ok {
+		return false
+	}
+	s.Location = loc
+	newCR := sh.cellRange(loc)
+	if newCR == cr {
+		return true
+	}
+	sh.removeFromCells(s, cr)
+	sh.ranges[s] = newCR
+	sh.addToCells(s, newCR)
+	return true
+}
+
+// SearchIntersect returns all spaces in the hash that intersect the given rectangle.
+// Queries covering more cells than the hash has occupied check every space
+// directly instead of walking each cell.
+func (sh *SpatialHash) SearchIntersect(bb floatgeom.Rect3) []*Space {
+	results := []*Space{}
+	if sh.cellCount(bb) > float64(len(sh.cells)) {
+		for s := range sh.ranges {
+			if s.Location.Intersects(bb) {
+				results = append(results, s)
+			}
+		}
+		return results
+	}
+	qr := sh.cellRange(bb)
+	for x := qr.Min.X(); x <= qr.Max.X(); x++ {
+		for y := qr.Min.Y(); y <= qr.Max.Y(); y++ {
+			for _, s := range sh.cells[intgeom.Point2{x, y}] {
+				// A space covering multiple cells is only considered in the first
+				// cell it shares with the query, so results are not duplicated.
+				sr := sh.ranges[s]
+				if x != maxInt(sr.Min.X(), qr.Min.X()) || y != maxInt(sr.Min.Y(), qr.Min.Y()) {
+					continue
+				}
+				if s.Location.Intersects(bb) {
+					results = append(results, s)
+				}
+			}
+		}
+	}
+	return results
+}
+
+// NearestNeighbor returns the closest space in the hash to the input point.
+func (sh *SpatialHash) NearestNeighbor(p floatgeom.Point3) *Space {
+	nearest := sh.NearestNeighbors(1, p)
+	if len(nearest) == 0 {
+		return nil
+	}
+	return nearest[0]
+}
+
+// NearestNeighbors returns the k nearest neighbors in the hash to the input point.
+// Cells are searched in rings of increasing distance from the point's cell.
+func (sh *SpatialHash) NearestNeighbors(k int, p floatgeom.Point3) []*Space {
+	if k <= 0 || len(sh.ranges) == 0 {
+		return []*Space{}
+	}
+	bounds := sh.occupiedBounds()
+	center := sh.cell(p.X(), p.Y())
+	maxRing := maxInt(
+		maxInt(absInt(center.X()-bounds.Min.X()), absInt(bounds.Max.X()-center.X())),
+		maxInt(absInt(center.Y()-bounds.Min.Y()), absInt(bounds.Max.Y()-center.Y())),
+	)
+
+	dists := make([]float64, 0, k)
+	nearest := make([]*Space, 0, k)
+	seen := make(map[*Space]struct{})
+	for ring := 0; ring <= maxRing; ring++ {
+		sh.forRing(center, ring, func(s *Space) {
+			if _, ok := seen[s]; ok {
+				return
+			}
+			seen[s] = struct{}{}
+			dists, nearest = insertNearest(k, dists, nearest, minDist(p, s.Location), s)
+		})
+		// Any space not yet seen is at least ring cells away from the point
+		if len(nearest) == k {
+			reach := float64(ring) * sh.cellSize
+			if dists[k-1] <= reach*reach {
+				break
+			}
+		}
+	}
+	return nearest
+}
+
+func (sh *SpatialHash) forRing(center intgeom.Point2, ring int, fn func(*Space)) {
+	visit := func(x, y int) {
+		for _, s := range sh.cells[intgeom.Point2{x, y}] {
+			fn(s)
+		}
+	}
+	if ring == 0 {
+		visit(center.X(), center.Y())
+		return
+	}
+	minX, maxX := center.X()-ring, center.X()+ring
+	minY, maxY := center.Y()-ring, center.Y()+ring
+	for x := minX; x <= maxX; x++ {
+		visit(x, minY)
+		visit(x, maxY)
+	}
+	for y := minY + 1; y < maxY; y++ {
+		visit(minX, y)
+		visit(maxX, y)
+	}
+}
+
+func (sh *SpatialHash) occupiedBounds() intgeom.Rect2 {
+	first := true
+	var bounds intgeom.Rect2
+	for k := range sh.cells {
+		if first {
+			bounds = intgeom.Rect2{Min: k, Max: k}
+			first = false
+			continue
+		}
+		bounds.Min = bounds.Min.LesserOf(k)
+		bounds.Max = bounds.Max.GreaterOf(k)
+	}
+	return bounds
+}
+
+// Clear removes all spaces from the hash.
+func (sh *SpatialHash) Clear() {
+	sh.cells = make(map[intgeom.Point2][]*Space)
+	sh.ranges = make(map[*Space]intgeom.Rect2)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,223 @@
+package collision
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+func TestNewSpatialHashInvalid(t *testing.T) {
+	if _, err := NewSpatialHash(0); err == nil {
+		t.Fatalf("spatial hash with zero cell size should have failed")
+	}
+	if _, err := NewSpatialHashTree(-1); err == nil {
+		t.Fatalf("spatial hash tree with negative cell size should have failed")
+	}
+}
+
+func TestTreeRtreeField(t *testing.T) {
+	if tree := NewTree(); tree.Rtree == nil || tree.Rtree != tree.Broadphase {
+		t.Fatalf("rtree backed tree should expose its Rtree")
+	}
+	tree, _ := NewSpatialHashTree(8)
+	if tree.Rtree != nil {
+		t.Fatalf("spatial hash tree should not have an Rtree")
+	}
+}
+
+func TestSpatialHashTreeScene(t *testing.T) {
+	tree, err := NewSpatialHashTree(8)
+	if err != nil {
+		t.Fatalf("unexpected error creating tree: %v", err)
+	}
+	s1 := NewFullSpace(0, 0, 10, 10, 1, 3)
+	s2 := NewFullSpace(10, 10, 20, 20, 2, 4)
+	tree.Add(s1, s2)
+	if tree.Size() != 2 {
+		t.Fatalf("tree with two additions did not have size 2")
+	}
+	if len(tree.Hits(NewSpace(5, 5, 1, 1, 0))) != 1 {
+		t.Fatalf("Hits did not collide with s1")
+	}
+	if len(tree.Hits(NewSpace(0, 0, 100, 100, 0))) != 2 {
+		t.Fatalf("Hits over multiple cells did not return each space once")
+	}
+	if tree.ShiftSpace(1, 1, s1) != nil {
+		t.Fatalf("shift space failed")
+	}
+	if len(tree.Hits(NewSpace(0, 0, 1, 1, 0))) != 0 {
+		t.Fatalf("hit s1 post shift")
+	}
+	if tree.UpdateSpace(50, 50, 5, 5, s2) != nil {
+		t.Fatalf("update space failed")
+	}
+	if tree.HitLabel(NewSpace(52, 52, 1, 1, 0), 2) == nil {
+		t.Fatalf("did not hit s2 post update")
+	}
+	if tree.HitLabel(NewSpace(15, 15, 1, 1, 0), 2) != nil {
+		t.Fatalf("hit s2 at old location")
+	}
+	if tree.UpdateSpace(0, 0, 1, 1, NewSpace(0, 0, 1, 1, 0)) == nil {
+		t.Fatalf("updating a space not in the tree should fail")
+	}
+	if tree.Remove(s2) != 1 {
+		t.Fatalf("remove space failed")
+	}
+	tree.Clear()
+	if tree.Size() != 0 {
+		t.Fatalf("tree not empty after clear")
+	}
+}
+
+// TestSpatialHashMatchesRtree checks that spatial hashes and rtrees agree on query
+// results for random workloads.
+func TestSpatialHashMatchesRtree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rt := newTree(defaultMinChildren, defaultMaxChildren)
+	sh, _ := NewSpatialHash(25)
+	spaces := make([]*Space, 500)
+	for i := range spaces {
+		spaces[i] = NewUnassignedSpace(rng.Float64()*1000-500, rng.Float64()*1000-500, rng.Float64()*40+1, rng.Float64()*40+1)
+		rt.Insert(spaces[i])
+		sh.Insert(spaces[i])
+	}
+	for i := 0; i < 200; i++ {
+		s := spaces[rng.Intn(len(spaces))]
+		loc := NewRect(s.X()+rng.Float64()*20-10, s.Y()+rng.Float64()*20-10, s.W(), s.H())
+		rt.Update(s, loc)
+		sh.Update(s, loc)
+	}
+	for i := 0; i < 100; i++ {
+		q := NewRect(rng.Float64()*1000-500, rng.Float64()*1000-500, rng.Float64()*200, rng.Float64()*200)
+		if !sameSpaces(rt.SearchIntersect(q), sh.SearchIntersect(q)) {
+			t.Fatalf("spatial hash and rtree disagreed on intersection query %v", q)
+		}
+		p := floatgeom.Point3{rng.Float64()*1200 - 600, rng.Float64()*1200 - 600, 0}
+		k := rng.Intn(10) + 1
+		rtNear := rt.NearestNeighbors(k, p)
+		shNear := sh.NearestNeighbors(k, p)
+		if len(rtNear) != len(shNear) {
+			t.Fatalf("expected %d neighbors, got %d", len(rtNear), len(shNear))
+		}
+		for j := range rtNear {
+			if minDist(p, rtNear[j].Location) != minDist(p, shNear[j].Location) {
+				t.Fatalf("spatial hash and rtree disagreed on neighbor %d of %v", j, p)
+			}
+		}
+	}
+	if sh.NearestNeighbor(floatgeom.Point3{}) == nil {
+		t.Fatalf("nearest neighbor in non-empty hash was nil")
+	}
+}
+
+func TestSpatialHashHugeQuery(t *testing.T) {
+	sh, _ := NewSpatialHash(1)
+	s1 := NewUnassignedSpace(0, 0, 5, 5)
+	s2 := NewUnassignedSpace(1e6, -1e6, 5, 5)
+	sh.Insert(s1)
+	sh.Insert(s2)
+	q := NewRect(-1e12, -1e12, 2e12, 2e12)
+	if !sameSpaces(sh.SearchIntersect(q), []*Space{s1, s2}) {
+		t.Fatalf("huge query did not return every space")
+	}
+	q = NewRect(-1e12, -1e12, 2e12, 1e12-1)
+	if !sameSpaces(sh.SearchIntersect(q), []*Space{s2}) {
+		t.Fatalf("huge query returned spaces outside of it")
+	}
+	q = floatgeom.NewRect3(math.Inf(-1), math.Inf(-1), 0, math.Inf(1), math.Inf(1), 1)
+	if !sameSpaces(sh.SearchIntersect(q), []*Space{s1, s2}) {
+		t.Fatalf("infinite query did not return every space")
+	}
+}
+
+func sameSpaces(a, b []*Space) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[*Space]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
+
+type broadphaseBench struct {
+	name string
+	new  func() Broadphase
+}
+
+var broadphaseBenches = []broadphaseBench{
+	{"Rtree", func() Broadphase { return newTree(defaultMinChildren, defaultMaxChildren) }},
+	{"SpatialHash", func() Broadphase {
+		sh, _ := NewSpatialHash(16)
+		return sh
+	}},
+}
+
+func benchSpaces(n int) []*Space {
+	rng := rand.New(rand.NewSource(0))
+	spaces := make([]*Space, n)
+	for i := range spaces {
+		spaces[i] = NewUnassignedSpace(rng.Float64()*2000, rng.Float64()*2000, 8, 8)
+	}
+	return spaces
+}
+
+func BenchmarkBroadphaseInsert(b *testing.B) {
+	spaces := benchSpaces(2000)
+	for _, bb := range broadphaseBenches {
+		b.Run(bb.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bp := bb.new()
+				for _, s := range spaces {
+					bp.Insert(s)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBroadphaseMove(b *testing.B) {
+	spaces := benchSpaces(2000)
+	for _, bb := range broadphaseBenches {
+		b.Run(bb.name, func(b *testing.B) {
+			bp := bb.new()
+			for _, s := range spaces {
+				bp.Insert(s)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, s := range spaces {
+					loc := s.Location
+					loc = loc.Shift(floatgeom.Point3{1, 1, 0})
+					bp.Update(s, loc)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBroadphaseQuery(b *testing.B) {
+	spaces := benchSpaces(2000)
+	for _, bb := range broadphaseBenches {
+		b.Run(bb.name, func(b *testing.B) {
+			bp := bb.new()
+			for _, s := range spaces {
+				bp.Insert(s)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, s := range spaces[:200] {
+					bp.SearchIntersect(s.Location)
+				}
+			}
+		})
+	}
+}
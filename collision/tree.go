@@ -10,8 +10,15 @@ import (
 
 // A Tree provides a space for managing collisions between rectangles
 type Tree struct {
-	*Rtree
+	Broadphase
+	// Rtree is this tree's Broadphase if it is an Rtree, and nil otherwise.
+	//
+	// Deprecated: Trees may use other broadphases; use Broadphase instead.
+	Rtree *Rtree
 	sync.Mutex
+	// Layers, if set, defines which collision layers interact within this tree.
+	// If nil, DefaultLayerMatrix is used.
+	Layers *LayerMatrix
 }
 
 const (
@@ -19,30 +26,45 @@ const (
 	defaultMaxChildren = 40
 )
 
-// NewTree returns a new collision Tree. defaultMinChildren and defaultMaxChildren
-// are used for node sizing.
+// NewTree returns a new collision Tree backed by an Rtree. defaultMinChildren
+// and defaultMaxChildren are used for node sizing.
 func NewTree() *Tree {
-	return &Tree{
-		Rtree: newTree(defaultMinChildren, defaultMaxChildren),
-		Mutex: sync.Mutex{},
-	}
+	return NewBroadphaseTree(newTree(defaultMinChildren, defaultMaxChildren))
 }
 
-// NewCustomTree returns a new collision Tree with custom node sizes.
-// minChildren must be less than maxChildren.
+// NewCustomTree returns a new collision Tree backed by an Rtree with custom
+// node sizes. minChildren must be less than maxChildren.
 func NewCustomTree(minChildren, maxChildren int) (*Tree, error) {
 	if minChildren > maxChildren {
 		return nil, errors.New("MaxChildren must exceed MinChildren")
 	}
+	return NewBroadphaseTree(newTree(minChildren, maxChildren)), nil
+}
+
+// NewSpatialHashTree returns a new collision Tree backed by a SpatialHash with
+// the given cell size. See SpatialHash.
+func NewSpatialHashTree(cellSize float64) (*Tree, error) {
+	sh, err := NewSpatialHash(cellSize)
+	if err != nil {
+		return nil, err
+	}
+	return NewBroadphaseTree(sh), nil
+}
+
+// NewBroadphaseTree returns a new collision Tree using the given Broadphase
+// to store its spaces.
+func NewBroadphaseTree(bp Broadphase) *Tree {
+	rt, _ := bp.(*Rtree)
 	return &Tree{
-		Rtree: newTree(minChildren, maxChildren),
-		Mutex: sync.Mutex{},
-	}, nil
+		Broadphase: bp,
+		Rtree:      rt,
+		Mutex:      sync.Mutex{},
+	}
 }
 
 // Clear resets a tree's contents to be empty
 func (t *Tree) Clear() {
-	t.Rtree = newTree(t.Rtree.MinChildren, t.Rtree.MaxChildren)
+	t.Broadphase.Clear()
 }
 
 // Add adds a set of spaces to the rtree
@@ -99,14 +121,11 @@ func (t *Tree) UpdateSpaceRect(rect floatgeom.Rect3, s *Space) error {
 		return oakerr.NilInput{InputName: "s"}
 	}
 	t.Lock()
-	deleted := t.Delete(s)
-	if !deleted {
-		t.Unlock()
+	updated := t.Update(s, rect)
+	t.Unlock()
+	if !updated {
 		return ErrNotExist
 	}
-	s.Location = rect
-	t.Insert(s)
-	t.Unlock()
 	return nil
 }
 
@@ -120,10 +139,42 @@ func (t *Tree) ShiftSpace(x, y float64, s *Space) error {
 	return t.UpdateSpace(x, y, s.GetW(), s.GetH(), s)
 }
 
+// LayerMatrix returns the layer matrix this tree uses to filter collisions,
+// which may be nil.
+func (t *Tree) LayerMatrix() *LayerMatrix {
+	if t.Layers != nil {
+		return t.Layers
+	}
+	return DefaultLayerMatrix
+}
+
+// Interacts returns whether the two spaces are allowed to collide in this tree,
+// according to their layers and masks and this tree's layer matrix.
+func (t *Tree) Interacts(a, b *Space) bool {
+	return Interacts(t.LayerMatrix(), a, b)
+}
+
+// filterLayers removes spaces from results which sp is not allowed to interact
+// with. results is modified in place.
+func (t *Tree) filterLayers(sp *Space, results []*Space) []*Space {
+	if sp.Layer == 0 && sp.Mask == 0 {
+		return results
+	}
+	m := t.LayerMatrix()
+	out := results[:0]
+	for _, v := range results {
+		if Interacts(m, sp, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // Hits returns the set of spaces which are colliding
 // with the passed in space. All spaces collide with
 // themselves, if they exist in the tree, but self-collision
-// will not be reported by Hits.
+// will not be reported by Hits. Spaces which the passed in space's
+// layer and mask do not interact with are not reported.
 func (t *Tree) Hits(sp *Space) []*Space {
 	results := t.SearchIntersect(sp.Bounds())
 	hitSelf := -1
@@ -136,9 +187,9 @@ func (t *Tree) Hits(sp *Space) []*Space {
 	}
 	if hitSelf != -1 {
 		out[hitSelf], out[len(out)-1] = out[len(out)-1], out[hitSelf]
-		return out[:len(out)-1]
+		out = out[:len(out)-1]
 	}
-	return out
+	return t.filterLayers(sp, out)
 }
 
 // HitLabel acts like Hits, but returns the first space within hits
@@ -146,7 +197,7 @@ func (t *Tree) Hits(sp *Space) []*Space {
 // space that is passed into it, if that space has a label in the set of
 // accepted labels.
 func (t *Tree) HitLabel(sp *Space, labels ...Label) *Space {
-	results := t.SearchIntersect(sp.Bounds())
+	results := t.filterLayers(sp, t.SearchIntersect(sp.Bounds()))
 	for _, v := range results {
 		for _, label := range labels {
 			if v != sp && v.Label == label {
@@ -160,7 +211,7 @@ func (t *Tree) HitLabel(sp *Space, labels ...Label) *Space {
 // Hit is an experimental new syntax that probably has performance hits
 // relative to Hits/HitLabel, see filters.go
 func (t *Tree) Hit(sp *Space, fs ...Filter) []*Space {
-	results := t.SearchIntersect(sp.Bounds())
+	results := t.filterLayers(sp, t.SearchIntersect(sp.Bounds()))
 	for _, f := range fs {
 		if len(results) == 0 {
 			return results
@@ -0,0 +1,7 @@
+// Package visibility computes what can be seen from a point, either as a polygon
+// against the edges of collision spaces or as a set of cells on a grid.
+//
+// Unlike ray.ConeCaster, which samples a fixed number of rays, a Viewer's
+// polygon has a vertex at each visible corner, so no gap between occluders is
+// missed however thin it is.
+package visibility
@@ -0,0 +1,103 @@
+package visibility
+
+import "github.com/oakmound/oak/v4/alg/intgeom"
+
+// octants transform coordinates from the first octant into each of the eight.
+var octants = [8][4]int{
+	{1, 0, 0, 1}, {0, 1, 1, 0}, {0, -1, 1, 0}, {-1, 0, 0, 1},
+	{-1, 0, 0, -1}, {0, -1, -1, 0}, {0, 1, -1, 0}, {1, 0, 0, -1},
+}
+
+// ShadowCast calls visit once for each grid cell visible from origin within
+// radius cells, including origin itself, using recursive shadowcasting. Opaque
+// cells are visible but block vision past them. opaque should return true for
+// cells outside of the grid.
+func ShadowCast(origin intgeom.Point2, radius int, opaque func(intgeom.Point2) bool, visit func(intgeom.Point2)) {
+	seen := map[intgeom.Point2]bool{origin: true}
+	visit(origin)
+	s := shadowCaster{
+		origin: origin,
+		radius: radius,
+		opaque: opaque,
+		visit: func(p intgeom.Point2) {
+			if !seen[p] {
+				seen[p] = true
+				visit(p)
+			}
+		},
+	}
+	for _, o := range octants {
+		s.cast(1, 1, 0, o)
+	}
+}
+
+// FOV returns whether each cell of a width by height grid is visible from origin
+// within radius cells. The returned slice is indexed by y, then x.
+func FOV(width, height int, origin intgeom.Point2, radius int, opaque func(intgeom.Point2) bool) [][]bool {
+	visible := make([][]bool, height)
+	for y := range visible {
+		visible[y] = make([]bool, width)
+	}
+	inBounds := func(p intgeom.Point2) bool {
+		return p.X() >= 0 && p.Y() >= 0 && p.X() < width && p.Y() < height
+	}
+	ShadowCast(origin, radius, func(p intgeom.Point2) bool {
+		return !inBounds(p) || opaque(p)
+	}, func(p intgeom.Point2) {
+		if inBounds(p) {
+			visible[p.Y()][p.X()] = true
+		}
+	})
+	return visible
+}
+
+type shadowCaster struct {
+	origin intgeom.Point2
+	radius int
+	opaque func(intgeom.Point2) bool
+	visit  func(intgeom.Point2)
+}
+
+// cast scans rows of an octant outward from row, between the start and end
+// slopes, recursing past each run of opaque cells.
+func (s shadowCaster) cast(row int, start, end float64, o [4]int) {
+	if start < end {
+		return
+	}
+	newStart := 0.0
+	for j := row; j <= s.radius; j++ {
+		blocked := false
+		for dx := -j; dx <= 0; dx++ {
+			dy := -j
+			left := (float64(dx) - .5) / (float64(dy) + .5)
+			right := (float64(dx) + .5) / (float64(dy) - .5)
+			if start < right {
+				continue
+			} else if end > left {
+				break
+			}
+			p := intgeom.Point2{
+				s.origin.X() + dx*o[0] + dy*o[1],
+				s.origin.Y() + dx*o[2] + dy*o[3],
+			}
+			if dx*dx+dy*dy <= s.radius*s.radius {
+				s.visit(p)
+			}
+			if blocked {
+				if s.opaque(p) {
+					newStart = right
+					continue
+				}
+				blocked = false
+				start = newStart
+			} else if s.opaque(p) && j < s.radius {
+				blocked = true
+				s.cast(j+1, start, left, o)
+				newStart = right
+			}
+		}
+		if blocked {
+			break
+		}
+	}
+}
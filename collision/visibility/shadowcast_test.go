@@ -0,0 +1,68 @@
+package visibility
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/intgeom"
+)
+
+func TestFOV(t *testing.T) {
+	grid := []string{
+		"..........",
+		"..........",
+		"....#.....",
+		"..........",
+		"..@.......",
+	}
+	var origin intgeom.Point2
+	for y, row := range grid {
+		if x := strings.IndexByte(row, '@'); x != -1 {
+			origin = intgeom.Point2{x, y}
+		}
+	}
+	visible := FOV(10, 5, origin, 20, func(p intgeom.Point2) bool {
+		return grid[p.Y()][p.X()] == '#'
+	})
+	want := []string{
+		"vvvvvv..vv",
+		"vvvvv.vvvv",
+		"vvvvvvvvvv",
+		"vvvvvvvvvv",
+		"vvvvvvvvvv",
+	}
+	var got []string
+	for _, row := range visible {
+		sb := strings.Builder{}
+		for _, v := range row {
+			if v {
+				sb.WriteByte('v')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		got = append(got, sb.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected\n%v\ngot\n%v", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestShadowCastRadius(t *testing.T) {
+	count := 0
+	seen := make(map[intgeom.Point2]bool)
+	ShadowCast(intgeom.Point2{0, 0}, 3, func(intgeom.Point2) bool { return false }, func(p intgeom.Point2) {
+		if seen[p] {
+			t.Fatalf("visited %v twice", p)
+		}
+		seen[p] = true
+		count++
+		if p.X()*p.X()+p.Y()*p.Y() > 9 {
+			t.Fatalf("visited %v outside radius", p)
+		}
+	})
+	// every cell within a circle of radius 3
+	if count != 29 {
+		t.Fatalf("expected 29 cells visited, got %v", count)
+	}
+}
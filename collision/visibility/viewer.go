@@ -0,0 +1,232 @@
+package visibility
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// sideAngle is how far to either side of each corner, in radians, a viewer looks
+// to find what is behind it.
+const sideAngle = 1e-5
+
+// A Viewer determines what is visible from a point. Spaces in its tree block
+// vision, as do its Occluders.
+type Viewer struct {
+	Tree *collision.Tree
+	// Filters are applied to spaces found in the tree; only spaces remaining after
+	// every filter block vision.
+	Filters []collision.Filter
+	// Occluders are additional polygons which block vision.
+	Occluders []floatgeom.Polygon2
+
+	// Range is how far the viewer can see.
+	Range float64
+	// Direction is the angle the viewer faces in degrees, and Spread is the width
+	// of its view in degrees, centered on Direction. A Spread of zero, or of 360
+	// or more, sees in every direction.
+	Direction float64
+	Spread    float64
+	// ArcStep is the greatest angle in degrees spanned by one edge of a polygon
+	// where it follows the edge of the viewer's range.
+	ArcStep float64
+}
+
+// NewViewer creates a viewer which sees up to rng in every direction, blocked by
+// all spaces in a tree. If tree is nil, the DefaultTree will be used.
+func NewViewer(tree *collision.Tree, rng float64) *Viewer {
+	if tree == nil {
+		tree = collision.DefaultTree
+	}
+	return &Viewer{
+		Tree:    tree,
+		Range:   rng,
+		ArcStep: 5,
+	}
+}
+
+type segment [2]floatgeom.Point2
+
+// segments returns the edges blocking vision around origin. Spaces containing
+// origin, such as the viewer's own, do not block vision, nor does except.
+func (v *Viewer) segments(origin floatgeom.Point2, except *collision.Space) []segment {
+	area := floatgeom.NewRect2WH(origin.X()-v.Range, origin.Y()-v.Range, v.Range*2, v.Range*2)
+	var segs []segment
+	addPolygon := func(pts ...floatgeom.Point2) {
+		for i, p := range pts {
+			segs = append(segs, segment{p, pts[(i+1)%len(pts)]})
+		}
+	}
+	if v.Tree != nil {
+		spaces := v.Tree.SearchIntersect(collision.NewRect(area.Min.X(), area.Min.Y(), area.W(), area.H()))
+		for _, f := range v.Filters {
+			spaces = f(spaces)
+		}
+		for _, s := range spaces {
+			x, y, w, h := s.X(), s.Y(), s.W(), s.H()
+			if s == except || (origin.X() > x && origin.X() < x+w && origin.Y() > y && origin.Y() < y+h) {
+				continue
+			}
+			addPolygon(
+				floatgeom.Point2{x, y},
+				floatgeom.Point2{x + w, y},
+				floatgeom.Point2{x + w, y + h},
+				floatgeom.Point2{x, y + h},
+			)
+		}
+	}
+	for _, o := range v.Occluders {
+		if o.Bounding.Intersects(area) {
+			addPolygon(o.Points...)
+		}
+	}
+	return segs
+}
+
+// full returns whether the viewer sees in every direction.
+func (v *Viewer) full() bool {
+	return v.Spread <= 0 || v.Spread >= 360
+}
+
+// cast returns the distance from origin along dir to the nearest segment, or the
+// viewer's range if no segment is closer.
+func (v *Viewer) cast(origin, dir floatgeom.Point2, segs []segment) float64 {
+	nearest := v.Range
+	for _, s := range segs {
+		e := s[1].Sub(s[0])
+		denom := dir.X()*e.Y() - dir.Y()*e.X()
+		if denom == 0 {
+			continue
+		}
+		ao := s[0].Sub(origin)
+		t := (ao.X()*e.Y() - ao.Y()*e.X()) / denom
+		u := (ao.X()*dir.Y() - ao.Y()*dir.X()) / denom
+		if t >= 0 && u >= 0 && u <= 1 && t < nearest {
+			nearest = t
+		}
+	}
+	return nearest
+}
+
+// Polygon returns the area visible from origin. If the viewer's Spread limits
+// its view, origin is one of the polygon's points.
+func (v *Viewer) Polygon(origin floatgeom.Point2) floatgeom.Polygon2 {
+	segs := v.segments(origin, nil)
+	start, span := 0.0, 2*math.Pi
+	if !v.full() {
+		start = (v.Direction - v.Spread/2) * alg.DegToRad
+		span = v.Spread * alg.DegToRad
+	}
+	steps := 3
+	if v.ArcStep > 0 {
+		steps = int(math.Max(math.Ceil(span*alg.RadToDeg/v.ArcStep), 1))
+	}
+	if v.full() && steps < 3 {
+		steps = 3
+	}
+	angles := make([]float64, 0, steps+1+len(segs)*3)
+	for i := 0; i <= steps; i++ {
+		if i == steps && v.full() {
+			break
+		}
+		angles = append(angles, span*float64(i)/float64(steps))
+	}
+	for _, s := range segs {
+		rel := math.Mod(s[0].Sub(origin).ToRadians()-start, 2*math.Pi)
+		if rel < 0 {
+			rel += 2 * math.Pi
+		}
+		for _, a := range []float64{rel - sideAngle, rel, rel + sideAngle} {
+			if a >= 0 && a <= span {
+				angles = append(angles, a)
+			}
+		}
+	}
+	sort.Float64s(angles)
+
+	var pts []floatgeom.Point2
+	if !v.full() {
+		pts = append(pts, origin)
+	}
+	for i, a := range angles {
+		if i > 0 && a == angles[i-1] {
+			continue
+		}
+		dir := floatgeom.RadianPoint(start + a)
+		p := origin.Add(dir.MulConst(v.cast(origin, dir, segs)))
+		if len(pts) != 0 && pts[len(pts)-1] == p {
+			continue
+		}
+		pts = append(pts, p)
+	}
+	for len(pts) < 3 {
+		pts = append(pts, origin)
+	}
+	return floatgeom.NewPolygon2(pts[0], pts[1], pts[2], pts[3:]...)
+}
+
+// Sees returns whether target is visible from origin.
+func (v *Viewer) Sees(origin, target floatgeom.Point2) bool {
+	return v.sees(origin, target, v.segments(origin, nil))
+}
+
+func (v *Viewer) sees(origin, target floatgeom.Point2, segs []segment) bool {
+	delta := target.Sub(origin)
+	dist := delta.Magnitude()
+	if dist > v.Range {
+		return false
+	}
+	if dist == 0 {
+		return true
+	}
+	if !v.full() {
+		diff := math.Mod(delta.ToAngle()-v.Direction, 360)
+		if diff > 180 {
+			diff -= 360
+		} else if diff < -180 {
+			diff += 360
+		}
+		if math.Abs(diff) > v.Spread/2 {
+			return false
+		}
+	}
+	return v.cast(origin, delta.DivConst(dist), segs) >= dist
+}
+
+// SeesSpace returns whether any part of a space is visible from origin: its
+// center, or a point just inside any of its corners. The space itself does not
+// block vision for this test.
+func (v *Viewer) SeesSpace(origin floatgeom.Point2, s *collision.Space) bool {
+	segs := v.segments(origin, s)
+	x, y, w, h := s.X(), s.Y(), s.W(), s.H()
+	center := floatgeom.Point2{x + w/2, y + h/2}
+	targets := []floatgeom.Point2{
+		center,
+		{x, y},
+		{x + w, y},
+		{x + w, y + h},
+		{x, y + h},
+	}
+	for i, t := range targets {
+		if i != 0 {
+			// pull corners in so that spaces touching this one do not hide it
+			t = t.Add(center.Sub(t).MulConst(.01))
+		}
+		if v.sees(origin, t, segs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Renderable returns the area visible from origin as a polygon filled with c.
+func (v *Viewer) Renderable(origin floatgeom.Point2, c color.Color) *render.Polygon {
+	poly := render.NewPolygon(v.Polygon(origin))
+	poly.Fill(c)
+	return poly
+}
@@ -0,0 +1,110 @@
+package visibility
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+func area(pg floatgeom.Polygon2) float64 {
+	a := 0.0
+	for i, p := range pg.Points {
+		q := pg.Points[(i+1)%len(pg.Points)]
+		a += p.X()*q.Y() - q.X()*p.Y()
+	}
+	return math.Abs(a / 2)
+}
+
+func TestViewerOpen(t *testing.T) {
+	v := NewViewer(collision.NewTree(), 100)
+	v.ArcStep = 1
+	pg := v.Polygon(floatgeom.Point2{0, 0})
+	if got, want := area(pg), math.Pi*100*100; math.Abs(got-want)/want > .001 {
+		t.Fatalf("expected area near %v, got %v", want, got)
+	}
+	if !v.Sees(floatgeom.Point2{0, 0}, floatgeom.Point2{-60, 60}) {
+		t.Fatal("expected point in range to be seen")
+	}
+	if v.Sees(floatgeom.Point2{0, 0}, floatgeom.Point2{100, 100}) {
+		t.Fatal("expected point out of range not to be seen")
+	}
+}
+
+func TestViewerThinGap(t *testing.T) {
+	tree := collision.NewTree()
+	// two walls with a narrow gap between them at y 50
+	tree.Add(
+		collision.NewUnassignedSpace(50, 0, 10, 49.9),
+		collision.NewUnassignedSpace(50, 50.1, 10, 50),
+	)
+	v := NewViewer(tree, 200)
+	origin := floatgeom.Point2{0, 50}
+	pg := v.Polygon(origin)
+	for _, p := range []floatgeom.Point2{{150, 50}, {30, 10}} {
+		if !pg.Contains(p.X(), p.Y()) || !v.Sees(origin, p) {
+			t.Fatalf("expected %v to be visible", p)
+		}
+	}
+	for _, p := range []floatgeom.Point2{{150, 20}, {100, 80}} {
+		if pg.Contains(p.X(), p.Y()) || v.Sees(origin, p) {
+			t.Fatalf("expected %v to be hidden", p)
+		}
+	}
+	v.Filters = []collision.Filter{collision.WithLabels(1)}
+	if !v.Sees(origin, floatgeom.Point2{150, 20}) {
+		t.Fatal("expected filtered spaces not to block vision")
+	}
+}
+
+func TestViewerCone(t *testing.T) {
+	v := NewViewer(collision.NewTree(), 100)
+	v.Direction = 90
+	v.Spread = 90
+	origin := floatgeom.Point2{0, 0}
+	pg := v.Polygon(origin)
+	if pg.Points[0] != origin {
+		t.Fatalf("expected cone polygon to start at origin, got %v", pg.Points)
+	}
+	if got, want := area(pg), math.Pi*100*100/4; math.Abs(got-want)/want > .01 {
+		t.Fatalf("expected area near %v, got %v", want, got)
+	}
+	if !v.Sees(origin, floatgeom.Point2{20, 50}) || !pg.Contains(20, 50) {
+		t.Fatal("expected point in cone to be seen")
+	}
+	if v.Sees(origin, floatgeom.Point2{50, 20}) || v.Sees(origin, floatgeom.Point2{0, -50}) {
+		t.Fatal("expected points outside cone not to be seen")
+	}
+}
+
+func TestViewerSeesSpace(t *testing.T) {
+	tree := collision.NewTree()
+	guard := collision.NewUnassignedSpace(-5, -5, 10, 10)
+	wall := collision.NewUnassignedSpace(40, -20, 10, 40)
+	hidden := collision.NewUnassignedSpace(60, -5, 10, 10)
+	peeking := collision.NewUnassignedSpace(60, 18, 10, 20)
+	tree.Add(guard, wall, hidden, peeking)
+	v := NewViewer(tree, 200)
+	origin := floatgeom.Point2{0, 0}
+	if v.SeesSpace(origin, hidden) {
+		t.Fatal("expected space behind wall to be hidden")
+	}
+	if !v.SeesSpace(origin, peeking) {
+		t.Fatal("expected space with its center hidden but a corner visible to be seen")
+	}
+	if r := v.Renderable(origin, color.RGBA{255, 255, 255, 100}); r == nil {
+		t.Fatal("expected renderable")
+	}
+}
+
+func TestViewerOccluders(t *testing.T) {
+	v := NewViewer(collision.NewTree(), 100)
+	v.Occluders = []floatgeom.Polygon2{floatgeom.NewPolygon2(
+		floatgeom.Point2{20, -10}, floatgeom.Point2{30, 0}, floatgeom.Point2{20, 10},
+	)}
+	if v.Sees(floatgeom.Point2{0, 0}, floatgeom.Point2{50, 0}) {
+		t.Fatal("expected occluder to block vision")
+	}
+}
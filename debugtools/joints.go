@@ -0,0 +1,130 @@
+package debugtools
+
+import (
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/physics/rigid"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// NewJoints creates a renderable that draws the joints of a physics world.
+func NewJoints(ctx *scene.Context, w *rigid.World) *Joints {
+	return &Joints{
+		World:        w,
+		Context:      ctx,
+		LayeredPoint: render.NewLayeredPoint(0, 0, -1),
+		AnchorColor:  color.RGBA{255, 255, 0, 255},
+		LineColor:    color.RGBA{0, 200, 255, 255},
+		LimitColor:   color.RGBA{255, 60, 60, 255},
+	}
+}
+
+// Joints draws the anchors of each joint in a world, a line between those anchors,
+// and the limits of rope, prismatic, and revolute joints.
+type Joints struct {
+	World *rigid.World
+	render.LayeredPoint
+	AnchorColor  color.RGBA
+	LineColor    color.RGBA
+	LimitColor   color.RGBA
+	DrawDisabled bool
+	Context      *scene.Context
+}
+
+// GetDims returns the total possible area to draw this on.
+func (j *Joints) GetDims() (int, int) {
+	bds := j.Context.Window.Bounds()
+	return bds.X(), bds.Y()
+}
+
+// Draw will draw the world's joints.
+func (j *Joints) Draw(buff draw.Image, xOff, yOff float64) {
+	if j.DrawDisabled {
+		return
+	}
+	vp := j.Context.Window.Viewport()
+	off := floatgeom.Point2{xOff - float64(vp.X()), yOff - float64(vp.Y())}
+	for _, jt := range j.World.Joints() {
+		pa, pb := jt.Anchors()
+		pa = pa.Add(off)
+		pb = pb.Add(off)
+		drawSegment(buff, pa, pb, j.LineColor)
+		switch jt := jt.(type) {
+		case *rigid.RopeJoint:
+			drawCircle(buff, pa, jt.MaxLength, j.LimitColor)
+		case *rigid.PrismaticJoint:
+			if jt.Limited {
+				lower := pa.Add(jt.Axis.MulConst(jt.Lower))
+				upper := pa.Add(jt.Axis.MulConst(jt.Upper))
+				drawSegment(buff, lower, upper, j.LimitColor)
+				perp := floatgeom.Point2{-jt.Axis.Y(), jt.Axis.X()}.MulConst(3)
+				drawSegment(buff, lower.Sub(perp), lower.Add(perp), j.LimitColor)
+				drawSegment(buff, upper.Sub(perp), upper.Add(perp), j.LimitColor)
+			}
+		case *rigid.RevoluteJoint:
+			if jt.Limited {
+				drawRevoluteLimits(buff, jt, pa, j.LimitColor)
+			}
+		}
+		drawMarker(buff, pa, j.AnchorColor)
+		drawMarker(buff, pb, j.AnchorColor)
+	}
+}
+
+// drawRevoluteLimits draws lines from a revolute joint's anchor through where the
+// center of its second body would be at each of the joint's limits.
+func drawRevoluteLimits(buff draw.Image, jt *rigid.RevoluteJoint, anchor floatgeom.Point2, c color.Color) {
+	a, b := jt.Bodies()
+	pa, pb := jt.Anchors()
+	var arm floatgeom.Point2
+	lower, upper := jt.Lower-jt.Angle(), jt.Upper-jt.Angle()
+	switch {
+	case b != nil:
+		arm = b.Center().Sub(pb)
+	case a != nil:
+		// the first body rotates opposite to the joint's angle
+		arm = a.Center().Sub(pa)
+		lower, upper = -lower, -upper
+	default:
+		return
+	}
+	if arm.Magnitude() < 10 {
+		arm = floatgeom.Point2{10, 0}.RotateRadians(arm.ToRadians())
+	}
+	drawSegment(buff, anchor, anchor.Add(arm.RotateRadians(lower)), c)
+	drawSegment(buff, anchor, anchor.Add(arm.RotateRadians(upper)), c)
+}
+
+func drawSegment(buff draw.Image, a, b floatgeom.Point2, c color.Color) {
+	d := b.Sub(a)
+	steps := int(math.Max(math.Abs(d.X()), math.Abs(d.Y())))
+	if steps == 0 {
+		buff.Set(int(a.X()), int(a.Y()), c)
+		return
+	}
+	step := d.DivConst(float64(steps))
+	for i := 0; i <= steps; i++ {
+		p := a.Add(step.MulConst(float64(i)))
+		buff.Set(int(p.X()), int(p.Y()), c)
+	}
+}
+
+func drawCircle(buff draw.Image, center floatgeom.Point2, radius float64, c color.Color) {
+	steps := int(2*math.Pi*radius) + 1
+	for i := 0; i < steps; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(steps)
+		buff.Set(int(center.X()+radius*math.Cos(theta)), int(center.Y()+radius*math.Sin(theta)), c)
+	}
+}
+
+func drawMarker(buff draw.Image, p floatgeom.Point2, c color.Color) {
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			buff.Set(int(p.X())+x, int(p.Y())+y, c)
+		}
+	}
+}
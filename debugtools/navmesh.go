@@ -0,0 +1,68 @@
+package debugtools
+
+import (
+	"image/color"
+	"image/draw"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/path/navmesh"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// NewNavMesh creates a renderable that draws the triangles of a navigation mesh.
+func NewNavMesh(ctx *scene.Context, m *navmesh.Mesh) *NavMesh {
+	return &NavMesh{
+		Mesh:         m,
+		Context:      ctx,
+		LayeredPoint: render.NewLayeredPoint(0, 0, -1),
+		EdgeColor:    color.RGBA{0, 120, 255, 255},
+		BorderColor:  color.RGBA{255, 255, 255, 255},
+		PathColor:    color.RGBA{255, 200, 0, 255},
+	}
+}
+
+// NavMesh draws the edges of each triangle in a navigation mesh, with edges on
+// the border of the mesh highlighted, and Path, if set, on top of the mesh.
+type NavMesh struct {
+	Mesh *navmesh.Mesh
+	Path []floatgeom.Point2
+	render.LayeredPoint
+	EdgeColor    color.RGBA
+	BorderColor  color.RGBA
+	PathColor    color.RGBA
+	DrawDisabled bool
+	Context      *scene.Context
+}
+
+// GetDims returns the total possible area to draw this on.
+func (n *NavMesh) GetDims() (int, int) {
+	bds := n.Context.Window.Bounds()
+	return bds.X(), bds.Y()
+}
+
+// Draw will draw the mesh and path.
+func (n *NavMesh) Draw(buff draw.Image, xOff, yOff float64) {
+	if n.DrawDisabled {
+		return
+	}
+	vp := n.Context.Window.Viewport()
+	off := floatgeom.Point2{xOff - float64(vp.X()), yOff - float64(vp.Y())}
+	for i := range n.Mesh.Triangles {
+		tri := n.Mesh.Triangle(i)
+		neighbors := n.Mesh.Neighbors(i)
+		for k := 0; k < 3; k++ {
+			c := n.EdgeColor
+			if neighbors[k] == -1 {
+				c = n.BorderColor
+			}
+			drawSegment(buff, tri[k].Add(off), tri[(k+1)%3].Add(off), c)
+		}
+	}
+	for i, p := range n.Path {
+		if i > 0 {
+			drawSegment(buff, n.Path[i-1].Add(off), p.Add(off), n.PathColor)
+		}
+		drawMarker(buff, p.Add(off), n.PathColor)
+	}
+}
@@ -0,0 +1,48 @@
+package bt
+
+import "sync"
+
+// A Blackboard stores values shared between the nodes of a tree, or between trees
+// given the same blackboard. It is safe for concurrent use.
+type Blackboard struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+// NewBlackboard creates an empty blackboard.
+func NewBlackboard() *Blackboard {
+	return &Blackboard{values: make(map[string]interface{})}
+}
+
+// Set stores a value under a key.
+func (bb *Blackboard) Set(key string, v interface{}) {
+	bb.mu.Lock()
+	bb.values[key] = v
+	bb.mu.Unlock()
+}
+
+// Get returns the value stored under a key, and whether there was one.
+func (bb *Blackboard) Get(key string) (interface{}, bool) {
+	bb.mu.RLock()
+	defer bb.mu.RUnlock()
+	v, ok := bb.values[key]
+	return v, ok
+}
+
+// Delete removes the value stored under a key.
+func (bb *Blackboard) Delete(key string) {
+	bb.mu.Lock()
+	delete(bb.values, key)
+	bb.mu.Unlock()
+}
+
+// Value returns the value stored under a key as a T. If there is no value, or it
+// is not a T, ok will be false.
+func Value[T any](bb *Blackboard, key string) (v T, ok bool) {
+	raw, ok := bb.Get(key)
+	if !ok {
+		return v, false
+	}
+	v, ok = raw.(T)
+	return v, ok
+}
@@ -0,0 +1,140 @@
+package bt
+
+// A Sequence ticks its nodes in order until one fails or is running. It succeeds
+// once all of its nodes have succeeded, and resumes from a running node on its
+// next tick.
+type Sequence struct {
+	Name  string
+	Nodes []Node
+
+	index int
+}
+
+// NewSequence creates a sequence of nodes.
+func NewSequence(name string, nodes ...Node) *Sequence {
+	return &Sequence{Name: name, Nodes: nodes}
+}
+
+func (s *Sequence) Tick(ctx *Context) Status {
+	for s.index < len(s.Nodes) {
+		switch ctx.Tick(s.Nodes[s.index]) {
+		case Running:
+			return Running
+		case Failure:
+			s.index = 0
+			return Failure
+		}
+		s.index++
+	}
+	s.index = 0
+	return Success
+}
+
+func (s *Sequence) Reset() {
+	s.index = 0
+	resetAll(s.Nodes)
+}
+
+// A Selector ticks its nodes in order until one succeeds or is running. It fails
+// once all of its nodes have failed, and resumes from a running node on its next
+// tick.
+type Selector struct {
+	Name  string
+	Nodes []Node
+
+	index int
+}
+
+// NewSelector creates a selector of nodes.
+func NewSelector(name string, nodes ...Node) *Selector {
+	return &Selector{Name: name, Nodes: nodes}
+}
+
+func (s *Selector) Tick(ctx *Context) Status {
+	for s.index < len(s.Nodes) {
+		switch ctx.Tick(s.Nodes[s.index]) {
+		case Running:
+			return Running
+		case Success:
+			s.index = 0
+			return Success
+		}
+		s.index++
+	}
+	s.index = 0
+	return Failure
+}
+
+func (s *Selector) Reset() {
+	s.index = 0
+	resetAll(s.Nodes)
+}
+
+// A Parallel ticks all of its unfinished nodes on each tick. It succeeds once
+// SuccessCount of its nodes have succeeded, and fails once so many have failed
+// that it cannot succeed. When it finishes, nodes still running are reset.
+type Parallel struct {
+	Name  string
+	Nodes []Node
+	// SuccessCount is how many nodes must succeed. If zero, all must succeed.
+	SuccessCount int
+
+	done []Status
+}
+
+// NewParallel creates a parallel of nodes which succeeds once successCount of
+// them have succeeded.
+func NewParallel(name string, successCount int, nodes ...Node) *Parallel {
+	return &Parallel{Name: name, Nodes: nodes, SuccessCount: successCount}
+}
+
+func (p *Parallel) Tick(ctx *Context) Status {
+	if len(p.done) != len(p.Nodes) {
+		p.done = make([]Status, len(p.Nodes))
+		for i := range p.done {
+			p.done[i] = Running
+		}
+	}
+	need := p.SuccessCount
+	if need <= 0 || need > len(p.Nodes) {
+		need = len(p.Nodes)
+	}
+	successes, failures := 0, 0
+	for i, n := range p.Nodes {
+		if p.done[i] == Running {
+			p.done[i] = ctx.Tick(n)
+		}
+		switch p.done[i] {
+		case Success:
+			successes++
+		case Failure:
+			failures++
+		}
+	}
+	status := Running
+	if successes >= need {
+		status = Success
+	} else if failures > len(p.Nodes)-need {
+		status = Failure
+	}
+	if status != Running {
+		for i, n := range p.Nodes {
+			if p.done[i] == Running {
+				n.Reset()
+			}
+		}
+		p.done = nil
+	}
+	return status
+}
+
+func (p *Parallel) Reset() {
+	p.done = nil
+	resetAll(p.Nodes)
+}
+
+func resetAll(nodes []Node) {
+	for _, n := range nodes {
+		n.Reset()
+	}
+}
@@ -0,0 +1,135 @@
+package bt
+
+import "time"
+
+// An Inverter succeeds when its node fails and fails when its node succeeds.
+type Inverter struct {
+	Node Node
+}
+
+// NewInverter creates an inverter of a node.
+func NewInverter(n Node) *Inverter {
+	return &Inverter{Node: n}
+}
+
+func (inv *Inverter) Tick(ctx *Context) Status {
+	switch ctx.Tick(inv.Node) {
+	case Success:
+		return Failure
+	case Failure:
+		return Success
+	}
+	return Running
+}
+
+func (inv *Inverter) Reset() {
+	inv.Node.Reset()
+}
+
+// A Repeat ticks its node until it has succeeded Count times, starting at most one
+// repetition per tick, and is running until then. If the node fails, the Repeat
+// fails.
+type Repeat struct {
+	Node Node
+	// Count is how many times the node must succeed. If zero, the node is repeated
+	// until it fails.
+	Count int
+
+	completed int
+}
+
+// NewRepeat creates a repeat of a node.
+func NewRepeat(n Node, count int) *Repeat {
+	return &Repeat{Node: n, Count: count}
+}
+
+func (r *Repeat) Tick(ctx *Context) Status {
+	switch ctx.Tick(r.Node) {
+	case Running:
+		return Running
+	case Failure:
+		r.completed = 0
+		return Failure
+	}
+	r.completed++
+	if r.Count > 0 && r.completed >= r.Count {
+		r.completed = 0
+		return Success
+	}
+	return Running
+}
+
+func (r *Repeat) Reset() {
+	r.completed = 0
+	r.Node.Reset()
+}
+
+// A Cooldown fails without ticking its node until Duration has passed since the
+// node last finished.
+type Cooldown struct {
+	Node     Node
+	Duration time.Duration
+
+	started bool
+	readyAt time.Duration
+}
+
+// NewCooldown creates a cooldown of a node.
+func NewCooldown(n Node, d time.Duration) *Cooldown {
+	return &Cooldown{Node: n, Duration: d}
+}
+
+func (c *Cooldown) Tick(ctx *Context) Status {
+	if c.started && ctx.Now < c.readyAt {
+		return Failure
+	}
+	s := ctx.Tick(c.Node)
+	if s != Running {
+		c.started = true
+		c.readyAt = ctx.Now + c.Duration
+	}
+	return s
+}
+
+// Reset resets the cooldown's node. The cooldown itself is not reset, so it can
+// not be skipped by interrupting its node.
+func (c *Cooldown) Reset() {
+	c.Node.Reset()
+}
+
+// A Timeout fails, resetting its node, if its node has been running for Duration.
+type Timeout struct {
+	Node     Node
+	Duration time.Duration
+
+	running bool
+	started time.Duration
+}
+
+// NewTimeout creates a timeout of a node.
+func NewTimeout(n Node, d time.Duration) *Timeout {
+	return &Timeout{Node: n, Duration: d}
+}
+
+func (t *Timeout) Tick(ctx *Context) Status {
+	if !t.running {
+		t.running = true
+		t.started = ctx.Now
+	}
+	s := ctx.Tick(t.Node)
+	if s != Running {
+		t.running = false
+		return s
+	}
+	if ctx.Now-t.started >= t.Duration {
+		t.running = false
+		t.Node.Reset()
+		return Failure
+	}
+	return Running
+}
+
+func (t *Timeout) Reset() {
+	t.running = false
+	t.Node.Reset()
+}
@@ -0,0 +1,194 @@
+package bt
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// Node types built in to Definitions. Definitions of any other type are leaves,
+// built from a Library.
+const (
+	TypeSequence = "sequence"
+	TypeSelector = "selector"
+	TypeParallel = "parallel"
+	TypeInverter = "inverter"
+	TypeRepeat   = "repeat"
+	TypeCooldown = "cooldown"
+	TypeTimeout  = "timeout"
+)
+
+// A Definition describes a tree of nodes, to be built by a Library.
+type Definition struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	// Count is the count of repeats and the success count of parallels.
+	Count int `json:"count,omitempty"`
+	// Duration is the duration of cooldowns and timeouts, as accepted by
+	// time.ParseDuration.
+	Duration string       `json:"duration,omitempty"`
+	Children []Definition `json:"children,omitempty"`
+}
+
+// ParseJSON parses a definition from JSON.
+func ParseJSON(data []byte) (Definition, error) {
+	def := Definition{}
+	err := json.Unmarshal(data, &def)
+	return def, err
+}
+
+// ParseText parses a definition from an indented text format. Each line is a node,
+// indented further than its parent, and '#' begins a comment:
+//
+//	selector
+//	  sequence attack
+//	    canSeePlayer
+//	    timeout 2s
+//	      chase
+//	  cooldown 5s
+//	    bark
+//	  repeat 3
+//	    wander
+//
+// Sequences, selectors, and parallels may be followed by a name, and parallels by
+// a success count before it. Repeats may be followed by a count, and cooldowns and
+// timeouts must be followed by a duration. Any other type is a leaf.
+func ParseText(text string) (Definition, error) {
+	type textNode struct {
+		def      Definition
+		indent   int
+		children []*textNode
+	}
+	var root *textNode
+	var stack []*textNode
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		n := &textNode{
+			def:    Definition{Type: fields[0]},
+			indent: len(line) - len(strings.TrimLeft(line, " \t")),
+		}
+		args := fields[1:]
+		switch n.def.Type {
+		case TypeRepeat, TypeParallel:
+			if len(args) != 0 {
+				if count, err := strconv.Atoi(args[0]); err == nil {
+					n.def.Count = count
+					args = args[1:]
+				}
+			}
+		case TypeCooldown, TypeTimeout:
+			if len(args) != 0 {
+				n.def.Duration = args[0]
+				args = args[1:]
+			}
+		}
+		n.def.Name = strings.Join(args, " ")
+		for len(stack) != 0 && stack[len(stack)-1].indent >= n.indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			if root != nil {
+				return Definition{}, oakerr.InvalidInput{InputName: "text"}
+			}
+			root = n
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, n)
+		}
+		stack = append(stack, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return Definition{}, err
+	}
+	if root == nil {
+		return Definition{}, oakerr.InsufficientInputs{AtLeast: 1, InputName: "text"}
+	}
+	var convert func(*textNode) Definition
+	convert = func(n *textNode) Definition {
+		for _, c := range n.children {
+			n.def.Children = append(n.def.Children, convert(c))
+		}
+		return n.def
+	}
+	return convert(root), nil
+}
+
+// A Library builds leaf nodes by type name. Each function should return a new
+// node, as nodes may not be shared between trees.
+type Library map[string]func() Node
+
+// Build builds the nodes described by a definition. Leaf types not in the library
+// return a NotFound error. Actions and conditions built without names are named
+// after their type.
+func (lib Library) Build(def Definition) (Node, error) {
+	children := make([]Node, len(def.Children))
+	for i, c := range def.Children {
+		n, err := lib.Build(c)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = n
+	}
+	switch def.Type {
+	case TypeSequence, TypeSelector, TypeParallel:
+		if len(children) == 0 {
+			return nil, oakerr.InsufficientInputs{AtLeast: 1, InputName: def.Type + " children"}
+		}
+		switch def.Type {
+		case TypeSequence:
+			return NewSequence(def.Name, children...), nil
+		case TypeSelector:
+			return NewSelector(def.Name, children...), nil
+		}
+		return NewParallel(def.Name, def.Count, children...), nil
+	case TypeInverter, TypeRepeat, TypeCooldown, TypeTimeout:
+		if len(children) != 1 {
+			return nil, oakerr.InvalidInput{InputName: def.Type + " children"}
+		}
+		switch def.Type {
+		case TypeInverter:
+			return NewInverter(children[0]), nil
+		case TypeRepeat:
+			return NewRepeat(children[0], def.Count), nil
+		}
+		d, err := time.ParseDuration(def.Duration)
+		if err != nil {
+			return nil, oakerr.InvalidInput{InputName: def.Type + " duration"}
+		}
+		if def.Type == TypeCooldown {
+			return NewCooldown(children[0], d), nil
+		}
+		return NewTimeout(children[0], d), nil
+	}
+	if len(children) != 0 {
+		return nil, oakerr.InvalidInput{InputName: def.Type + " children"}
+	}
+	fn, ok := lib[def.Type]
+	if !ok {
+		return nil, oakerr.NotFound{InputName: def.Type}
+	}
+	n := fn()
+	switch leaf := n.(type) {
+	case *Action:
+		if leaf.Name == "" {
+			leaf.Name = def.Type
+		}
+	case *Condition:
+		if leaf.Name == "" {
+			leaf.Name = def.Type
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package bt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testText = `
+# guard behavior
+selector root
+  sequence attack
+    canSee
+    timeout 2s
+      chase
+  cooldown 5s
+    bark
+  parallel 1 idle
+    inverter
+      canSee
+    repeat 3
+      wander
+`
+
+const testJSON = `{"type": "selector", "name": "root", "children": [
+	{"type": "sequence", "name": "attack", "children": [
+		{"type": "canSee"},
+		{"type": "timeout", "duration": "2s", "children": [{"type": "chase"}]}
+	]},
+	{"type": "cooldown", "duration": "5s", "children": [{"type": "bark"}]},
+	{"type": "parallel", "name": "idle", "count": 1, "children": [
+		{"type": "inverter", "children": [{"type": "canSee"}]},
+		{"type": "repeat", "count": 3, "children": [{"type": "wander"}]}
+	]}
+]}`
+
+func testLibrary() Library {
+	return Library{
+		"canSee": func() Node {
+			return NewCondition("", func(ctx *Context) bool {
+				v, _ := Value[bool](ctx.Blackboard, "seen")
+				return v
+			})
+		},
+		"chase":  func() Node { return NewAction("", func(*Context) Status { return Running }) },
+		"bark":   func() Node { return NewAction("", func(*Context) Status { return Failure }) },
+		"wander": func() Node { return NewAction("", func(*Context) Status { return Success }) },
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	fromText, err := ParseText(testText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ParseJSON([]byte(testJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"selector root [success]",
+		"  sequence attack [failure]",
+		"    canSee [failure]",
+		"    timeout 2s [-]",
+		"      chase [-]",
+		"  cooldown 5s [failure]",
+		"    bark [failure]",
+		"  parallel idle [success]",
+		"    inverter [success]",
+		"      canSee [failure]",
+		"    repeat 3 [running]",
+		"      wander [success]",
+	}, "\n")
+	for _, def := range []Definition{fromText, fromJSON} {
+		root, err := testLibrary().Build(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree := newTestTree(root)
+		tree.Tick(time.Second / 60)
+		in := NewInspector()
+		in.Add(tree)
+		if got, _ := in.Describe(tree.Caller.CID()); got != want {
+			t.Fatalf("expected tree:\n%s\ngot:\n%s", want, got)
+		}
+		cmd := in.Command("bt")
+		if out := cmd.Operation(nil); !strings.Contains(out, "selector root [success]") {
+			t.Fatalf("unexpected tree list %q", out)
+		}
+		if out := cmd.Operation([]string{"999"}); !strings.Contains(out, "no tree") {
+			t.Fatalf("unexpected output for missing tree %q", out)
+		}
+	}
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"sequence\nselector",
+		"sequence",
+		"inverter\n  wander\n  wander",
+		"cooldown soon\n  wander",
+		"missing",
+		"wander\n  wander",
+	} {
+		def, err := ParseText(text)
+		if err == nil {
+			_, err = testLibrary().Build(def)
+		}
+		if err == nil {
+			t.Fatalf("expected error for definition %q", text)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package bt provides behavior trees for driving callers.
+//
+// A Tree ticks its root node, usually once per frame. Composite nodes tick their
+// children in turn, decorators alter the results of a single child, and leaves
+// perform actions or check conditions. Each node reports Success, Failure, or
+// Running; nodes which are Running are ticked again on the next tick, resuming
+// where they left off.
+//
+// Nodes keep state between ticks, so a tree of nodes should only be ticked by a
+// single Tree. To give many callers the same behavior, build a tree for each of
+// them from a Definition, or from a function returning fresh nodes.
+package bt
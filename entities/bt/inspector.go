@@ -0,0 +1,170 @@
+package bt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/oakmound/oak/v4/debugstream"
+	"github.com/oakmound/oak/v4/event"
+)
+
+// An Inspector tracks trees by caller so their node statuses can be shown from a
+// debug stream.
+type Inspector struct {
+	mu    sync.Mutex
+	trees map[event.CallerID]*Tree
+}
+
+// NewInspector creates an inspector tracking no trees.
+func NewInspector() *Inspector {
+	return &Inspector{trees: make(map[event.CallerID]*Tree)}
+}
+
+// Add tracks trees by the IDs of their callers, replacing any tree already tracked
+// for the same caller.
+func (in *Inspector) Add(ts ...*Tree) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for _, t := range ts {
+		in.trees[t.Caller.CID()] = t
+	}
+}
+
+// Remove stops tracking the trees of the given callers.
+func (in *Inspector) Remove(ids ...event.CallerID) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for _, id := range ids {
+		delete(in.trees, id)
+	}
+}
+
+// Describe returns the nodes of the tree tracked for a caller, one per line and
+// indented by depth, with their statuses from the tree's last tick. Nodes which
+// were not ticked are marked with '-'.
+func (in *Inspector) Describe(id event.CallerID) (string, bool) {
+	in.mu.Lock()
+	t, ok := in.trees[id]
+	in.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	sb := &strings.Builder{}
+	describe(sb, t, t.Root, 0)
+	return strings.TrimSuffix(sb.String(), "\n"), true
+}
+
+func describe(sb *strings.Builder, t *Tree, n Node, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(label(n))
+	if s, ok := t.NodeStatus(n); ok {
+		sb.WriteString(" [" + s.String() + "]\n")
+	} else {
+		sb.WriteString(" [-]\n")
+	}
+	for _, c := range children(n) {
+		describe(sb, t, c, depth+1)
+	}
+}
+
+// Command returns a debugstream command with the given name. Given a caller ID,
+// it describes that caller's tree; given nothing, it lists the callers with
+// tracked trees and their trees' statuses.
+func (in *Inspector) Command(name string) debugstream.Command {
+	return debugstream.Command{
+		Name:  name,
+		Usage: "[callerID]: list behavior trees, or show the node statuses of one",
+		Operation: func(args []string) string {
+			if len(args) == 0 {
+				return in.list()
+			}
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return "invalid caller ID " + args[0]
+			}
+			out, ok := in.Describe(event.CallerID(id))
+			if !ok {
+				return "no tree for caller " + args[0]
+			}
+			return out
+		},
+	}
+}
+
+func (in *Inspector) list() string {
+	in.mu.Lock()
+	ids := make([]event.CallerID, 0, len(in.trees))
+	for id := range in.trees {
+		ids = append(ids, id)
+	}
+	in.mu.Unlock()
+	if len(ids) == 0 {
+		return "no behavior trees"
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	lines := make([]string, len(ids))
+	for i, id := range ids {
+		in.mu.Lock()
+		t := in.trees[id]
+		in.mu.Unlock()
+		status := "-"
+		if s, ok := t.Status(); ok {
+			status = s.String()
+		}
+		lines[i] = fmt.Sprintf("%d: %s [%s]", id, label(t.Root), status)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func label(n Node) string {
+	named := func(kind, name string) string {
+		if name == "" {
+			return kind
+		}
+		return kind + " " + name
+	}
+	switch n := n.(type) {
+	case *Sequence:
+		return named(TypeSequence, n.Name)
+	case *Selector:
+		return named(TypeSelector, n.Name)
+	case *Parallel:
+		return named(TypeParallel, n.Name)
+	case *Inverter:
+		return TypeInverter
+	case *Repeat:
+		return named(TypeRepeat, strconv.Itoa(n.Count))
+	case *Cooldown:
+		return named(TypeCooldown, n.Duration.String())
+	case *Timeout:
+		return named(TypeTimeout, n.Duration.String())
+	case *Action:
+		return n.Name
+	case *Condition:
+		return n.Name
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+func children(n Node) []Node {
+	switch n := n.(type) {
+	case *Sequence:
+		return n.Nodes
+	case *Selector:
+		return n.Nodes
+	case *Parallel:
+		return n.Nodes
+	case *Inverter:
+		return []Node{n.Node}
+	case *Repeat:
+		return []Node{n.Node}
+	case *Cooldown:
+		return []Node{n.Node}
+	case *Timeout:
+		return []Node{n.Node}
+	}
+	return nil
+}
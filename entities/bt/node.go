@@ -0,0 +1,106 @@
+package bt
+
+import (
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// Status is the result of ticking a node.
+type Status int
+
+const (
+	Success Status = iota
+	Failure
+	Running
+)
+
+func (s Status) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Failure:
+		return "failure"
+	case Running:
+		return "running"
+	}
+	return "unknown"
+}
+
+// A Node is an element of a behavior tree.
+type Node interface {
+	// Tick runs the node, returning its status.
+	Tick(*Context) Status
+	// Reset returns the node and its children to their initial state,
+	// abandoning any running work.
+	Reset()
+}
+
+// A Context is passed to nodes as they are ticked.
+type Context struct {
+	Tree       *Tree
+	Caller     event.Caller
+	Blackboard *Blackboard
+	// Elapsed is the time since the tree's last tick, and Now is the total of
+	// all elapsed time the tree has been ticked with.
+	Elapsed time.Duration
+	Now     time.Duration
+}
+
+// Tick ticks a node, recording its status on the context's tree. Composite nodes
+// should tick their children through this method.
+func (ctx *Context) Tick(n Node) Status {
+	s := n.Tick(ctx)
+	ctx.Tree.record(n, s)
+	return s
+}
+
+// An Action is a leaf node calling a function.
+type Action struct {
+	Name string
+	Fn   func(*Context) Status
+}
+
+// NewAction creates an action node.
+func NewAction(name string, fn func(*Context) Status) *Action {
+	return &Action{Name: name, Fn: fn}
+}
+
+// CallerAction creates an action node calling fn with the tree's caller. If the
+// caller is not a C, the action fails.
+func CallerAction[C event.Caller](name string, fn func(C, *Context) Status) *Action {
+	return NewAction(name, func(ctx *Context) Status {
+		c, ok := ctx.Caller.(C)
+		if !ok {
+			return Failure
+		}
+		return fn(c, ctx)
+	})
+}
+
+func (a *Action) Tick(ctx *Context) Status {
+	return a.Fn(ctx)
+}
+
+func (a *Action) Reset() {}
+
+// A Condition is a leaf node which succeeds when its function returns true, and
+// fails otherwise.
+type Condition struct {
+	Name string
+	Fn   func(*Context) bool
+}
+
+// NewCondition creates a condition node.
+func NewCondition(name string, fn func(*Context) bool) *Condition {
+	return &Condition{Name: name, Fn: fn}
+}
+
+func (c *Condition) Tick(ctx *Context) Status {
+	if c.Fn(ctx) {
+		return Success
+	}
+	return Failure
+}
+
+func (c *Condition) Reset() {}
@@ -0,0 +1,95 @@
+package bt
+
+import (
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision/ray"
+)
+
+// A Positioned caller has a position, as entities do.
+type Positioned interface {
+	X() float64
+	Y() float64
+}
+
+// A Mover is a Positioned caller which can be shifted, as entities can.
+type Mover interface {
+	Positioned
+	ShiftPos(x, y float64)
+}
+
+// A Locator finds a point for a node, returning false if there is none.
+type Locator func(*Context) (floatgeom.Point2, bool)
+
+// Key locates the floatgeom.Point2 stored in the blackboard under a key.
+func Key(key string) Locator {
+	return func(ctx *Context) (floatgeom.Point2, bool) {
+		return Value[floatgeom.Point2](ctx.Blackboard, key)
+	}
+}
+
+// CallerPosition locates the caller's position plus an offset, if the caller is
+// Positioned.
+func CallerPosition(offset floatgeom.Point2) Locator {
+	return func(ctx *Context) (floatgeom.Point2, bool) {
+		p, ok := ctx.Caller.(Positioned)
+		if !ok {
+			return floatgeom.Point2{}, false
+		}
+		return floatgeom.Point2{p.X(), p.Y()}.Add(offset), true
+	}
+}
+
+// CanSee creates a condition which succeeds when target is no further from eye
+// than the caster's CastDistance and a ray cast from eye to target hits nothing.
+// The caster's filters and tree determine what blocks sight; the spaces of the
+// caller and target should usually be ignored.
+func CanSee(name string, caster *ray.Caster, eye, target Locator) *Condition {
+	return NewCondition(name, func(ctx *Context) bool {
+		from, ok := eye(ctx)
+		if !ok {
+			return false
+		}
+		to, ok := target(ctx)
+		if !ok {
+			return false
+		}
+		dist := from.Distance(to)
+		if dist > caster.CastDistance {
+			return false
+		}
+		c := caster.Copy()
+		c.CastDistance = dist
+		return len(c.CastTo(from, to)) == 0
+	})
+}
+
+// MoveTo creates an action moving a Mover caller's position toward target at speed
+// units per second. It is running until the caller is within distance of the
+// target, and fails if the caller is not a Mover or there is no target.
+func MoveTo(name string, target Locator, speed, within float64) *Action {
+	return NewAction(name, func(ctx *Context) Status {
+		m, ok := ctx.Caller.(Mover)
+		if !ok {
+			return Failure
+		}
+		to, ok := target(ctx)
+		if !ok {
+			return Failure
+		}
+		diff := to.Sub(floatgeom.Point2{m.X(), m.Y()})
+		dist := diff.Magnitude()
+		if dist <= within {
+			return Success
+		}
+		step := speed * ctx.Elapsed.Seconds()
+		if step >= dist-within {
+			step = dist - within
+		}
+		move := diff.MulConst(step / dist)
+		m.ShiftPos(move.X(), move.Y())
+		if step == dist-within {
+			return Success
+		}
+		return Running
+	})
+}
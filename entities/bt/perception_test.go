@@ -0,0 +1,35 @@
+package bt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/collision/ray"
+)
+
+func TestCanSeeAndMoveTo(t *testing.T) {
+	tr := collision.NewTree()
+	tr.Add(collision.NewUnassignedSpace(50, -10, 10, 20))
+	caster := ray.NewCaster(ray.Tree(tr), ray.Distance(100))
+	target := Key("target")
+	tree := newTestTree(NewSequence("hunt",
+		CanSee("canSee", caster, CallerPosition(floatgeom.Point2{}), target),
+		MoveTo("approach", target, 100, 5),
+	))
+	tree.Blackboard.Set("target", floatgeom.Point2{100, 0})
+	if s := tree.Tick(time.Second / 10); s != Failure {
+		t.Fatalf("expected wall to block sight, got %v", s)
+	}
+	tree.Blackboard.Set("target", floatgeom.Point2{0, 50})
+	expectStatuses(t, tree, time.Second/10, Running, Running, Running, Running, Success)
+	a := tree.Caller.(*agent)
+	if d := (floatgeom.Point2{a.x, a.y}).Distance(floatgeom.Point2{0, 50}); d > 5.0001 {
+		t.Fatalf("expected agent to approach target, was %v away", d)
+	}
+	tree.Blackboard.Set("target", floatgeom.Point2{0, 500})
+	if s := tree.Tick(time.Second / 10); s != Failure {
+		t.Fatalf("expected target out of range to be unseen, got %v", s)
+	}
+}
@@ -0,0 +1,98 @@
+package bt
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A Tree ticks a root node on behalf of a caller.
+type Tree struct {
+	Root       Node
+	Caller     event.Caller
+	Handler    event.Handler
+	Blackboard *Blackboard
+
+	ctx Context
+
+	mu       sync.Mutex
+	status   Status
+	ticked   bool
+	statuses map[Node]Status
+}
+
+// NewTree creates a tree ticking root for a caller, with an empty blackboard. If
+// handler is nil, the DefaultBus will be used.
+func NewTree(handler event.Handler, caller event.Caller, root Node) *Tree {
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	return &Tree{
+		Root:       root,
+		Caller:     caller,
+		Handler:    handler,
+		Blackboard: NewBlackboard(),
+		statuses:   make(map[Node]Status),
+	}
+}
+
+// Tick ticks the tree's root node, with elapsed time having passed since the last
+// tick. Once the root node finishes, the next tick starts it over.
+func (t *Tree) Tick(elapsed time.Duration) Status {
+	t.mu.Lock()
+	t.statuses = make(map[Node]Status, len(t.statuses))
+	t.mu.Unlock()
+	t.ctx.Tree = t
+	t.ctx.Caller = t.Caller
+	t.ctx.Blackboard = t.Blackboard
+	t.ctx.Elapsed = elapsed
+	t.ctx.Now += elapsed
+	s := t.ctx.Tick(t.Root)
+	t.mu.Lock()
+	t.status = s
+	t.ticked = true
+	t.mu.Unlock()
+	return s
+}
+
+func (t *Tree) record(n Node, s Status) {
+	t.mu.Lock()
+	t.statuses[n] = s
+	t.mu.Unlock()
+}
+
+// Status returns the status of the root node from the last tick, and whether the
+// tree has been ticked.
+func (t *Tree) Status() (Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status, t.ticked
+}
+
+// NodeStatus returns the status of a node from the last tick, and whether it was
+// ticked.
+func (t *Tree) NodeStatus(n Node) (Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.statuses[n]
+	return s, ok
+}
+
+// Reset resets every node in the tree.
+func (t *Tree) Reset() {
+	t.Root.Reset()
+	t.mu.Lock()
+	t.statuses = make(map[Node]Status)
+	t.ticked = false
+	t.mu.Unlock()
+}
+
+// Bind causes this tree to Tick every frame, until the returned binding is
+// unbound or the caller's bindings are unbound.
+func (t *Tree) Bind() event.Binding {
+	return t.Handler.UnsafeBind(event.Enter.UnsafeEventID, t.Caller.CID(), func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		t.Tick(payload.(event.EnterPayload).SinceLastFrame)
+		return 0
+	})
+}
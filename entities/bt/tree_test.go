@@ -0,0 +1,125 @@
+package bt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+type agent struct {
+	event.CallerID
+	x, y float64
+}
+
+func (a *agent) X() float64 { return a.x }
+func (a *agent) Y() float64 { return a.y }
+func (a *agent) ShiftPos(x, y float64) {
+	a.x += x
+	a.y += y
+}
+
+// script returns an action which returns the given statuses in order, repeating
+// the last, and counts its ticks.
+func script(ticks *int, statuses ...Status) *Action {
+	i := 0
+	return NewAction("script", func(*Context) Status {
+		*ticks++
+		s := statuses[i]
+		if i < len(statuses)-1 {
+			i++
+		}
+		return s
+	})
+}
+
+func newTestTree(root Node) *Tree {
+	h := event.NewBus(event.NewCallerMap())
+	a := &agent{}
+	a.CallerID = h.GetCallerMap().Register(a)
+	return NewTree(h, a, root)
+}
+
+func expectStatuses(t *testing.T, tree *Tree, step time.Duration, want ...Status) {
+	t.Helper()
+	for i, w := range want {
+		if got := tree.Tick(step); got != w {
+			t.Fatalf("tick %d: expected %v, got %v", i, w, got)
+		}
+	}
+}
+
+func TestSequenceAndSelector(t *testing.T) {
+	var a, b, c int
+	seq := NewSequence("seq", script(&a, Success), script(&b, Running, Success), script(&c, Failure))
+	expectStatuses(t, newTestTree(seq), 0, Running, Failure)
+	if a != 1 || b != 2 || c != 1 {
+		t.Fatalf("sequence did not resume from its running node: %v %v %v", a, b, c)
+	}
+	a, b, c = 0, 0, 0
+	sel := NewSelector("sel", script(&a, Failure), script(&b, Running, Success), script(&c, Success))
+	expectStatuses(t, newTestTree(sel), 0, Running, Success, Success)
+	if a != 2 || b != 3 || c != 0 {
+		t.Fatalf("selector ticked unexpected nodes: %v %v %v", a, b, c)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var a, b, c int
+	par := NewParallel("par", 2, script(&a, Success), script(&b, Running, Running, Success), script(&c, Running))
+	expectStatuses(t, newTestTree(par), 0, Running, Running, Success)
+	if a != 1 || b != 3 || c != 3 {
+		t.Fatalf("parallel ticked unexpected nodes: %v %v %v", a, b, c)
+	}
+	par = NewParallel("par", 0, script(&a, Success), script(&b, Failure))
+	expectStatuses(t, newTestTree(par), 0, Failure)
+}
+
+func TestDecorators(t *testing.T) {
+	var n int
+	expectStatuses(t, newTestTree(NewInverter(script(&n, Running, Success, Failure))), 0, Running, Failure, Success)
+
+	n = 0
+	expectStatuses(t, newTestTree(NewRepeat(script(&n, Success), 3)), 0, Running, Running, Success, Running)
+	expectStatuses(t, newTestTree(NewRepeat(script(&n, Success, Failure), 0)), 0, Running, Failure)
+
+	n = 0
+	cd := newTestTree(NewCooldown(script(&n, Success), time.Second))
+	expectStatuses(t, cd, 400*time.Millisecond, Success, Failure, Failure, Success)
+	if n != 2 {
+		t.Fatalf("cooldown ticked its node %d times, expected 2", n)
+	}
+
+	n = 0
+	to := newTestTree(NewTimeout(script(&n, Running), time.Second))
+	expectStatuses(t, to, 400*time.Millisecond, Running, Running, Running, Failure, Running)
+}
+
+func TestTreeBind(t *testing.T) {
+	ticked := make(chan time.Duration, 1)
+	tree := newTestTree(NewAction("record", func(ctx *Context) Status {
+		ticked <- ctx.Elapsed
+		return Success
+	}))
+	b := tree.Bind()
+	<-b.Bound
+	event.TriggerForCallerOn(tree.Handler, tree.Caller.CID(), event.Enter, event.EnterPayload{SinceLastFrame: time.Second})
+	if d := <-ticked; d != time.Second {
+		t.Fatalf("expected tick with elapsed time of one second, got %v", d)
+	}
+}
+
+func TestBlackboard(t *testing.T) {
+	bb := NewBlackboard()
+	bb.Set("hp", 10)
+	if hp, ok := Value[int](bb, "hp"); !ok || hp != 10 {
+		t.Fatalf("expected stored value 10, got %v %v", hp, ok)
+	}
+	if _, ok := Value[string](bb, "hp"); ok {
+		t.Fatalf("value of the wrong type was returned")
+	}
+	bb.Delete("hp")
+	if _, ok := bb.Get("hp"); ok {
+		t.Fatalf("deleted value was returned")
+	}
+}
@@ -0,0 +1,13 @@
+// Package ecs provides an opt-in entity component system. Entities are caller IDs,
+// components of each type are stored densely together, and systems run in a fixed
+// order every frame.
+//
+// Queries call their function for every entity with all of the queried component
+// types. The component pointers passed in are only valid for the duration of the
+// call. Components may be added and removed, and entities destroyed, during a
+// query, but these changes are deferred until the outermost query finishes so
+// that no component moves while a query runs. Removed components are treated as
+// absent immediately, so entities which no longer match when they would be
+// visited are skipped; added components are not visible until the query
+// finishes, so entities which begin to match during the query are not visited.
+package ecs
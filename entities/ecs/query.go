@@ -0,0 +1,107 @@
+package ecs
+
+import "github.com/oakmound/oak/v4/event"
+
+// Each calls fn for every entity with a component of type A.
+func Each[A any](w *World, fn func(event.CallerID, *A)) {
+	sa := lookup[A](w)
+	if sa == nil {
+		return
+	}
+	w.beginQuery()
+	defer w.endQuery()
+	for _, id := range sa.ids {
+		a, ok := sa.get(id)
+		if !ok {
+			continue
+		}
+		fn(id, a)
+	}
+}
+
+// Query calls fn for every entity with components of both types A and B.
+func Query[A, B any](w *World, fn func(event.CallerID, *A, *B)) {
+	sa, sb := lookup[A](w), lookup[B](w)
+	if sa == nil || sb == nil {
+		return
+	}
+	w.beginQuery()
+	defer w.endQuery()
+	for _, id := range smallest(sa.ids, sb.ids) {
+		a, ok := sa.get(id)
+		if !ok {
+			continue
+		}
+		b, ok := sb.get(id)
+		if !ok {
+			continue
+		}
+		fn(id, a, b)
+	}
+}
+
+// Query3 calls fn for every entity with components of types A, B, and C.
+func Query3[A, B, C any](w *World, fn func(event.CallerID, *A, *B, *C)) {
+	sa, sb, sc := lookup[A](w), lookup[B](w), lookup[C](w)
+	if sa == nil || sb == nil || sc == nil {
+		return
+	}
+	w.beginQuery()
+	defer w.endQuery()
+	for _, id := range smallest(sa.ids, sb.ids, sc.ids) {
+		a, ok := sa.get(id)
+		if !ok {
+			continue
+		}
+		b, ok := sb.get(id)
+		if !ok {
+			continue
+		}
+		c, ok := sc.get(id)
+		if !ok {
+			continue
+		}
+		fn(id, a, b, c)
+	}
+}
+
+// Query4 calls fn for every entity with components of types A, B, C, and D.
+func Query4[A, B, C, D any](w *World, fn func(event.CallerID, *A, *B, *C, *D)) {
+	sa, sb, sc, sd := lookup[A](w), lookup[B](w), lookup[C](w), lookup[D](w)
+	if sa == nil || sb == nil || sc == nil || sd == nil {
+		return
+	}
+	w.beginQuery()
+	defer w.endQuery()
+	for _, id := range smallest(sa.ids, sb.ids, sc.ids, sd.ids) {
+		a, ok := sa.get(id)
+		if !ok {
+			continue
+		}
+		b, ok := sb.get(id)
+		if !ok {
+			continue
+		}
+		c, ok := sc.get(id)
+		if !ok {
+			continue
+		}
+		d, ok := sd.get(id)
+		if !ok {
+			continue
+		}
+		fn(id, a, b, c, d)
+	}
+}
+
+// smallest returns the shortest of the given ID lists, which is the cheapest to
+// drive a query from.
+func smallest(idLists ...[]event.CallerID) []event.CallerID {
+	min := idLists[0]
+	for _, ids := range idLists[1:] {
+		if len(ids) < len(min) {
+			min = ids
+		}
+	}
+	return min
+}
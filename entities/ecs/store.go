@@ -0,0 +1,87 @@
+package ecs
+
+import "github.com/oakmound/oak/v4/event"
+
+// A store holds every component of one type, densely packed.
+type store[T any] struct {
+	components []T
+	ids        []event.CallerID
+	index      map[event.CallerID]int
+	// removing holds components removed during a query, which stay in place until
+	// the query finishes but are otherwise treated as absent.
+	removing map[event.CallerID]struct{}
+}
+
+// storeKey identifies the store for components of type T.
+type storeKey[T any] struct{}
+
+// anyStore is the type independent part of a store.
+type anyStore interface {
+	remove(id event.CallerID)
+	markRemoving(id event.CallerID)
+	has(id event.CallerID) bool
+	len() int
+}
+
+func newStore[T any]() *store[T] {
+	return &store[T]{
+		index:    make(map[event.CallerID]int),
+		removing: make(map[event.CallerID]struct{}),
+	}
+}
+
+func (s *store[T]) set(id event.CallerID, c T) {
+	if i, ok := s.index[id]; ok {
+		s.components[i] = c
+		return
+	}
+	s.index[id] = len(s.components)
+	s.components = append(s.components, c)
+	s.ids = append(s.ids, id)
+}
+
+func (s *store[T]) get(id event.CallerID) (*T, bool) {
+	i, ok := s.index[id]
+	if !ok {
+		return nil, false
+	}
+	if _, ok := s.removing[id]; ok {
+		return nil, false
+	}
+	return &s.components[i], true
+}
+
+func (s *store[T]) has(id event.CallerID) bool {
+	_, ok := s.get(id)
+	return ok
+}
+
+func (s *store[T]) len() int {
+	return len(s.components) - len(s.removing)
+}
+
+func (s *store[T]) markRemoving(id event.CallerID) {
+	if _, ok := s.index[id]; ok {
+		s.removing[id] = struct{}{}
+	}
+}
+
+// remove swaps the last component into the removed component's place.
+func (s *store[T]) remove(id event.CallerID) {
+	delete(s.removing, id)
+	i, ok := s.index[id]
+	if !ok {
+		return
+	}
+	last := len(s.components) - 1
+	if i != last {
+		s.components[i] = s.components[last]
+		s.ids[i] = s.ids[last]
+		s.index[s.ids[i]] = i
+	}
+	var zero T
+	s.components[last] = zero
+	s.components = s.components[:last]
+	s.ids = s.ids[:last]
+	delete(s.index, id)
+}
@@ -0,0 +1,89 @@
+package ecs
+
+import (
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+)
+
+// A System acts on the components of a world once per frame.
+type System func(w *World, ev event.EnterPayload)
+
+type scheduledSystem struct {
+	order int
+	System
+}
+
+// AddSystem schedules a system to run every frame. Systems run in ascending order;
+// systems with the same order run in the order they were added.
+func (w *World) AddSystem(order int, sys System) {
+	i := len(w.systems)
+	for j, s := range w.systems {
+		if s.order > order {
+			i = j
+			break
+		}
+	}
+	w.systems = append(w.systems, scheduledSystem{})
+	copy(w.systems[i+1:], w.systems[i:])
+	w.systems[i] = scheduledSystem{order: order, System: sys}
+}
+
+// RunSystems runs each of this world's systems once, in order.
+func (w *World) RunSystems(ev event.EnterPayload) {
+	for _, s := range w.systems {
+		s.System(w, ev)
+	}
+}
+
+// Bind causes this world to RunSystems at the start of every frame, until the
+// returned binding is unbound.
+func (w *World) Bind() event.Binding {
+	return event.GlobalBind(w.Handler, event.Enter, func(ev event.EnterPayload) event.Response {
+		w.RunSystems(ev)
+		return 0
+	})
+}
+
+// Position is a component for an entity's position.
+type Position struct {
+	floatgeom.Point2
+}
+
+// Velocity is a component for an entity's velocity, in units per second.
+type Velocity struct {
+	floatgeom.Point2
+}
+
+// Movement is a system which moves every entity with a Position by its Velocity.
+func Movement(w *World, ev event.EnterPayload) {
+	dt := ev.SinceLastFrame.Seconds()
+	Query(w, func(_ event.CallerID, p *Position, v *Velocity) {
+		p.Point2 = p.Add(v.MulConst(dt))
+	})
+}
+
+// SyncRenderables is a system which moves every entity's render.Renderable
+// component to its Position.
+func SyncRenderables(w *World, _ event.EnterPayload) {
+	Query(w, func(_ event.CallerID, p *Position, r *render.Renderable) {
+		(*r).SetPos(p.X(), p.Y())
+	})
+}
+
+// SyncSpaces returns a system which moves every entity's *collision.Space component
+// to its Position within the given tree. If tree is nil, the DefaultTree is used.
+func SyncSpaces(tree *collision.Tree) System {
+	if tree == nil {
+		tree = collision.DefaultTree
+	}
+	return func(w *World, _ event.EnterPayload) {
+		Query(w, func(_ event.CallerID, p *Position, s **collision.Space) {
+			sp := *s
+			if sp.X() != p.X() || sp.Y() != p.Y() {
+				tree.UpdateSpace(p.X(), p.Y(), sp.GetW(), sp.GetH(), sp)
+			}
+		})
+	}
+}
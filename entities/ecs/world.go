@@ -0,0 +1,173 @@
+package ecs
+
+import (
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A World holds entities' components and the systems which act on them.
+//
+// A World is not safe for concurrent use; components should be modified from
+// within systems or event bindings.
+type World struct {
+	CallerMap *event.CallerMap
+	Handler   event.Handler
+
+	stores  map[any]anyStore
+	owned   map[event.CallerID]struct{}
+	systems []scheduledSystem
+
+	// querying counts the queries in progress. While it is positive, changes which
+	// would move components are deferred until the outermost query returns.
+	querying int
+	deferred []func()
+}
+
+// An Entity is the caller registered for entities created by a World. Bindings
+// made against an entity's ID can use *Entity as their caller type.
+type Entity struct {
+	event.CallerID
+	World *World
+}
+
+// NewWorld creates a world registering entities with the given caller map and
+// running systems on the given handler. If callers is nil, the handler's caller
+// map will be used. If handler is nil, the DefaultBus will be used.
+func NewWorld(callers *event.CallerMap, handler event.Handler) *World {
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	if callers == nil {
+		callers = handler.GetCallerMap()
+	}
+	return &World{
+		CallerMap: callers,
+		Handler:   handler,
+		stores:    make(map[any]anyStore),
+		owned:     make(map[event.CallerID]struct{}),
+	}
+}
+
+// NewEntity registers a new entity with no components. Components can also be
+// added to IDs registered elsewhere, such as those of entities.Entity.
+func (w *World) NewEntity() *Entity {
+	e := &Entity{World: w}
+	e.CallerID = w.CallerMap.Register(e)
+	w.owned[e.CallerID] = struct{}{}
+	return e
+}
+
+// Destroy removes all of an entity's components. If the entity was created by this
+// world, it is also removed from the world's caller map and its bindings are unbound.
+func (w *World) Destroy(id event.CallerID) {
+	if w.querying > 0 {
+		for _, s := range w.stores {
+			s.markRemoving(id)
+		}
+		w.deferred = append(w.deferred, func() { w.Destroy(id) })
+		return
+	}
+	for _, s := range w.stores {
+		s.remove(id)
+	}
+	if _, ok := w.owned[id]; ok {
+		delete(w.owned, id)
+		w.Handler.UnbindAllFrom(id)
+		w.CallerMap.RemoveEntity(id)
+	}
+}
+
+// Alive returns whether the entity has any components.
+func (w *World) Alive(id event.CallerID) bool {
+	for _, s := range w.stores {
+		if s.has(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// beginQuery must be called before a query visits any entity, and endQuery after
+// it finishes.
+func (w *World) beginQuery() {
+	w.querying++
+}
+
+func (w *World) endQuery() {
+	w.querying--
+	if w.querying > 0 {
+		return
+	}
+	// Deferred changes may themselves defer more changes if they run queries.
+	for len(w.deferred) > 0 {
+		deferred := w.deferred
+		w.deferred = nil
+		for _, fn := range deferred {
+			fn()
+		}
+	}
+}
+
+func lookup[T any](w *World) *store[T] {
+	s, ok := w.stores[storeKey[T]{}]
+	if !ok {
+		return nil
+	}
+	return s.(*store[T])
+}
+
+// Set sets an entity's component of type T, adding it if the entity does not
+// already have one. Components added during a query are not added until the
+// query finishes.
+func Set[T any](w *World, id event.CallerID, c T) {
+	s := lookup[T](w)
+	if s == nil {
+		s = newStore[T]()
+		w.stores[storeKey[T]{}] = s
+	}
+	if w.querying > 0 && !s.has(id) {
+		w.deferred = append(w.deferred, func() { s.set(id, c) })
+		return
+	}
+	s.set(id, c)
+}
+
+// Get returns a pointer to an entity's component of type T, if it has one. The
+// pointer is only valid until a component of type T is added to or removed
+// from any entity outside of a query.
+func Get[T any](w *World, id event.CallerID) (*T, bool) {
+	s := lookup[T](w)
+	if s == nil {
+		return nil, false
+	}
+	return s.get(id)
+}
+
+// Has returns whether an entity has a component of type T.
+func Has[T any](w *World, id event.CallerID) bool {
+	s := lookup[T](w)
+	return s != nil && s.has(id)
+}
+
+// Remove removes an entity's component of type T, if it has one. Components
+// removed during a query are treated as absent immediately, but are not moved out
+// of their store until the query finishes.
+func Remove[T any](w *World, id event.CallerID) {
+	s := lookup[T](w)
+	if s == nil {
+		return
+	}
+	if w.querying > 0 {
+		s.markRemoving(id)
+		w.deferred = append(w.deferred, func() { s.remove(id) })
+		return
+	}
+	s.remove(id)
+}
+
+// Count returns how many entities have a component of type T.
+func Count[T any](w *World) int {
+	if s := lookup[T](w); s != nil {
+		return s.len()
+	}
+	return 0
+}
@@ -0,0 +1,237 @@
+package ecs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+)
+
+type health int
+
+func newTestWorld() *World {
+	return NewWorld(nil, event.NewBus(event.NewCallerMap()))
+}
+
+func TestComponents(t *testing.T) {
+	w := newTestWorld()
+	a, b, c := w.NewEntity(), w.NewEntity(), w.NewEntity()
+	Set(w, a.CID(), health(1))
+	Set(w, b.CID(), health(2))
+	Set(w, c.CID(), health(3))
+	Set(w, b.CID(), health(20))
+	if Count[health](w) != 3 {
+		t.Fatalf("expected 3 health components, got %d", Count[health](w))
+	}
+	Remove[health](w, a.CID())
+	if Has[health](w, a.CID()) {
+		t.Fatalf("removed component still present")
+	}
+	for id, want := range map[event.CallerID]health{b.CID(): 20, c.CID(): 3} {
+		h, ok := Get[health](w, id)
+		if !ok || *h != want {
+			t.Fatalf("expected health %v for %v, got %v", want, id, h)
+		}
+	}
+	if _, ok := Get[Position](w, a.CID()); ok {
+		t.Fatalf("got component of a type never set")
+	}
+	h, _ := Get[health](w, c.CID())
+	*h = 30
+	if h, _ := Get[health](w, c.CID()); *h != 30 {
+		t.Fatalf("modification through pointer was not kept")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	w := newTestWorld()
+	moving := w.NewEntity()
+	still := w.NewEntity()
+	Set(w, moving.CID(), Position{floatgeom.Point2{0, 0}})
+	Set(w, moving.CID(), Velocity{floatgeom.Point2{10, 0}})
+	Set(w, moving.CID(), health(1))
+	Set(w, still.CID(), Position{floatgeom.Point2{5, 5}})
+
+	seen := 0
+	Query(w, func(id event.CallerID, p *Position, v *Velocity) {
+		seen++
+		if id != moving.CID() {
+			t.Fatalf("query visited entity without velocity")
+		}
+	})
+	if seen != 1 {
+		t.Fatalf("expected one query match, got %d", seen)
+	}
+	seen = 0
+	Each(w, func(id event.CallerID, p *Position) {
+		seen++
+	})
+	if seen != 2 {
+		t.Fatalf("expected two positions, got %d", seen)
+	}
+	seen = 0
+	Query3(w, func(id event.CallerID, p *Position, v *Velocity, h *health) {
+		seen++
+		Remove[health](w, still.CID())
+		w.Destroy(moving.CID())
+	})
+	if seen != 1 {
+		t.Fatalf("expected one query3 match, got %d", seen)
+	}
+	if w.Alive(moving.CID()) || !w.Alive(still.CID()) {
+		t.Fatalf("destroy removed the wrong entity")
+	}
+	if w.CallerMap.HasEntity(moving.CID()) {
+		t.Fatalf("destroyed entity still in caller map")
+	}
+	Query4(w, func(event.CallerID, *Position, *Velocity, *health, *Entity) {
+		t.Fatalf("query for unset component type visited an entity")
+	})
+}
+
+func TestQueryMutation(t *testing.T) {
+	w := newTestWorld()
+	const n = 64
+	ids := make([]event.CallerID, n)
+	for i := range ids {
+		ids[i] = w.NewEntity().CID()
+		Set(w, ids[i], Position{})
+		Set(w, ids[i], health(i))
+	}
+
+	var added []event.CallerID
+	visited := map[event.CallerID]bool{}
+	Each(w, func(id event.CallerID, p *Position) {
+		visited[id] = true
+		// Adding components would grow and reallocate the store, and removing
+		// them would swap others into their place.
+		for i := 0; i < 4; i++ {
+			e := w.NewEntity().CID()
+			Set(w, e, Position{})
+			added = append(added, e)
+		}
+		h, _ := Get[health](w, id)
+		if *h%2 == 0 && *h < n/2 {
+			Remove[Position](w, ids[n-1-int(*h)])
+		}
+		if Count[Position](w) > n {
+			t.Fatalf("components added during a query were counted")
+		}
+		p.Point2 = floatgeom.Point2{float64(*h), 1}
+	})
+	for i, id := range ids {
+		removed := i%2 == 1 && i >= n/2
+		p, ok := Get[Position](w, id)
+		if removed {
+			if ok {
+				t.Fatalf("entity %d kept a removed position", i)
+			}
+			continue
+		}
+		if !ok || p.Point2 != (floatgeom.Point2{float64(i), 1}) {
+			t.Fatalf("entity %d lost a write made during the query: %v", i, p)
+		}
+		if !visited[id] {
+			t.Fatalf("entity %d was not visited", i)
+		}
+	}
+	for _, id := range added {
+		if visited[id] {
+			t.Fatalf("entity added during the query was visited")
+		}
+		if !Has[Position](w, id) {
+			t.Fatalf("component added during the query was not added after it")
+		}
+	}
+
+	// Nested queries defer changes until the outermost finishes.
+	Query(w, func(id event.CallerID, p *Position, h *health) {
+		Each(w, func(other event.CallerID, _ *health) {
+			if other != id {
+				w.Destroy(other)
+			}
+		})
+		if !w.CallerMap.HasEntity(ids[1]) && id == ids[0] {
+			t.Fatalf("entity destroyed during the query before it finished")
+		}
+		p.Point2 = floatgeom.Point2{-1, -1}
+	})
+	if got := Count[health](w); got != 1 {
+		t.Fatalf("expected one entity with health after destroying the rest, got %d", got)
+	}
+	if p, _ := Get[Position](w, ids[0]); p.Point2 != (floatgeom.Point2{-1, -1}) {
+		t.Fatalf("lost a write made during a nested query: %v", p)
+	}
+	if w.CallerMap.HasEntity(ids[1]) {
+		t.Fatalf("destroyed entity still in caller map")
+	}
+}
+
+func TestSystems(t *testing.T) {
+	w := newTestWorld()
+	var order []int
+	for _, o := range []int{2, 0, 1, 0} {
+		o := o
+		w.AddSystem(o, func(*World, event.EnterPayload) {
+			order = append(order, o)
+		})
+	}
+	w.AddSystem(1, Movement)
+	tree := collision.NewTree()
+	w.AddSystem(3, SyncSpaces(tree))
+	w.AddSystem(3, SyncRenderables)
+
+	e := w.NewEntity()
+	sp := collision.NewSpace(0, 0, 10, 10, e.CID())
+	tree.Add(sp)
+	var r render.Renderable = render.EmptyRenderable()
+	Set(w, e.CID(), Position{})
+	Set(w, e.CID(), Velocity{floatgeom.Point2{60, 30}})
+	Set(w, e.CID(), sp)
+	Set(w, e.CID(), r)
+
+	w.RunSystems(event.EnterPayload{SinceLastFrame: time.Second / 2})
+	if len(order) != 4 || order[0] != 0 || order[1] != 0 || order[2] != 1 || order[3] != 2 {
+		t.Fatalf("systems ran out of order: %v", order)
+	}
+	want := floatgeom.Point2{30, 15}
+	if p, _ := Get[Position](w, e.CID()); p.Point2 != want {
+		t.Fatalf("expected position %v, got %v", want, p.Point2)
+	}
+	if sp.X() != 30 || sp.Y() != 15 {
+		t.Fatalf("space was not synced: %v", sp.Location)
+	}
+	if len(tree.Hits(collision.NewUnassignedSpace(35, 20, 1, 1))) != 1 {
+		t.Fatalf("space was not moved within tree")
+	}
+	if r.X() != 30 || r.Y() != 15 {
+		t.Fatalf("renderable was not synced: %v,%v", r.X(), r.Y())
+	}
+}
+
+func TestWorldBind(t *testing.T) {
+	bus := event.NewBus(event.NewCallerMap())
+	w := NewWorld(nil, bus)
+	ran := make(chan struct{}, 1)
+	w.AddSystem(0, func(*World, event.EnterPayload) {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+	})
+	b := w.Bind()
+	<-b.Bound
+	<-event.TriggerOn(bus, event.Enter, event.EnterPayload{})
+	select {
+	case <-ran:
+	default:
+		t.Fatalf("systems did not run on enter")
+	}
+	e := w.NewEntity()
+	if got := bus.GetCallerMap().GetEntity(e.CID()); got != e {
+		t.Fatalf("entity was not registered with the handler's caller map")
+	}
+}
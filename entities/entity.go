@@ -25,6 +25,14 @@ type Generator struct {
 	Mod mod.Mod
 
 	Label collision.Label
+	Layer collision.Layer
+	Mask  collision.Layer
+
+	// Sensor entities do not add their space to their collision tree, and
+	// instead detect spaces entering and leaving them. See collision.Sensor.
+	Sensor       bool
+	SensorLabels []collision.Label
+	SensorMode   collision.SensorMode
 
 	DrawLayers []int
 
@@ -94,9 +102,19 @@ type Entity struct {
 	Space *collision.Space
 	Tree  *collision.Tree
 
+	// Sensor is set for entities created with WithSensor. Its space is
+	// the entity's Space.
+	Sensor *collision.Sensor
+
 	metadata map[string]string
 
 	Children []*Entity
+
+	// Transform is set for entities given a transform by NewTransform.
+	Transform *Transform
+
+	// drawLayers are the layers the entity's renderable was drawn to by New, if any.
+	drawLayers []int
 }
 
 func (e Entity) CID() event.CallerID {
@@ -134,18 +152,23 @@ func (e *Entity) ShiftDelta() {
 
 func (e *Entity) Shift(delta floatgeom.Point2) {
 	// TODO: attachment?
-	// TODO: helper
 	e.Renderable.ShiftX(delta.X())
 	e.Renderable.ShiftY(delta.Y())
 	e.Rect = e.Rect.Shift(delta)
-	if e.Tree != nil {
+	e.updateSpace()
+	for _, c := range e.Children {
+		c.Shift(delta)
+	}
+}
+
+func (e *Entity) updateSpace() {
+	if e.Sensor != nil {
+		e.Space.Location = collision.NewRect(e.X(), e.Y(), e.W(), e.H())
+	} else if e.Tree != nil {
 		e.Tree.UpdateSpace(
 			e.X(), e.Y(), e.W(), e.H(), e.Space,
 		)
 	}
-	for _, c := range e.Children {
-		c.Shift(delta)
-	}
 }
 
 func (e *Entity) SetX(x float64) {
@@ -159,11 +182,7 @@ func (e *Entity) SetY(y float64) {
 func (e *Entity) ShiftX(x float64) {
 	e.Renderable.ShiftX(x)
 	e.Rect = e.Rect.Shift(floatgeom.Point2{x, 0})
-	if e.Tree != nil {
-		e.Tree.UpdateSpace(
-			e.X(), e.Y(), e.W(), e.H(), e.Space,
-		)
-	}
+	e.updateSpace()
 	for _, c := range e.Children {
 		c.ShiftX(x)
 	}
@@ -172,11 +191,7 @@ func (e *Entity) ShiftX(x float64) {
 func (e *Entity) ShiftY(y float64) {
 	e.Renderable.ShiftY(y)
 	e.Rect = e.Rect.Shift(floatgeom.Point2{0, y})
-	if e.Tree != nil {
-		e.Tree.UpdateSpace(
-			e.X(), e.Y(), e.W(), e.H(), e.Space,
-		)
-	}
+	e.updateSpace()
 	for _, c := range e.Children {
 		c.ShiftY(y)
 	}
@@ -194,7 +209,12 @@ func (e *Entity) HitLabel(label collision.Label) *collision.Space {
 	return e.Tree.HitLabel(e.Space, label)
 }
 
+// Destroy undraws this entity, removes its space, and unbinds its handlers. If the
+// entity has a Transform, every entity beneath it is destroyed as well.
 func (e *Entity) Destroy() {
+	if e.Transform != nil {
+		e.Transform.destroy()
+	}
 	e.Renderable.Undraw()
 	e.Tree.Remove(e.Space)
 	e.ctx.UnbindAllFrom(e.CallerID)
@@ -277,12 +297,38 @@ func New(ctx *scene.Context, opts ...Option) *Entity {
 			e.X(), e.Y(), e.W(), e.H(), e.CallerID,
 		)
 		e.Space.Label = g.Label
-		e.Tree.Add(e.Space)
+		e.Space.Layer = g.Layer
+		e.Space.Mask = g.Mask
+		if g.Sensor {
+			e.Sensor = collision.NewSensor(e.Space, e.Tree, g.SensorMode, g.SensorLabels...)
+			e.Sensor.Bind(ctx)
+		} else {
+			e.Tree.Add(e.Space)
+		}
 	}
 
 	if len(g.DrawLayers) != 0 && e.Renderable != nil {
 		ctx.Draw(e.Renderable, g.DrawLayers...)
+		e.drawLayers = g.DrawLayers
 	}
 
 	return e
 }
+
+// hitsAt returns the spaces in the entity's tree which the entity's space would
+// hit if it were at r, excluding the entity's own space.
+func (e *Entity) hitsAt(r floatgeom.Rect2) []*collision.Space {
+	if e.Tree == nil || e.Space == nil {
+		return nil
+	}
+	probe := *e.Space
+	probe.Location = collision.NewRect(r.Min.X(), r.Min.Y(), r.W(), r.H())
+	hits := e.Tree.Hits(&probe)
+	out := hits[:0]
+	for _, s := range hits {
+		if s != e.Space {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+func testContext() *scene.Context {
+	callers := event.NewCallerMap()
+	ks := key.NewState()
+	return &scene.Context{
+		CallerMap:     callers,
+		Handler:       event.NewBus(callers),
+		DrawStack:     render.NewDrawStack(render.NewDynamicHeap()),
+		State:         &ks,
+		CollisionTree: collision.NewTree(),
+	}
+}
+
+func testEntity(ctx *scene.Context, x, y, w, h float64, opts ...Option) *Entity {
+	opts = append([]Option{
+		WithRect(floatgeom.NewRect2WH(x, y, w, h)),
+		WithColor(color.RGBA{255, 0, 0, 255}),
+	}, opts...)
+	return New(ctx, opts...)
+}
+
+func TestEntitySetPos(t *testing.T) {
+	ctx := testContext()
+	e := testEntity(ctx, 0, 0, 10, 10)
+	e.SetPos(floatgeom.Point2{20, 30})
+	if e.X() != 20 || e.Y() != 30 {
+		t.Fatalf("entity at %v,%v, expected 20,30", e.X(), e.Y())
+	}
+	if e.Space.X() != 20 || e.Space.Y() != 30 {
+		t.Fatalf("entity space at %v,%v, expected 20,30", e.Space.X(), e.Space.Y())
+	}
+	if r := e.Renderable; r.X() != 20 || r.Y() != 30 {
+		t.Fatalf("entity renderable at %v,%v, expected 20,30", r.X(), r.Y())
+	}
+}
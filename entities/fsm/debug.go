@@ -0,0 +1,112 @@
+package fsm
+
+import (
+	"strings"
+	"time"
+
+	"github.com/oakmound/oak/v4/debugstream"
+)
+
+// recentLimit is how many transitions a machine remembers for debugging.
+const recentLimit = 10
+
+func (m *Machine[C]) record(from, to string) {
+	if from == "" {
+		from = "<start>"
+	}
+	m.recent = append(m.recent, from+" -> "+to)
+	if len(m.recent) > recentLimit {
+		m.recent = m.recent[len(m.recent)-recentLimit:]
+	}
+}
+
+// debugTimeout is how long a debug command waits for its machine to update.
+var debugTimeout = time.Second
+
+// A debugRequest is a debug command waiting for its machine's next Update.
+type debugRequest struct {
+	args  []string
+	reply chan string
+}
+
+// DebugCommand returns a debugstream command with the given name describing this
+// machine. With no arguments it prints the active states and recent transitions;
+// 'fire <trigger>' fires a trigger and 'set <state>' transitions to a state.
+//
+// The command is run by the machine's next Update, so that it does not race with
+// the machine's caller. If the machine does not update within a second, the
+// command gives up.
+func (m *Machine[C]) DebugCommand(name string) debugstream.Command {
+	return debugstream.Command{
+		Name:  name,
+		Usage: "[fire <trigger> | set <state>]: inspect or drive a state machine",
+		Operation: func(args []string) string {
+			req := debugRequest{args: args, reply: make(chan string, 1)}
+			m.debugMu.Lock()
+			m.debugQueue = append(m.debugQueue, req)
+			m.debugMu.Unlock()
+			select {
+			case out := <-req.reply:
+				return out
+			case <-time.After(debugTimeout):
+			}
+			m.debugMu.Lock()
+			for i, r := range m.debugQueue {
+				if r.reply == req.reply {
+					m.debugQueue = append(m.debugQueue[:i], m.debugQueue[i+1:]...)
+					m.debugMu.Unlock()
+					return "machine is not updating"
+				}
+			}
+			m.debugMu.Unlock()
+			// the request was taken by an update in progress
+			return <-req.reply
+		},
+	}
+}
+
+// runDebugRequests runs debug commands queued since the last Update.
+func (m *Machine[C]) runDebugRequests() {
+	m.debugMu.Lock()
+	queue := m.debugQueue
+	m.debugQueue = nil
+	m.debugMu.Unlock()
+	for _, req := range queue {
+		req.reply <- m.debug(req.args)
+	}
+}
+
+func (m *Machine[C]) debug(args []string) string {
+	if len(args) == 2 {
+		switch args[0] {
+		case "fire":
+			if !m.Fire(args[1]) {
+				return "no transition taken"
+			}
+		case "set":
+			if err := m.Transition(args[1]); err != nil {
+				return err.Error()
+			}
+		default:
+			return "unknown subcommand " + args[0]
+		}
+	} else if len(args) != 0 {
+		return "expected no arguments or a subcommand and its argument"
+	}
+	return m.describe()
+}
+
+func (m *Machine[C]) describe() string {
+	sb := strings.Builder{}
+	sb.WriteString("active: ")
+	if m.active == nil {
+		sb.WriteString("<not started>")
+	} else {
+		sb.WriteString(strings.Join(m.ActivePath(), "/"))
+	}
+	for _, r := range m.recent {
+		sb.WriteString("\n  ")
+		sb.WriteString(r)
+	}
+	return sb.String()
+}
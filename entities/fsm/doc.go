@@ -0,0 +1,11 @@
+// Package fsm provides finite and hierarchical state machines bound to callers.
+//
+// A Machine holds a tree of named states. The active state is always a leaf of
+// that tree, and every ancestor of the active state is also active. Entering a
+// state with children enters its initial child, or, if the state keeps history,
+// the child which was active when the state was last exited.
+//
+// Bindings made with Bind while a state is being entered belong to that state,
+// and are unbound when it is exited, so states need not track their own handlers.
+// Bindings made while a state is being exited belong to no state.
+package fsm
@@ -0,0 +1,331 @@
+package fsm
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// A Machine tracks the active state of a caller and moves it between states.
+//
+// A Machine is not safe for concurrent use; it should be driven from the bindings
+// of its caller. Transitions requested from within state hooks are taken once the
+// current transition completes.
+type Machine[C event.Caller] struct {
+	Caller  C
+	Handler event.Handler
+
+	// OnTransition, if set, is called after each transition with the names of the
+	// previously and newly active leaf states. from is empty for the first state
+	// entered by Start.
+	OnTransition func(from, to string)
+
+	states      map[string]*State[C]
+	top         []*State[C]
+	transitions []Transition[C]
+
+	active   *State[C]
+	entering *State[C]
+	exiting  bool
+
+	transitioning bool
+	pending       []*State[C]
+
+	recent []string
+
+	debugMu    sync.Mutex
+	debugQueue []debugRequest
+}
+
+// NewMachine creates a machine for a caller, binding state handlers on the given
+// handler. If handler is nil, the DefaultBus will be used.
+func NewMachine[C event.Caller](handler event.Handler, caller C) *Machine[C] {
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	return &Machine[C]{
+		Caller:  caller,
+		Handler: handler,
+		states:  make(map[string]*State[C]),
+	}
+}
+
+// AddState adds top level states to the machine. Adding a state with the name of
+// an existing state returns an ExistingElement error.
+func (m *Machine[C]) AddState(states ...*State[C]) error {
+	for _, s := range states {
+		if err := m.add(s); err != nil {
+			return err
+		}
+		m.top = append(m.top, s)
+	}
+	return nil
+}
+
+// AddSubstate adds states nested within the named parent state.
+func (m *Machine[C]) AddSubstate(parent string, states ...*State[C]) error {
+	p, ok := m.states[parent]
+	if !ok {
+		return oakerr.NotFound{InputName: parent}
+	}
+	for _, s := range states {
+		if err := m.add(s); err != nil {
+			return err
+		}
+		s.parent = p
+		p.children = append(p.children, s)
+	}
+	return nil
+}
+
+func (m *Machine[C]) add(s *State[C]) error {
+	if _, ok := m.states[s.Name]; ok {
+		return oakerr.ExistingElement{InputName: s.Name, InputType: "state"}
+	}
+	m.states[s.Name] = s
+	return nil
+}
+
+// State returns the named state, if it exists.
+func (m *Machine[C]) State(name string) (*State[C], bool) {
+	s, ok := m.states[name]
+	return s, ok
+}
+
+// AddTransition adds transitions to the machine. Transitions naming states which
+// do not exist return a NotFound error.
+func (m *Machine[C]) AddTransition(ts ...Transition[C]) error {
+	for _, t := range ts {
+		if _, ok := m.states[t.From]; t.From != "" && !ok {
+			return oakerr.NotFound{InputName: t.From}
+		}
+		if _, ok := m.states[t.To]; !ok {
+			return oakerr.NotFound{InputName: t.To}
+		}
+		m.transitions = append(m.transitions, t)
+	}
+	return nil
+}
+
+// Start enters the named state, or the first top level state if name is empty.
+// Start may also be used to restart a machine, exiting all active states. If any
+// state's Initial does not name one of its children, Start returns a NotFound
+// error without entering any state.
+func (m *Machine[C]) Start(name string) error {
+	for _, s := range m.states {
+		if s.Initial == "" {
+			continue
+		}
+		if _, ok := s.child(s.Initial); !ok {
+			return oakerr.NotFound{InputName: s.Name + ".Initial"}
+		}
+	}
+	if name == "" {
+		if len(m.top) == 0 {
+			return oakerr.InsufficientInputs{AtLeast: 1, InputName: "states"}
+		}
+		name = m.top[0].Name
+	}
+	return m.Transition(name)
+}
+
+// Active returns the name of the active leaf state, or an empty string if the
+// machine has not started.
+func (m *Machine[C]) Active() string {
+	if m.active == nil {
+		return ""
+	}
+	return m.active.Name
+}
+
+// ActivePath returns the names of all active states, from the top level down.
+func (m *Machine[C]) ActivePath() []string {
+	if m.active == nil {
+		return nil
+	}
+	path := m.active.path()
+	names := make([]string, len(path))
+	for i, s := range path {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// IsIn returns whether the named state is active, either as the leaf state or as
+// one of its ancestors.
+func (m *Machine[C]) IsIn(name string) bool {
+	for s := m.active; s != nil; s = s.parent {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the machine to the named state, exiting active states which
+// do not contain it and entering those which do. Transitioning to an active
+// state exits and re-enters it. Guards are not checked.
+func (m *Machine[C]) Transition(to string) error {
+	s, ok := m.states[to]
+	if !ok {
+		return oakerr.NotFound{InputName: to}
+	}
+	m.pending = append(m.pending, s)
+	if m.transitioning {
+		return nil
+	}
+	m.transitioning = true
+	defer func() { m.transitioning = false }()
+	for len(m.pending) != 0 {
+		next := m.pending[0]
+		m.pending = m.pending[1:]
+		m.transition(next)
+	}
+	return nil
+}
+
+func (m *Machine[C]) transition(to *State[C]) {
+	from := m.active
+	dst := to.path()
+	var src []*State[C]
+	if from != nil {
+		src = from.path()
+	}
+	common := 0
+	for common < len(src) && common < len(dst) && src[common] == dst[common] {
+		common++
+	}
+	if common == len(dst) {
+		// the target is active; re-enter it
+		common--
+	}
+	for i := len(src) - 1; i >= common; i-- {
+		m.exit(src[i])
+	}
+	for _, s := range dst[common:] {
+		m.enter(s)
+	}
+	leaf := to
+	deep := false
+	for len(leaf.children) != 0 {
+		deep = deep || leaf.History == DeepHistory
+		next := leaf.initial()
+		if leaf.last != nil && (deep || leaf.History == ShallowHistory) {
+			next = leaf.last
+		}
+		m.enter(next)
+		leaf = next
+	}
+	m.active = leaf
+	fromName := ""
+	if from != nil {
+		fromName = from.Name
+	}
+	m.record(fromName, leaf.Name)
+	if m.OnTransition != nil {
+		m.OnTransition(fromName, leaf.Name)
+	}
+}
+
+func (m *Machine[C]) enter(s *State[C]) {
+	m.entering = s
+	if s.OnEnter != nil {
+		s.OnEnter(m)
+	}
+	m.entering = nil
+}
+
+func (m *Machine[C]) exit(s *State[C]) {
+	for _, b := range s.bindings {
+		b.Unbind()
+	}
+	s.bindings = nil
+	if s.OnExit != nil {
+		m.exiting = true
+		s.OnExit(m)
+		m.exiting = false
+	}
+	if s.parent != nil {
+		s.parent.last = s
+	}
+}
+
+// Fire takes the first transition with the given trigger whose guard passes,
+// returning whether a transition was taken. Transitions from deeper active
+// states are preferred, then transitions in the order they were added.
+func (m *Machine[C]) Fire(trigger string) bool {
+	if t, ok := m.find(trigger); ok {
+		m.Transition(t.To)
+		return true
+	}
+	return false
+}
+
+func (m *Machine[C]) find(trigger string) (Transition[C], bool) {
+	if m.active == nil {
+		return Transition[C]{}, false
+	}
+	matches := func(t Transition[C], from string) bool {
+		return t.Trigger == trigger && t.From == from && (t.Guard == nil || t.Guard(m))
+	}
+	for s := m.active; s != nil; s = s.parent {
+		for _, t := range m.transitions {
+			if matches(t, s.Name) {
+				return t, true
+			}
+		}
+	}
+	for _, t := range m.transitions {
+		if matches(t, "") {
+			return t, true
+		}
+	}
+	return Transition[C]{}, false
+}
+
+// Update runs any pending debug commands, takes the first untriggered transition
+// whose guard passes, then calls the OnUpdate hook of each active state.
+func (m *Machine[C]) Update(ev event.EnterPayload) {
+	m.runDebugRequests()
+	if t, ok := m.find(""); ok {
+		m.Transition(t.To)
+	}
+	if m.active == nil {
+		return
+	}
+	for _, s := range m.active.path() {
+		if s.OnUpdate != nil {
+			s.OnUpdate(m, ev)
+		}
+	}
+}
+
+// Bind causes this machine to Update every frame, until the returned binding is
+// unbound or the caller's bindings are unbound.
+func (m *Machine[C]) Bind() event.Binding {
+	return m.Handler.UnsafeBind(event.Enter.UnsafeEventID, m.Caller.CID(), func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		m.Update(payload.(event.EnterPayload))
+		return 0
+	})
+}
+
+// Bind binds fn to an event for the machine's caller. If called while a state is
+// being entered, usually from its OnEnter hook, the binding is unbound when that
+// state is exited. If called from an OnExit hook, the binding belongs to no state
+// and lasts until it or the caller's bindings are unbound. Otherwise it belongs to
+// the active leaf state.
+func Bind[C event.Caller, Payload any](m *Machine[C], ev event.EventID[Payload], fn event.Bindable[C, Payload]) event.Binding {
+	b := event.Bind(m.Handler, ev, m.Caller, fn)
+	if m.exiting {
+		return b
+	}
+	owner := m.entering
+	if owner == nil {
+		owner = m.active
+	}
+	if owner != nil {
+		owner.bindings = append(owner.bindings, b)
+	}
+	return b
+}
@@ -0,0 +1,236 @@
+package fsm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+)
+
+type guard struct {
+	event.CallerID
+	alert bool
+	log   []string
+}
+
+func newTestMachine(t *testing.T) (*Machine[*guard], *guard) {
+	t.Helper()
+	h := event.NewBus(event.NewCallerMap())
+	g := &guard{}
+	g.CallerID = h.GetCallerMap().Register(g)
+	m := NewMachine(h, g)
+	logged := func(name string) *State[*guard] {
+		return &State[*guard]{
+			Name:    name,
+			OnEnter: func(m *Machine[*guard]) { m.Caller.log = append(m.Caller.log, "enter "+name) },
+			OnExit:  func(m *Machine[*guard]) { m.Caller.log = append(m.Caller.log, "exit "+name) },
+		}
+	}
+	patrol := logged("patrol")
+	patrol.History = ShallowHistory
+	must(t, m.AddState(patrol, logged("combat")))
+	must(t, m.AddSubstate("patrol", logged("walk"), logged("look")))
+	must(t, m.AddSubstate("combat", logged("chase"), logged("attack")))
+	must(t, m.AddTransition(
+		Transition[*guard]{From: "walk", To: "look", Trigger: "turn"},
+		Transition[*guard]{From: "look", To: "walk", Trigger: "turn"},
+		Transition[*guard]{From: "patrol", To: "combat", Guard: func(m *Machine[*guard]) bool { return m.Caller.alert }},
+		Transition[*guard]{To: "patrol", Trigger: "calm"},
+	))
+	return m, g
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMachineTransitions(t *testing.T) {
+	m, g := newTestMachine(t)
+	must(t, m.Start(""))
+	if got := m.ActivePath(); !reflect.DeepEqual(got, []string{"patrol", "walk"}) {
+		t.Fatalf("unexpected starting path %v", got)
+	}
+	if !m.Fire("turn") || m.Active() != "look" {
+		t.Fatalf("expected turn to move to look, was in %v", m.Active())
+	}
+	m.Update(event.EnterPayload{})
+	if m.Active() != "look" {
+		t.Fatalf("guarded transition taken with guard failing")
+	}
+	g.alert = true
+	m.Update(event.EnterPayload{})
+	if m.Active() != "chase" || !m.IsIn("combat") || m.IsIn("patrol") {
+		t.Fatalf("expected guarded transition to combat, was in %v", m.ActivePath())
+	}
+	g.log = nil
+	g.alert = false
+	if !m.Fire("calm") {
+		t.Fatalf("expected transition from any state to be taken")
+	}
+	// patrol keeps history, so look is re-entered rather than walk
+	want := []string{"exit chase", "exit combat", "enter patrol", "enter look"}
+	if !reflect.DeepEqual(g.log, want) {
+		t.Fatalf("expected hooks %v, got %v", want, g.log)
+	}
+	if m.Fire("missing") {
+		t.Fatalf("unknown trigger took a transition")
+	}
+	if err := m.Transition("missing"); err == nil {
+		t.Fatalf("expected error transitioning to unknown state")
+	}
+}
+
+func TestMachineDeepHistory(t *testing.T) {
+	h := event.NewBus(event.NewCallerMap())
+	g := &guard{}
+	g.CallerID = h.GetCallerMap().Register(g)
+	m := NewMachine(h, g)
+	must(t, m.AddState(&State[*guard]{Name: "a", History: DeepHistory}, &State[*guard]{Name: "b"}))
+	must(t, m.AddSubstate("a", &State[*guard]{Name: "a1"}, &State[*guard]{Name: "a2"}))
+	must(t, m.AddSubstate("a2", &State[*guard]{Name: "x"}, &State[*guard]{Name: "y"}))
+	must(t, m.Start("y"))
+	must(t, m.Transition("b"))
+	must(t, m.Transition("a"))
+	if m.Active() != "y" {
+		t.Fatalf("expected deep history to restore y, got %v", m.Active())
+	}
+}
+
+func TestMachineSelfAndNestedTransitions(t *testing.T) {
+	m, g := newTestMachine(t)
+	must(t, m.Start("walk"))
+	g.log = nil
+	must(t, m.Transition("patrol"))
+	want := []string{"exit walk", "exit patrol", "enter patrol", "enter walk"}
+	if !reflect.DeepEqual(g.log, want) {
+		t.Fatalf("expected hooks %v, got %v", want, g.log)
+	}
+	s, _ := m.State("look")
+	s.OnEnter = func(m *Machine[*guard]) { m.Transition("attack") }
+	g.log = nil
+	must(t, m.Transition("look"))
+	if m.Active() != "attack" {
+		t.Fatalf("expected transition from hook to be taken, was in %v", m.Active())
+	}
+	want = []string{"exit walk", "exit look", "exit patrol", "enter combat", "enter attack"}
+	if !reflect.DeepEqual(g.log, want) {
+		t.Fatalf("expected hooks %v, got %v", want, g.log)
+	}
+}
+
+func TestMachineBindings(t *testing.T) {
+	m, g := newTestMachine(t)
+	ev := event.RegisterEvent[int]()
+	total := 0
+	var b event.Binding
+	s, _ := m.State("combat")
+	s.OnEnter = func(m *Machine[*guard]) {
+		b = Bind(m, ev, func(_ *guard, n int) event.Response {
+			total += n
+			return 0
+		})
+		<-b.Bound
+	}
+	must(t, m.Start("combat"))
+	<-event.TriggerForCallerOn(m.Handler, g.CID(), ev, 2)
+	if total != 2 {
+		t.Fatalf("expected state binding to be triggered, total was %v", total)
+	}
+	must(t, m.Transition("patrol"))
+	// wait for the unbind made on exit by unbinding again
+	<-b.Unbind()
+	<-event.TriggerForCallerOn(m.Handler, g.CID(), ev, 2)
+	if total != 2 {
+		t.Fatalf("expected state binding to be unbound on exit, total was %v", total)
+	}
+}
+
+func TestMachineExitBindings(t *testing.T) {
+	m, g := newTestMachine(t)
+	ev := event.RegisterEvent[int]()
+	total := 0
+	s, _ := m.State("combat")
+	s.OnExit = func(m *Machine[*guard]) {
+		<-Bind(m, ev, func(_ *guard, n int) event.Response {
+			total += n
+			return 0
+		}).Bound
+	}
+	must(t, m.Start("combat"))
+	must(t, m.Transition("patrol"))
+	<-event.TriggerForCallerOn(m.Handler, g.CID(), ev, 3)
+	if total != 3 {
+		t.Fatalf("expected binding made on exit to outlive the exit, total was %v", total)
+	}
+	if !m.Fire("turn") {
+		t.Fatalf("expected turn to be taken")
+	}
+	<-event.TriggerForCallerOn(m.Handler, g.CID(), ev, 3)
+	if total != 6 {
+		t.Fatalf("expected binding made on exit to belong to no state, total was %v", total)
+	}
+}
+
+func TestMachineUnknownInitial(t *testing.T) {
+	m, _ := newTestMachine(t)
+	s, _ := m.State("combat")
+	s.Initial = "atack"
+	if err := m.Start("combat"); err == nil {
+		t.Fatalf("expected error for misspelled initial state")
+	}
+	if m.Active() != "" {
+		t.Fatalf("machine should not start with an invalid initial state")
+	}
+	s.Initial = "attack"
+	must(t, m.Start("combat"))
+	if m.Active() != "attack" {
+		t.Fatalf("expected initial state attack, got %v", m.Active())
+	}
+}
+
+func TestMachineDebugCommand(t *testing.T) {
+	m, _ := newTestMachine(t)
+	cmd := m.DebugCommand("guard")
+
+	defer func(timeout time.Duration) { debugTimeout = timeout }(debugTimeout)
+	debugTimeout = 10 * time.Millisecond
+	if out := cmd.Operation(nil); out != "machine is not updating" {
+		t.Fatalf("unexpected output without updates: %v", out)
+	}
+	if len(m.debugQueue) != 0 {
+		t.Fatalf("timed out debug command was left queued")
+	}
+	debugTimeout = time.Second
+
+	// drive the machine from its own goroutine, as a scene's frame loop would
+	m.Bind()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				<-event.TriggerForCallerOn(m.Handler, m.Caller.CID(), event.Enter, event.EnterPayload{})
+			}
+		}
+	}()
+
+	if out := cmd.Operation(nil); !strings.Contains(out, "not started") {
+		t.Fatalf("unexpected output before start: %v", out)
+	}
+	cmd.Operation([]string{"set", "patrol"})
+	cmd.Operation([]string{"fire", "turn"})
+	out := cmd.Operation([]string{"set", "attack"})
+	for _, want := range []string{"combat/attack", "walk -> look", "look -> attack"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package fsm
+
+import (
+	"github.com/oakmound/oak/v4/event"
+)
+
+// History controls which child is entered when a state with children is entered.
+type History int
+
+const (
+	// NoHistory states always enter their initial child.
+	NoHistory History = iota
+	// ShallowHistory states enter the child which was last active within them.
+	// That child enters its own children as normal.
+	ShallowHistory
+	// DeepHistory states restore the last active state within them at every level.
+	DeepHistory
+)
+
+// A State is a named node in a machine's state tree. Its hooks are each optional.
+type State[C event.Caller] struct {
+	Name string
+
+	// Initial names the child entered when this state is entered. If empty, the
+	// first child added is used. Machine.Start fails if it names no child.
+	Initial string
+	History History
+
+	// OnEnter is called when the state is entered, after its parent's OnEnter
+	// and before its children's.
+	OnEnter func(*Machine[C])
+	// OnExit is called when the state is exited, after its children's OnExit and
+	// after the bindings it owns have been unbound.
+	OnExit func(*Machine[C])
+	// OnUpdate is called each update while the state is active, after its
+	// parent's OnUpdate.
+	OnUpdate func(*Machine[C], event.EnterPayload)
+
+	parent   *State[C]
+	children []*State[C]
+	last     *State[C]
+	bindings []event.Binding
+}
+
+// Parent returns the state this state is nested in, or nil for top level states.
+func (s *State[C]) Parent() *State[C] {
+	return s.parent
+}
+
+func (s *State[C]) initial() *State[C] {
+	if c, ok := s.child(s.Initial); ok {
+		return c
+	}
+	return s.children[0]
+}
+
+func (s *State[C]) child(name string) (*State[C], bool) {
+	for _, c := range s.children {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// path returns this state and its ancestors, from the top level down.
+func (s *State[C]) path() []*State[C] {
+	var p []*State[C]
+	for st := s; st != nil; st = st.parent {
+		p = append(p, st)
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+// A Transition moves a machine from one state to another. Transitions with a
+// Trigger are taken when that trigger is fired; transitions without one are
+// checked on every update.
+type Transition[C event.Caller] struct {
+	// From names the state this transition leaves. It applies while that state or
+	// any state within it is active. If empty, it applies from any state.
+	From string
+	To   string
+
+	Trigger string
+	// Guard, if set, must return true for the transition to be taken.
+	Guard func(*Machine[C]) bool
+}
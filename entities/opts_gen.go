@@ -70,6 +70,41 @@ func WithLabel(v collision.Label) Option {
 	}
 }
 
+func WithLayer(v collision.Layer) Option {
+	return func(s Generator) Generator {
+		s.Layer = v
+		return s
+	}
+}
+
+func WithMask(v collision.Layer) Option {
+	return func(s Generator) Generator {
+		s.Mask = v
+		return s
+	}
+}
+
+func WithSensor(v bool) Option {
+	return func(s Generator) Generator {
+		s.Sensor = v
+		return s
+	}
+}
+
+func WithSensorLabels(v []collision.Label) Option {
+	return func(s Generator) Generator {
+		s.SensorLabels = v
+		return s
+	}
+}
+
+func WithSensorMode(v collision.SensorMode) Option {
+	return func(s Generator) Generator {
+		s.SensorMode = v
+		return s
+	}
+}
+
 func WithDrawLayers(v []int) Option {
 	return func(s Generator) Generator {
 		s.DrawLayers = v
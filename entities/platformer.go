@@ -0,0 +1,433 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+)
+
+// Platformer events, triggered on a platformer's entity.
+var (
+	// PlatformerLanded: triggered when the entity lands on the ground, with the space landed on.
+	PlatformerLanded = event.RegisterEvent[*collision.Space]()
+	// PlatformerLeftGround: triggered when the entity stops being on the ground, by jumping or otherwise.
+	PlatformerLeftGround = event.RegisterEvent[struct{}]()
+	// PlatformerJumped: triggered when the entity jumps off of the ground.
+	PlatformerJumped = event.RegisterEvent[struct{}]()
+	// PlatformerWallJumped: triggered when the entity jumps off of a wall, with the side
+	// the wall was on (-1 for left, 1 for right).
+	PlatformerWallJumped = event.RegisterEvent[int]()
+	// PlatformerHitWall: triggered when the entity starts touching a wall, with the wall's space.
+	PlatformerHitWall = event.RegisterEvent[*collision.Space]()
+	// PlatformerHitCeiling: triggered when the entity starts touching a ceiling, with the ceiling's space.
+	PlatformerHitCeiling = event.RegisterEvent[*collision.Space]()
+)
+
+// PlatformerInput is the input state a Platformer reads each frame.
+type PlatformerInput struct {
+	Left, Right bool
+	// Down, while standing on a one way platform, drops through that platform.
+	Down bool
+	Jump bool
+}
+
+// collisionSkin is how far spaces must overlap to block a controller, and how far
+// away spaces can be to be considered touching.
+const collisionSkin = .01
+
+// A Platformer is a kinematic platformer character controller. It moves its entity
+// with gravity and jumps, resolving that movement against the spaces in the entity's
+// collision tree.
+//
+// Slopes are supported as stairs of spaces no taller than MaxStep, which the entity
+// will walk up and down smoothly.
+type Platformer struct {
+	*Entity
+
+	// SolidLabels are the labels of spaces which block movement. If empty, all spaces
+	// which are not one way platforms block movement.
+	SolidLabels []collision.Label
+	// OneWayLabels are the labels of platforms which only block falling onto them
+	// from above.
+	OneWayLabels []collision.Label
+
+	// Input is called each frame to get the entity's input.
+	Input func() PlatformerInput
+
+	// Velocity is the entity's current velocity, in units per second.
+	Velocity floatgeom.Point2
+
+	// Gravity is the entity's downward acceleration in units per second squared.
+	Gravity      float64
+	MaxFallSpeed float64
+
+	// MoveSpeed is the entity's maximum horizontal speed. Acceleration and
+	// AirAcceleration are how quickly it reaches that speed, or stops, on the ground
+	// and in the air. Zero accelerations change speed instantly.
+	MoveSpeed       float64
+	Acceleration    float64
+	AirAcceleration float64
+
+	// JumpHeight is how high a full jump goes.
+	JumpHeight float64
+	// JumpCut multiplies the entity's upwards velocity if jump is released before
+	// the peak of a jump, allowing for variable jump heights.
+	JumpCut float64
+	// CoyoteTime is how long after walking off of a ledge the entity can still jump.
+	CoyoteTime time.Duration
+	// JumpBuffer is how long before landing a jump press will still cause a jump.
+	JumpBuffer time.Duration
+
+	// MaxStep is the tallest ledge the entity will walk up, or snap down to,
+	// without jumping or falling.
+	MaxStep float64
+
+	// WallSlideSpeed, if positive, limits falling speed while pushing into a wall.
+	WallSlideSpeed float64
+	// WallJump, if not zero, is the velocity given when jumping off of a wall. Its X
+	// component is directed away from the wall and its Y component upwards.
+	WallJump floatgeom.Point2
+
+	grounded  bool
+	onWall    int
+	onCeiling bool
+	ground    *collision.Space
+	groundLoc floatgeom.Rect3
+	dropping  *collision.Space
+	carrier   *collision.Space
+
+	jumpWasDown      bool
+	jumping          bool
+	jumped           bool
+	sinceGrounded    time.Duration
+	sinceJumpPressed time.Duration
+}
+
+// NewPlatformer creates a platformer controller for an entity, reading input from
+// A, D, S and Spacebar. The entity's Speed is not used.
+func NewPlatformer(e *Entity) *Platformer {
+	p := &Platformer{
+		Entity:           e,
+		Gravity:          1500,
+		MaxFallSpeed:     800,
+		MoveSpeed:        200,
+		Acceleration:     2000,
+		AirAcceleration:  1200,
+		JumpHeight:       80,
+		JumpCut:          .5,
+		CoyoteTime:       100 * time.Millisecond,
+		JumpBuffer:       100 * time.Millisecond,
+		MaxStep:          4,
+		WallSlideSpeed:   100,
+		WallJump:         floatgeom.Point2{250, 400},
+		sinceGrounded:    math.MaxInt64,
+		sinceJumpPressed: math.MaxInt64,
+	}
+	p.KeyInput(key.A, key.D, key.S, key.Spacebar)
+	return p
+}
+
+// KeyInput sets this platformer to read its input from the given keys.
+func (p *Platformer) KeyInput(left, right, down, jump key.Code) {
+	p.Input = func() PlatformerInput {
+		return PlatformerInput{
+			Left:  p.ctx.IsDown(left),
+			Right: p.ctx.IsDown(right),
+			Down:  p.ctx.IsDown(down),
+			Jump:  p.ctx.IsDown(jump),
+		}
+	}
+}
+
+// Grounded returns whether the entity is standing on the ground.
+func (p *Platformer) Grounded() bool {
+	return p.grounded
+}
+
+// Ground returns the space the entity is standing on, if any.
+func (p *Platformer) Ground() *collision.Space {
+	if !p.grounded {
+		return nil
+	}
+	return p.ground
+}
+
+// OnWall returns which side of the entity is touching a wall: -1 for left, 1 for
+// right, or 0 if neither.
+func (p *Platformer) OnWall() int {
+	return p.onWall
+}
+
+// OnCeiling returns whether the top of the entity is touching a ceiling.
+func (p *Platformer) OnCeiling() bool {
+	return p.onCeiling
+}
+
+// Bind causes this platformer to Update every frame, until the returned binding
+// is unbound or the entity's bindings are unbound.
+func (p *Platformer) Bind() event.Binding {
+	return p.ctx.UnsafeBind(event.Enter.UnsafeEventID, p.CID(), func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		p.Update(payload.(event.EnterPayload).SinceLastFrame)
+		return 0
+	})
+}
+
+// Update moves the entity by the given elapsed time.
+func (p *Platformer) Update(elapsed time.Duration) {
+	dt := elapsed.Seconds()
+	in := PlatformerInput{}
+	if p.Input != nil {
+		in = p.Input()
+	}
+
+	if in.Jump && !p.jumpWasDown {
+		p.sinceJumpPressed = 0
+	} else if p.sinceJumpPressed < math.MaxInt64-elapsed {
+		p.sinceJumpPressed += elapsed
+	}
+	p.jumpWasDown = in.Jump
+	if !p.grounded && p.sinceGrounded < math.MaxInt64-elapsed {
+		p.sinceGrounded += elapsed
+	}
+
+	if p.grounded && p.ground != nil && p.ground.Location != p.groundLoc {
+		delta := p.ground.Location.Min.Sub(p.groundLoc.Min)
+		p.carry(floatgeom.Point2{delta.X(), delta.Y()})
+	}
+
+	dir := 0.0
+	if in.Left {
+		dir--
+	}
+	if in.Right {
+		dir++
+	}
+	accel := p.Acceleration
+	if !p.grounded {
+		accel = p.AirAcceleration
+	}
+	p.Velocity[0] = approach(p.Velocity.X(), dir*p.MoveSpeed, accel*dt)
+
+	p.Velocity[1] = math.Min(p.Velocity.Y()+p.Gravity*dt, p.MaxFallSpeed)
+	if p.WallSlideSpeed > 0 && !p.grounded && p.onWall != 0 && float64(p.onWall) == dir {
+		p.Velocity[1] = math.Min(p.Velocity.Y(), p.WallSlideSpeed)
+	}
+
+	jumpedNow := false
+	if p.sinceJumpPressed <= p.JumpBuffer {
+		if p.grounded || (!p.jumped && p.sinceGrounded <= p.CoyoteTime) {
+			p.Velocity[1] = -math.Sqrt(2 * p.Gravity * p.JumpHeight)
+			jumpedNow = true
+			event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerJumped, struct{}{})
+		} else if p.onWall != 0 && p.WallJump != (floatgeom.Point2{}) {
+			p.Velocity = floatgeom.Point2{-float64(p.onWall) * p.WallJump.X(), -p.WallJump.Y()}
+			jumpedNow = true
+			event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerWallJumped, p.onWall)
+		}
+		if jumpedNow {
+			p.jumping = true
+			p.jumped = true
+			p.sinceJumpPressed = math.MaxInt64
+		}
+	}
+	if in.Down && p.grounded && p.isOneWay(p.ground) {
+		p.dropping = p.ground
+	}
+	if p.jumping && (p.Velocity.Y() >= 0 || !in.Jump) {
+		if p.Velocity.Y() < 0 {
+			p.Velocity[1] *= p.JumpCut
+		}
+		p.jumping = false
+	}
+
+	wasGrounded := p.grounded
+	p.moveX(p.Velocity.X()*dt, wasGrounded)
+	p.moveY(p.Velocity.Y() * dt)
+	if wasGrounded && !jumpedNow && p.Velocity.Y() >= 0 {
+		p.snapDown()
+	}
+	if p.dropping != nil && p.Rect.Min.Y() > p.dropping.Location.Min.Y() {
+		p.dropping = nil
+	}
+	p.updateState()
+}
+
+func approach(v, target, step float64) float64 {
+	if step <= 0 {
+		return target
+	}
+	if v < target {
+		return math.Min(v+step, target)
+	}
+	return math.Max(v-step, target)
+}
+
+// carry moves the entity along with the space it is standing on, stopping at any
+// other space which blocks it. Carrying does not change the entity's velocity.
+func (p *Platformer) carry(delta floatgeom.Point2) {
+	vel := p.Velocity
+	p.carrier = p.ground
+	p.moveY(delta.Y())
+	p.moveX(delta.X(), false)
+	p.carrier = nil
+	p.Velocity = vel
+}
+
+func (p *Platformer) isOneWay(s *collision.Space) bool {
+	return s != nil && s.HasLabel(p.OneWayLabels...)
+}
+
+func (p *Platformer) isSolid(s *collision.Space) bool {
+	return !p.isOneWay(s) && s.MatchesLabels(p.SolidLabels...)
+}
+
+// blockers returns the spaces overlapping r which block the entity. One way platforms
+// are included if their top is at or below oneWayAbove.
+func (p *Platformer) blockers(r floatgeom.Rect2, oneWayAbove float64) []*collision.Space {
+	var out []*collision.Space
+	for _, s := range p.hitsAt(r) {
+		if s == p.carrier {
+			continue
+		}
+		if p.isSolid(s) {
+			out = append(out, s)
+		} else if p.isOneWay(s) && s != p.dropping && s.Location.Min.Y() >= oneWayAbove-collisionSkin {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (p *Platformer) moveX(dx float64, grounded bool) {
+	if dx == 0 {
+		return
+	}
+	next := p.Rect.Shift(floatgeom.Point2{dx, 0})
+	shrunk := floatgeom.NewRect2(next.Min.X(), next.Min.Y()+collisionSkin, next.Max.X(), next.Max.Y()-collisionSkin)
+	hits := p.blockers(shrunk, math.Inf(1))
+	if len(hits) == 0 {
+		p.ShiftX(dx)
+		return
+	}
+	if grounded && p.MaxStep > 0 {
+		top := math.Inf(1)
+		for _, s := range hits {
+			top = math.Min(top, s.Location.Min.Y())
+		}
+		rise := next.Max.Y() - top
+		if rise <= p.MaxStep {
+			raised := shrunk.Shift(floatgeom.Point2{0, -rise})
+			if len(p.blockers(raised, math.Inf(1))) == 0 {
+				p.Shift(floatgeom.Point2{dx, -rise})
+				return
+			}
+		}
+	}
+	x := next.Min.X()
+	for _, s := range hits {
+		if dx > 0 {
+			x = math.Min(x, s.Location.Min.X()-p.W())
+		} else {
+			x = math.Max(x, s.Location.Max.X())
+		}
+	}
+	p.SetX(x)
+	p.Velocity[0] = 0
+}
+
+func (p *Platformer) moveY(dy float64) {
+	if dy == 0 {
+		return
+	}
+	next := p.Rect.Shift(floatgeom.Point2{0, dy})
+	shrunk := floatgeom.NewRect2(next.Min.X()+collisionSkin, next.Min.Y(), next.Max.X()-collisionSkin, next.Max.Y())
+	oneWayAbove := math.Inf(1)
+	if dy > 0 {
+		oneWayAbove = p.Bottom()
+	}
+	hits := p.blockers(shrunk, oneWayAbove)
+	if len(hits) == 0 {
+		p.ShiftY(dy)
+		return
+	}
+	y := next.Min.Y()
+	for _, s := range hits {
+		if dy > 0 {
+			y = math.Min(y, s.Location.Min.Y()-p.H())
+		} else {
+			y = math.Max(y, s.Location.Max.Y())
+		}
+	}
+	p.SetY(y)
+	p.Velocity[1] = 0
+}
+
+func (p *Platformer) snapDown() {
+	probe := floatgeom.NewRect2(p.Left()+collisionSkin, p.Bottom(), p.Right()-collisionSkin, p.Bottom()+p.MaxStep)
+	top := math.Inf(1)
+	for _, s := range p.blockers(probe, p.Bottom()) {
+		top = math.Min(top, s.Location.Min.Y())
+	}
+	if !math.IsInf(top, 1) && top > p.Bottom() {
+		p.SetY(top - p.H())
+	}
+}
+
+// touching returns the first blocking space within the skin width of r.
+func (p *Platformer) touching(r floatgeom.Rect2, oneWayAbove float64) *collision.Space {
+	hits := p.blockers(r, oneWayAbove)
+	if len(hits) == 0 {
+		return nil
+	}
+	return hits[0]
+}
+
+func (p *Platformer) updateState() {
+	const skin = 2 * collisionSkin
+	ground := p.touching(floatgeom.NewRect2(p.Left()+skin, p.Bottom()-skin, p.Right()-skin, p.Bottom()+skin), p.Bottom())
+	if p.Velocity.Y() < 0 {
+		// Rising through the top of a one way platform is not landing on it
+		ground = nil
+	}
+	ceiling := p.touching(floatgeom.NewRect2(p.Left()+skin, p.Top()-skin, p.Right()-skin, p.Top()+skin), math.Inf(1))
+	left := p.touching(floatgeom.NewRect2(p.Left()-skin, p.Top()+skin, p.Left()+skin, p.Bottom()-skin), math.Inf(1))
+	right := p.touching(floatgeom.NewRect2(p.Right()-skin, p.Top()+skin, p.Right()+skin, p.Bottom()-skin), math.Inf(1))
+
+	wasGrounded := p.grounded
+	p.grounded = ground != nil
+	p.ground = ground
+	if ground != nil {
+		p.groundLoc = ground.Location
+		p.sinceGrounded = 0
+		p.jumped = false
+		if !wasGrounded {
+			event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerLanded, ground)
+		}
+	} else if wasGrounded {
+		event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerLeftGround, struct{}{})
+	}
+
+	wasOnWall := p.onWall
+	var wall *collision.Space
+	switch {
+	case left != nil:
+		p.onWall, wall = -1, left
+	case right != nil:
+		p.onWall, wall = 1, right
+	default:
+		p.onWall = 0
+	}
+	if wall != nil && p.onWall != wasOnWall {
+		event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerHitWall, wall)
+	}
+
+	wasOnCeiling := p.onCeiling
+	p.onCeiling = ceiling != nil
+	if ceiling != nil && !wasOnCeiling {
+		event.TriggerForCallerOn(p.ctx, p.CID(), PlatformerHitCeiling, ceiling)
+	}
+}
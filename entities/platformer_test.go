@@ -0,0 +1,261 @@
+package entities
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+const (
+	frame       = time.Second / 60
+	oneWayLabel = collision.Label(2)
+)
+
+type platformerTest struct {
+	t   *testing.T
+	ctx *scene.Context
+	p   *Platformer
+	in  PlatformerInput
+}
+
+// newPlatformerTest creates a 10x10 platformer at x, y in a tree containing the
+// given solid spaces.
+func newPlatformerTest(t *testing.T, x, y float64, solids ...floatgeom.Rect2) *platformerTest {
+	ctx := testContext()
+	for _, r := range solids {
+		ctx.CollisionTree.Add(collision.NewSpace(r.Min.X(), r.Min.Y(), r.W(), r.H(), 0))
+	}
+	pt := &platformerTest{t: t, ctx: ctx}
+	pt.p = NewPlatformer(testEntity(ctx, x, y, 10, 10))
+	pt.p.OneWayLabels = []collision.Label{oneWayLabel}
+	pt.p.Input = func() PlatformerInput {
+		return pt.in
+	}
+	return pt
+}
+
+func (pt *platformerTest) add(x, y, w, h float64, label collision.Label) *collision.Space {
+	s := collision.NewLabeledSpace(x, y, w, h, label)
+	pt.ctx.CollisionTree.Add(s)
+	return s
+}
+
+func (pt *platformerTest) step(frames int) {
+	for i := 0; i < frames; i++ {
+		pt.p.Update(frame)
+	}
+}
+
+// settle lets the platformer fall until it lands.
+func (pt *platformerTest) settle() {
+	pt.t.Helper()
+	for i := 0; i < 300 && !pt.p.Grounded(); i++ {
+		pt.step(1)
+	}
+	if !pt.p.Grounded() {
+		pt.t.Fatalf("platformer never landed, at %v", pt.p.Rect)
+	}
+}
+
+func floor(y float64) floatgeom.Rect2 {
+	return floatgeom.NewRect2WH(-1000, y, 2000, 20)
+}
+
+func TestPlatformerLands(t *testing.T) {
+	pt := newPlatformerTest(t, 0, 0, floor(50))
+	pt.settle()
+	if math.Abs(pt.p.Bottom()-50) > collisionSkin {
+		t.Fatalf("platformer landed at %v, expected 50", pt.p.Bottom())
+	}
+	if pt.p.Velocity.Y() != 0 {
+		t.Fatalf("landed platformer kept falling at %v", pt.p.Velocity.Y())
+	}
+	pt.step(10)
+	if !pt.p.Grounded() || math.Abs(pt.p.Bottom()-50) > collisionSkin {
+		t.Fatalf("platformer did not stay on the ground")
+	}
+}
+
+func TestPlatformerCoyoteTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		lateBy    int
+		wantJumps bool
+	}{
+		{"on the ledge", 0, true},
+		{"just after leaving", 2, true},
+		{"well after leaving", 12, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// A ledge ending at x=20, walked off to the right.
+			pt := newPlatformerTest(t, 5, 40, floatgeom.NewRect2WH(-100, 50, 120, 20))
+			pt.p.Acceleration = 0
+			pt.p.AirAcceleration = 0
+			pt.settle()
+			pt.in.Right = true
+			for pt.p.Grounded() {
+				pt.step(1)
+			}
+			if tc.lateBy == 0 {
+				// Rewind to the ledge by jumping on the frame the ground is lost.
+				pt.in.Jump = true
+				pt.step(1)
+			} else {
+				pt.step(tc.lateBy)
+				pt.in.Jump = true
+				pt.step(1)
+			}
+			if jumped := pt.p.Velocity.Y() < 0; jumped != tc.wantJumps {
+				t.Fatalf("expected jump %v, velocity was %v", tc.wantJumps, pt.p.Velocity)
+			}
+		})
+	}
+}
+
+func TestPlatformerJumpBuffer(t *testing.T) {
+	tests := []struct {
+		name      string
+		early     time.Duration
+		wantJumps bool
+	}{
+		{"just before landing", 50 * time.Millisecond, true},
+		{"long before landing", 300 * time.Millisecond, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pt := newPlatformerTest(t, 0, 0, floor(200))
+			// Fall until the given time before landing, found by a trial run.
+			trial := newPlatformerTest(t, 0, 0, floor(200))
+			frames := 0
+			for !trial.p.Grounded() {
+				trial.step(1)
+				frames++
+			}
+			early := int(tc.early / frame)
+			pt.step(frames - early)
+			pt.in.Jump = true
+			pt.step(1)
+			pt.in.Jump = false
+			jumped := false
+			for i := 0; i < early+2; i++ {
+				pt.step(1)
+				if pt.p.Velocity.Y() < 0 {
+					jumped = true
+				}
+			}
+			if jumped != tc.wantJumps {
+				t.Fatalf("expected buffered jump %v", tc.wantJumps)
+			}
+		})
+	}
+}
+
+func TestPlatformerOneWay(t *testing.T) {
+	pt := newPlatformerTest(t, 0, 90, floor(100))
+	platform := pt.add(-50, 60, 100, 5, oneWayLabel)
+	pt.settle()
+
+	// Jumping up through the platform is not blocked, and lands on top of it.
+	pt.in.Jump = true
+	for i := 0; i < 120 && !(pt.p.Grounded() && pt.p.Velocity.Y() == 0 && pt.p.Bottom() < 90); i++ {
+		pt.step(1)
+	}
+	if pt.p.Ground() != platform {
+		t.Fatalf("expected to land on the one way platform, was on %v at %v", pt.p.Ground(), pt.p.Rect)
+	}
+	pt.in.Jump = false
+
+	// Holding down drops through it to the floor.
+	pt.in.Down = true
+	pt.step(2)
+	pt.in.Down = false
+	pt.settle()
+	if pt.p.Ground() == platform || math.Abs(pt.p.Bottom()-100) > collisionSkin {
+		t.Fatalf("expected to drop to the floor, was at %v", pt.p.Rect)
+	}
+}
+
+func TestPlatformerCarry(t *testing.T) {
+	pt := newPlatformerTest(t, 0, 0)
+	platform := pt.add(-20, 50, 60, 10, 0)
+	wall := pt.add(70, -100, 10, 200, 0)
+	pt.settle()
+	for i := 0; i < 45; i++ {
+		pt.ctx.CollisionTree.ShiftSpace(2, -1, platform)
+		pt.step(1)
+		if pt.p.Right() > wall.X()+collisionSkin {
+			t.Fatalf("carry pushed the platformer into the wall: %v", pt.p.Rect)
+		}
+	}
+	if pt.p.Right() < wall.X()-collisionSkin {
+		t.Fatalf("expected to be carried up to the wall, was at %v", pt.p.Rect)
+	}
+	if math.Abs(pt.p.Bottom()-platform.Y()) > 2 {
+		t.Fatalf("expected to ride the rising platform, bottom %v vs %v", pt.p.Bottom(), platform.Y())
+	}
+}
+
+func TestPlatformerSteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		height   float64
+		climbs   bool
+		wantTopY float64
+	}{
+		{"low step", 3, true, 47},
+		{"tall ledge", 10, false, 50},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pt := newPlatformerTest(t, 0, 40, floor(50))
+			pt.add(30, 50-tc.height, 100, tc.height, 0)
+			pt.settle()
+			pt.in.Right = true
+			pt.step(30)
+			if climbed := pt.p.Left() > 30; climbed != tc.climbs {
+				t.Fatalf("expected climb %v, at %v", tc.climbs, pt.p.Rect)
+			}
+			if !pt.p.Grounded() || math.Abs(pt.p.Bottom()-tc.wantTopY) > collisionSkin {
+				t.Fatalf("expected to stand at %v, at %v", tc.wantTopY, pt.p.Rect)
+			}
+		})
+	}
+}
+
+func TestPlatformerSnapDown(t *testing.T) {
+	// Walking off a small step down keeps the platformer on the ground.
+	pt := newPlatformerTest(t, 0, 37, floatgeom.NewRect2WH(-100, 47, 130, 20), floor(50))
+	pt.settle()
+	pt.in.Right = true
+	for i := 0; i < 30; i++ {
+		pt.step(1)
+		if !pt.p.Grounded() {
+			t.Fatalf("platformer left the ground stepping down at %v", pt.p.Rect)
+		}
+	}
+	if math.Abs(pt.p.Bottom()-50) > collisionSkin {
+		t.Fatalf("expected to stand on the lower floor, at %v", pt.p.Rect)
+	}
+}
+
+func TestPlatformerWallJump(t *testing.T) {
+	pt := newPlatformerTest(t, 10, 0, floor(500), floatgeom.NewRect2WH(20, -500, 10, 1000))
+	pt.in.Right = true
+	pt.step(20)
+	if pt.p.OnWall() != 1 {
+		t.Fatalf("expected to be against the right wall, at %v", pt.p.Rect)
+	}
+	if pt.p.Velocity.Y() > pt.p.WallSlideSpeed {
+		t.Fatalf("wall slide did not limit falling speed: %v", pt.p.Velocity.Y())
+	}
+	pt.in.Jump = true
+	pt.step(1)
+	if pt.p.Velocity.X() >= 0 || pt.p.Velocity.Y() >= 0 {
+		t.Fatalf("expected wall jump up and away from the wall, velocity %v", pt.p.Velocity)
+	}
+}
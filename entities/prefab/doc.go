@@ -0,0 +1,3 @@
+// Package prefab builds entities from data driven templates, such as JSON or
+// YAML files authored without code.
+package prefab
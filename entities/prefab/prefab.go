@@ -0,0 +1,113 @@
+package prefab
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// A Prefab describes an entity. Unset fields are left to the entities package's
+// defaults, or to the prefab this prefab extends.
+type Prefab struct {
+	// Extends names a registered prefab whose fields this prefab overrides.
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
+
+	Position   *floatgeom.Point2 `json:"position,omitempty" yaml:"position,omitempty"`
+	Dimensions *floatgeom.Point2 `json:"dimensions,omitempty" yaml:"dimensions,omitempty"`
+	Speed      *floatgeom.Point2 `json:"speed,omitempty" yaml:"speed,omitempty"`
+
+	// Color is used to draw a box if Renderable is not set.
+	Color      *Color      `json:"color,omitempty" yaml:"color,omitempty"`
+	Renderable *Renderable `json:"renderable,omitempty" yaml:"renderable,omitempty"`
+
+	Label      *collision.Label `json:"label,omitempty" yaml:"label,omitempty"`
+	DrawLayers []int            `json:"drawLayers,omitempty" yaml:"drawLayers,omitempty"`
+
+	// Children are positioned relative to their parent.
+	Children []Prefab `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// A Renderable describes how a prefab is drawn. Exactly one field should be set.
+type Renderable struct {
+	// Sprite is the path of an image file.
+	Sprite string `json:"sprite,omitempty" yaml:"sprite,omitempty"`
+	Sheet  *Sheet `json:"sheet,omitempty" yaml:"sheet,omitempty"`
+	Text   string `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// A Sheet describes an animation from a sprite sheet.
+type Sheet struct {
+	// File is the path of the sheet's image file.
+	File string `json:"file" yaml:"file"`
+	// CellSize is the width and height of each frame in the sheet.
+	CellSize [2]int  `json:"cellSize" yaml:"cellSize"`
+	FPS      float64 `json:"fps" yaml:"fps"`
+	// Frames are the x,y sheet coordinates of each frame of the animation.
+	Frames [][2]int `json:"frames" yaml:"frames"`
+}
+
+// Color is a color written as a hex string, "#rrggbb" or "#rrggbbaa".
+type Color color.RGBA
+
+// UnmarshalText parses a hex color string.
+func (c *Color) UnmarshalText(text []byte) error {
+	s := strings.TrimPrefix(string(text), "#")
+	var r, g, b uint8
+	a := uint8(255)
+	var err error
+	switch len(s) {
+	case 6:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
+	case 8:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x%02x", &r, &g, &b, &a)
+	default:
+		return oakerr.InvalidInput{InputName: "color"}
+	}
+	if err != nil {
+		return oakerr.InvalidInput{InputName: "color"}
+	}
+	*c = Color{r, g, b, a}
+	return nil
+}
+
+// MarshalText writes a color as a hex string.
+func (c Color) MarshalText() ([]byte, error) {
+	if c.A == 255 {
+		return []byte(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)), nil
+	}
+	return []byte(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)), nil
+}
+
+// Override returns a copy of p with every field set in o replacing p's value. The
+// Extends field of o is ignored.
+func (p Prefab) Override(o Prefab) Prefab {
+	if o.Position != nil {
+		p.Position = o.Position
+	}
+	if o.Dimensions != nil {
+		p.Dimensions = o.Dimensions
+	}
+	if o.Speed != nil {
+		p.Speed = o.Speed
+	}
+	if o.Color != nil {
+		p.Color = o.Color
+	}
+	if o.Renderable != nil {
+		p.Renderable = o.Renderable
+	}
+	if o.Label != nil {
+		p.Label = o.Label
+	}
+	if o.DrawLayers != nil {
+		p.DrawLayers = o.DrawLayers
+	}
+	if o.Children != nil {
+		p.Children = o.Children
+	}
+	return p
+}
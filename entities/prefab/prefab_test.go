@@ -0,0 +1,139 @@
+package prefab
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+func testContext() *scene.Context {
+	callers := event.NewCallerMap()
+	ks := key.NewState()
+	return &scene.Context{
+		CallerMap:     callers,
+		Handler:       event.NewBus(callers),
+		DrawStack:     render.NewDrawStack(render.NewDynamicHeap()),
+		State:         &ks,
+		CollisionTree: collision.NewTree(),
+	}
+}
+
+func TestColor(t *testing.T) {
+	var c Color
+	if err := c.UnmarshalText([]byte("#10203040")); err != nil {
+		t.Fatal(err)
+	}
+	if c != (Color{0x10, 0x20, 0x30, 0x40}) {
+		t.Fatalf("unexpected color %v", c)
+	}
+	if err := c.UnmarshalText([]byte("102030")); err != nil {
+		t.Fatal(err)
+	}
+	if txt, _ := c.MarshalText(); string(txt) != "#102030" {
+		t.Fatalf("unexpected color text %v", string(txt))
+	}
+	for _, bad := range []string{"#12", "#zzzzzz"} {
+		if err := c.UnmarshalText([]byte(bad)); err == nil {
+			t.Fatalf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestRegistryLoadFile(t *testing.T) {
+	r := NewRegistry()
+	if err := r.LoadFile("testdata/enemies.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadFile("testdata/enemies.yaml"); err == nil {
+		t.Fatal("expected unsupported format error without a yaml unmarshaler")
+	} else if _, ok := err.(oakerr.UnsupportedFormat); !ok {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+	goblin, err := r.Resolve("goblin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goblin.Label == nil || *goblin.Label != 3 {
+		t.Fatalf("goblin did not inherit label")
+	}
+	if goblin.Renderable == nil || goblin.Renderable.Sprite != "testdata/jeremy.png" {
+		t.Fatalf("goblin renderable not set")
+	}
+	if len(goblin.Children) != 1 {
+		t.Fatalf("expected one child")
+	}
+
+	ctx := testContext()
+	pos := floatgeom.Point2{100, 50}
+	e, err := r.NewFrom(ctx, Prefab{Extends: "goblin", Position: &pos}, entities.WithSpeed(floatgeom.Point2{5, 5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Rect != floatgeom.NewRect2WH(100, 50, 16, 16) {
+		t.Fatalf("unexpected rect %v", e.Rect)
+	}
+	if e.Speed != (floatgeom.Point2{5, 5}) {
+		t.Fatalf("option override not applied, speed was %v", e.Speed)
+	}
+	if _, ok := e.Renderable.(*render.Sprite); !ok {
+		t.Fatalf("expected sprite renderable, got %T", e.Renderable)
+	}
+	if e.Space.Label != 3 {
+		t.Fatalf("unexpected label %v", e.Space.Label)
+	}
+	if len(e.Children) != 1 || e.Children[0].Rect != floatgeom.NewRect2WH(104, 42, 8, 4) {
+		t.Fatalf("child not created relative to parent")
+	}
+}
+
+func TestRegistryResolveErrors(t *testing.T) {
+	r := NewRegistry()
+	r.Register("a", Prefab{Extends: "b"})
+	r.Register("b", Prefab{Extends: "a"})
+	r.Register("c", Prefab{Extends: "missing"})
+	if _, err := r.Resolve("a"); err == nil {
+		t.Fatal("expected error for cyclical prefabs")
+	}
+	if _, err := r.Resolve("c"); err == nil {
+		t.Fatal("expected error for missing base prefab")
+	}
+	if _, err := r.New(testContext(), "missing"); err == nil {
+		t.Fatal("expected error for missing prefab")
+	}
+	r.Register("d", Prefab{Renderable: &Renderable{}})
+	if _, err := r.New(testContext(), "d"); err == nil {
+		t.Fatal("expected error for empty renderable")
+	}
+}
+
+func TestRegistryCustomUnmarshaler(t *testing.T) {
+	r := NewRegistry()
+	red := Color{255, 0, 0, 255}
+	dims := floatgeom.Point2{4, 4}
+	err := r.Load([]byte("ignored"), func(data []byte, v interface{}) error {
+		*(v.(*map[string]Prefab)) = map[string]Prefab{
+			"box": {Color: &red, Dimensions: &dims},
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := r.New(testContext(), "box")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cb, ok := e.Renderable.(*render.Sprite); !ok {
+		t.Fatalf("expected color box sprite, got %T", e.Renderable)
+	} else if cb.GetRGBA().At(0, 0) != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("color box was not red")
+	}
+}
@@ -0,0 +1,207 @@
+package prefab
+
+import (
+	"encoding/json"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/alg/intgeom"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/fileutil"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// An Unmarshaler decodes data into a value, like json.Unmarshal.
+type Unmarshaler func(data []byte, v interface{}) error
+
+// A Registry holds named prefabs and instantiates them as entities.
+type Registry struct {
+	// Unmarshalers maps file extensions to the functions LoadFile decodes them
+	// with. Only ".json" is registered by default; YAML files can be supported by
+	// registering a YAML library's Unmarshal function under ".yaml" and ".yml".
+	Unmarshalers map[string]Unmarshaler
+
+	mu      sync.RWMutex
+	prefabs map[string]Prefab
+}
+
+// DefaultRegistry is a global registry.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		Unmarshalers: map[string]Unmarshaler{
+			".json": json.Unmarshal,
+		},
+		prefabs: make(map[string]Prefab),
+	}
+}
+
+// Register adds a prefab to the registry under name, replacing any existing prefab
+// with that name.
+func (r *Registry) Register(name string, p Prefab) {
+	r.mu.Lock()
+	r.prefabs[name] = p
+	r.mu.Unlock()
+}
+
+// Get returns the prefab registered under name as it was registered, without
+// applying the prefab it extends.
+func (r *Registry) Get(name string) (Prefab, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.prefabs[name]
+	return p, ok
+}
+
+// Load decodes data as an object mapping names to prefabs, and registers each of
+// them.
+func (r *Registry) Load(data []byte, unmarshal Unmarshaler) error {
+	prefabs := map[string]Prefab{}
+	if err := unmarshal(data, &prefabs); err != nil {
+		return err
+	}
+	for name, p := range prefabs {
+		r.Register(name, p)
+	}
+	return nil
+}
+
+// LoadFile reads a file of prefabs and Loads it with the unmarshaler registered for
+// the file's extension.
+func (r *Registry) LoadFile(file string) error {
+	ext := strings.ToLower(filepath.Ext(file))
+	unmarshal, ok := r.Unmarshalers[ext]
+	if !ok {
+		return oakerr.UnsupportedFormat{Format: ext}
+	}
+	data, err := fileutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return r.Load(data, unmarshal)
+}
+
+// Resolve returns the prefab registered under name with the prefabs it extends
+// applied.
+func (r *Registry) Resolve(name string) (Prefab, error) {
+	return r.resolve(Prefab{Extends: name})
+}
+
+func (r *Registry) resolve(p Prefab) (Prefab, error) {
+	chain := []Prefab{p}
+	seen := map[string]bool{}
+	for p.Extends != "" {
+		if seen[p.Extends] {
+			return Prefab{}, oakerr.InvalidInput{InputName: "extends " + p.Extends}
+		}
+		seen[p.Extends] = true
+		base, ok := r.Get(p.Extends)
+		if !ok {
+			return Prefab{}, oakerr.NotFound{InputName: p.Extends}
+		}
+		chain = append(chain, base)
+		p = base
+	}
+	out := Prefab{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		out = out.Override(chain[i])
+	}
+	return out, nil
+}
+
+// Options converts a prefab, after applying the prefabs it extends, into options
+// for entities.New.
+func (r *Registry) Options(p Prefab) ([]entities.Option, error) {
+	p, err := r.resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	opts := []entities.Option{}
+	if p.Position != nil {
+		opts = append(opts, entities.WithPosition(*p.Position))
+	}
+	if p.Speed != nil {
+		opts = append(opts, entities.WithSpeed(*p.Speed))
+	}
+	if p.Color != nil {
+		opts = append(opts, entities.WithColor(color.RGBA(*p.Color)))
+	}
+	if p.Renderable != nil {
+		rend, err := p.Renderable.build()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, entities.WithRenderable(rend))
+		if p.Dimensions == nil {
+			w, h := rend.GetDims()
+			opts = append(opts, entities.WithDimensions(floatgeom.Point2{float64(w), float64(h)}))
+		}
+	}
+	if p.Dimensions != nil {
+		opts = append(opts, entities.WithDimensions(*p.Dimensions))
+	}
+	if p.Label != nil {
+		opts = append(opts, entities.WithLabel(*p.Label))
+	}
+	if p.DrawLayers != nil {
+		opts = append(opts, entities.WithDrawLayers(p.DrawLayers))
+	}
+	for _, child := range p.Children {
+		childOpts, err := r.Options(child)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, entities.WithChild(childOpts...))
+	}
+	return opts, nil
+}
+
+// New instantiates the prefab registered under name into a scene. Overrides are
+// applied after the prefab's own options.
+func (r *Registry) New(ctx *scene.Context, name string, overrides ...entities.Option) (*entities.Entity, error) {
+	return r.NewFrom(ctx, Prefab{Extends: name}, overrides...)
+}
+
+// NewFrom instantiates a prefab into a scene. The prefab will often extend a
+// registered prefab, overriding some of its fields.
+func (r *Registry) NewFrom(ctx *scene.Context, p Prefab, overrides ...entities.Option) (*entities.Entity, error) {
+	opts, err := r.Options(p)
+	if err != nil {
+		return nil, err
+	}
+	return entities.New(ctx, append(opts, overrides...)...), nil
+}
+
+func (rd *Renderable) build() (render.Renderable, error) {
+	switch {
+	case rd.Sprite != "":
+		sp, err := render.GetSprite(rd.Sprite)
+		if err != nil {
+			sp, err = render.LoadSprite(rd.Sprite)
+		}
+		return sp, err
+	case rd.Sheet != nil:
+		sh, err := render.GetSheet(rd.Sheet.File)
+		if err != nil {
+			sh, err = render.LoadSheet(rd.Sheet.File, intgeom.Point2{rd.Sheet.CellSize[0], rd.Sheet.CellSize[1]})
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames := make([]int, 0, len(rd.Sheet.Frames)*2)
+		for _, f := range rd.Sheet.Frames {
+			frames = append(frames, f[0], f[1])
+		}
+		return render.NewSheetSequence(sh, rd.Sheet.FPS, frames...)
+	case rd.Text != "":
+		return render.NewText(rd.Text, 0, 0), nil
+	}
+	return nil, oakerr.InvalidInput{InputName: "renderable"}
+}
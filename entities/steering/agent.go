@@ -0,0 +1,58 @@
+package steering
+
+import (
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/event"
+)
+
+// An Agent moves an entity by steering its body. The body's position is kept at
+// the center of the entity.
+type Agent struct {
+	*entities.Entity
+	Body
+
+	// Steer is called each update to get the agent's acceleration.
+	Steer func(*Agent) floatgeom.Point2
+}
+
+// NewAgent creates an agent for an entity with the given speed and force limits.
+func NewAgent(e *entities.Entity, maxSpeed, maxForce float64) *Agent {
+	return &Agent{
+		Entity: e,
+		Body: Body{
+			Position: e.Rect.Center(),
+			MaxSpeed: maxSpeed,
+			MaxForce: maxForce,
+		},
+	}
+}
+
+// JoinFlock adds this agent's body to a flock, found by the entity's space.
+func (a *Agent) JoinFlock(f *Flock) {
+	f.Add(a.Space, &a.Body)
+}
+
+// Bind causes this agent to Update every frame on the given handler, until the
+// returned binding is unbound or the entity's bindings are unbound.
+func (a *Agent) Bind(h event.Handler) event.Binding {
+	return h.UnsafeBind(event.Enter.UnsafeEventID, a.CID(), func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		a.Update(payload.(event.EnterPayload).SinceLastFrame)
+		return 0
+	})
+}
+
+// Update steers the agent for the elapsed time, setting the entity's Delta to how
+// far it should move and shifting it by that delta.
+func (a *Agent) Update(elapsed time.Duration) {
+	a.Position = a.Rect.Center()
+	accel := floatgeom.Point2{}
+	if a.Steer != nil {
+		accel = a.Steer(a)
+	}
+	a.Delta = Integrate(&a.Body, accel, elapsed.Seconds())
+	a.ShiftDelta()
+	a.Position = a.Rect.Center()
+}
@@ -0,0 +1,48 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+// AvoidObstacles steers a body sideways, away from the nearest space ahead of it
+// in a collision tree. Spaces are probed for with a box of the given dimensions,
+// centered on the body and swept lookahead units along its velocity. The closer
+// the space, the harder the body steers. If labels are given, only spaces with one
+// of those labels are avoided; ignore, usually the body's own space, never is.
+func AvoidObstacles(b Body, tree *collision.Tree, ignore *collision.Space, dims floatgeom.Point2, lookahead float64, labels ...collision.Label) floatgeom.Point2 {
+	heading := b.Velocity.Normalize()
+	if heading == (floatgeom.Point2{}) || lookahead <= 0 {
+		return floatgeom.Point2{}
+	}
+	step := math.Max(math.Min(dims.X(), dims.Y()), 1)
+	for d := 0.0; d <= lookahead; d += step {
+		at := b.Position.Add(heading.MulConst(d))
+		probe := collision.NewUnassignedSpace(at.X()-dims.X()/2, at.Y()-dims.Y()/2, dims.X(), dims.Y())
+		var nearest *collision.Space
+		nearestDist := math.Inf(1)
+		for _, s := range tree.Hits(probe) {
+			if s == ignore || !s.MatchesLabels(labels...) {
+				continue
+			}
+			if dist := center(s).Distance(b.Position); dist < nearestDist {
+				nearest, nearestDist = s, dist
+			}
+		}
+		if nearest == nil {
+			continue
+		}
+		side := floatgeom.Point2{-heading.Y(), heading.X()}
+		if center(nearest).Sub(b.Position).Dot(side) > 0 {
+			side = side.MulConst(-1)
+		}
+		return side.MulConst(b.MaxForce * (1 - d/(lookahead+step)))
+	}
+	return floatgeom.Point2{}
+}
+
+func center(s *collision.Space) floatgeom.Point2 {
+	return floatgeom.Point2{s.X() + s.W()/2, s.Y() + s.H()/2}
+}
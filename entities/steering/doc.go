@@ -0,0 +1,7 @@
+// Package steering provides steering behaviors for moving bodies toward, away
+// from, and around targets, obstacles, and each other.
+//
+// Behaviors return accelerations, limited to their body's MaxForce, which may be
+// weighted and summed before being integrated into the body's velocity. Agents
+// apply this to entities, moving them by their Delta each frame.
+package steering
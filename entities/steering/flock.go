@@ -0,0 +1,151 @@
+package steering
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+// A Flock tracks bodies by their spaces in a collision tree, so that the bodies
+// near one another can be found for flocking.
+type Flock struct {
+	Tree *collision.Tree
+	// Neighbors is the most bodies considered near any one body, and Radius is
+	// how near they must be.
+	Neighbors int
+	Radius    float64
+
+	mu      sync.RWMutex
+	members map[*collision.Space]*Body
+}
+
+// NewFlock creates a flock finding up to neighbors bodies within radius of one
+// another in a tree.
+func NewFlock(tree *collision.Tree, neighbors int, radius float64) *Flock {
+	return &Flock{
+		Tree:      tree,
+		Neighbors: neighbors,
+		Radius:    radius,
+		members:   make(map[*collision.Space]*Body),
+	}
+}
+
+// Add adds a body to the flock, found by its space.
+func (f *Flock) Add(s *collision.Space, b *Body) {
+	f.mu.Lock()
+	f.members[s] = b
+	f.mu.Unlock()
+}
+
+// Remove removes the bodies of spaces from the flock.
+func (f *Flock) Remove(ss ...*collision.Space) {
+	f.mu.Lock()
+	for _, s := range ss {
+		delete(f.members, s)
+	}
+	f.mu.Unlock()
+}
+
+// NeighborsOf returns the bodies of the flock near a space, excluding its own.
+// Spaces in the tree which are not in the flock are not counted toward the
+// Neighbors limit.
+func (f *Flock) NeighborsOf(s *collision.Space) []*Body {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	at := center(s)
+	p := floatgeom.Point3{at.X(), at.Y(), s.Location.Min.Z()}
+	// Search for more spaces than needed, as some will not be flock members, and
+	// widen the search until enough members are found or the spaces found reach
+	// beyond Radius.
+	for k := f.Neighbors*2 + 1; ; k *= 2 {
+		near := f.Tree.NearestNeighbors(k, p)
+		out := make([]*Body, 0, f.Neighbors)
+		for _, n := range near {
+			if len(out) == f.Neighbors {
+				break
+			}
+			b, ok := f.members[n]
+			if !ok || n == s {
+				continue
+			}
+			if b.Position.Distance(at) > f.Radius {
+				continue
+			}
+			out = append(out, b)
+		}
+		if len(out) == f.Neighbors || len(near) < k {
+			return out
+		}
+		last := near[len(near)-1].Location.ProjectZ()
+		if last.Clamp(at).Distance(at) > f.Radius {
+			return out
+		}
+	}
+}
+
+// FlockWeights weight the behaviors combined by Flock.Steer.
+type FlockWeights struct {
+	Separation float64
+	Alignment  float64
+	Cohesion   float64
+}
+
+// Steer returns the weighted sum of the separation, alignment, and cohesion of
+// the body of a space in the flock, limited to the body's MaxForce.
+func (f *Flock) Steer(s *collision.Space, w FlockWeights) floatgeom.Point2 {
+	f.mu.RLock()
+	b, ok := f.members[s]
+	f.mu.RUnlock()
+	if !ok {
+		return floatgeom.Point2{}
+	}
+	ns := f.NeighborsOf(s)
+	return Truncate(floatgeom.Point2{}.Add(
+		Separation(*b, ns).MulConst(w.Separation),
+		Alignment(*b, ns).MulConst(w.Alignment),
+		Cohesion(*b, ns).MulConst(w.Cohesion),
+	), b.MaxForce)
+}
+
+// Separation steers a body away from its neighbors, more strongly from those
+// closer to it.
+func Separation(b Body, neighbors []*Body) floatgeom.Point2 {
+	push := floatgeom.Point2{}
+	for _, n := range neighbors {
+		away := b.Position.Sub(n.Position)
+		dist := away.Magnitude()
+		if dist == 0 {
+			continue
+		}
+		push = push.Add(away.DivConst(dist * dist))
+	}
+	if push == (floatgeom.Point2{}) {
+		return push
+	}
+	return steerFor(b, push.Normalize().MulConst(b.MaxSpeed))
+}
+
+// Alignment steers a body toward the average heading of its neighbors.
+func Alignment(b Body, neighbors []*Body) floatgeom.Point2 {
+	heading := floatgeom.Point2{}
+	for _, n := range neighbors {
+		heading = heading.Add(n.Velocity.Normalize())
+	}
+	if heading == (floatgeom.Point2{}) {
+		return heading
+	}
+	return steerFor(b, heading.Normalize().MulConst(b.MaxSpeed))
+}
+
+// Cohesion steers a body toward the average position of its neighbors.
+func Cohesion(b Body, neighbors []*Body) floatgeom.Point2 {
+	if len(neighbors) == 0 {
+		return floatgeom.Point2{}
+	}
+	sum := floatgeom.Point2{}
+	for _, n := range neighbors {
+		sum = sum.Add(n.Position)
+	}
+	return Seek(b, sum.DivConst(float64(len(neighbors))))
+}
@@ -0,0 +1,60 @@
+package steering
+
+import (
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// A PathFollower steers a body through a series of waypoints.
+type PathFollower struct {
+	Path []floatgeom.Point2
+	// Radius is how close a body must come to a waypoint before moving on to the
+	// next one.
+	Radius float64
+	// Loop causes the path to begin again after its last waypoint. Otherwise, the
+	// body arrives at the last waypoint, slowing within SlowRadius of it.
+	Loop       bool
+	SlowRadius float64
+
+	index int
+}
+
+// NewPathFollower creates a path follower through waypoints.
+func NewPathFollower(radius float64, path ...floatgeom.Point2) *PathFollower {
+	return &PathFollower{Path: path, Radius: radius, SlowRadius: radius * 4}
+}
+
+// Waypoint returns the index of the waypoint being steered toward.
+func (pf *PathFollower) Waypoint() int {
+	return pf.index
+}
+
+// Done returns whether a body has reached the last waypoint of a path which does
+// not loop.
+func (pf *PathFollower) Done(b Body) bool {
+	if len(pf.Path) == 0 {
+		return true
+	}
+	if pf.Loop {
+		return false
+	}
+	return pf.index == len(pf.Path)-1 && b.Position.Distance(pf.Path[pf.index]) <= pf.Radius
+}
+
+// Steer returns the acceleration toward the current waypoint, moving on to the
+// next waypoint once the body is within Radius of it.
+func (pf *PathFollower) Steer(b Body) floatgeom.Point2 {
+	if len(pf.Path) == 0 {
+		return floatgeom.Point2{}
+	}
+	if b.Position.Distance(pf.Path[pf.index]) <= pf.Radius {
+		if pf.index < len(pf.Path)-1 {
+			pf.index++
+		} else if pf.Loop {
+			pf.index = 0
+		}
+	}
+	if !pf.Loop && pf.index == len(pf.Path)-1 {
+		return Arrive(b, pf.Path[pf.index], pf.SlowRadius)
+	}
+	return Seek(b, pf.Path[pf.index])
+}
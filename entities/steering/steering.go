@@ -0,0 +1,99 @@
+package steering
+
+import (
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// A Body is the state steering behaviors act on.
+type Body struct {
+	Position floatgeom.Point2
+	Velocity floatgeom.Point2
+	// MaxSpeed limits the body's velocity, and MaxForce limits the accelerations
+	// behaviors return, in units per second and units per second squared.
+	MaxSpeed float64
+	MaxForce float64
+}
+
+// Truncate returns p, shortened to a magnitude of max if it is longer.
+func Truncate(p floatgeom.Point2, max float64) floatgeom.Point2 {
+	mag := p.Magnitude()
+	if mag <= max || mag == 0 {
+		return p
+	}
+	return p.MulConst(max / mag)
+}
+
+// Integrate accelerates a body for dt seconds and moves it by its new velocity,
+// returning how far it moved. The acceleration is limited to MaxForce and the
+// resulting velocity to MaxSpeed.
+func Integrate(b *Body, accel floatgeom.Point2, dt float64) floatgeom.Point2 {
+	accel = Truncate(accel, b.MaxForce)
+	b.Velocity = Truncate(b.Velocity.Add(accel.MulConst(dt)), b.MaxSpeed)
+	delta := b.Velocity.MulConst(dt)
+	b.Position = b.Position.Add(delta)
+	return delta
+}
+
+// steerFor returns the acceleration which changes a body's velocity to desired.
+// The difference is scaled by MaxForce / MaxSpeed, so bodies respond to changes
+// in desired velocity in about the time they take to reach full speed from rest.
+func steerFor(b Body, desired floatgeom.Point2) floatgeom.Point2 {
+	diff := desired.Sub(b.Velocity)
+	if b.MaxSpeed > 0 {
+		diff = diff.MulConst(b.MaxForce / b.MaxSpeed)
+	}
+	return Truncate(diff, b.MaxForce)
+}
+
+// Seek accelerates a body toward a target at full speed.
+func Seek(b Body, target floatgeom.Point2) floatgeom.Point2 {
+	return steerFor(b, target.Sub(b.Position).Normalize().MulConst(b.MaxSpeed))
+}
+
+// Flee accelerates a body directly away from a threat within panicDistance of
+// it. If panicDistance is zero, threats are fled from at any distance.
+func Flee(b Body, threat floatgeom.Point2, panicDistance float64) floatgeom.Point2 {
+	away := b.Position.Sub(threat)
+	if panicDistance > 0 && away.Magnitude() > panicDistance {
+		return floatgeom.Point2{}
+	}
+	return steerFor(b, away.Normalize().MulConst(b.MaxSpeed))
+}
+
+// Arrive accelerates a body toward a target, slowing down within slowRadius of it
+// to come to a stop at the target.
+func Arrive(b Body, target floatgeom.Point2, slowRadius float64) floatgeom.Point2 {
+	toTarget := target.Sub(b.Position)
+	dist := toTarget.Magnitude()
+	if dist == 0 {
+		return steerFor(b, floatgeom.Point2{})
+	}
+	speed := b.MaxSpeed
+	if dist < slowRadius {
+		speed *= dist / slowRadius
+	}
+	return steerFor(b, toTarget.MulConst(speed/dist))
+}
+
+// predict returns where a target moving at velocity will be by the time a body
+// could reach it.
+func predict(b Body, target, velocity floatgeom.Point2) floatgeom.Point2 {
+	if b.MaxSpeed <= 0 {
+		return target
+	}
+	t := b.Position.Distance(target) / b.MaxSpeed
+	return target.Add(velocity.MulConst(t))
+}
+
+// Pursue seeks where a target moving at velocity will be.
+func Pursue(b Body, target, velocity floatgeom.Point2) floatgeom.Point2 {
+	return Seek(b, predict(b, target, velocity))
+}
+
+// Evade flees from where a threat moving at velocity will be. See Flee.
+func Evade(b Body, threat, velocity floatgeom.Point2, panicDistance float64) floatgeom.Point2 {
+	if panicDistance > 0 && b.Position.Distance(threat) > panicDistance {
+		return floatgeom.Point2{}
+	}
+	return Flee(b, predict(b, threat, velocity), 0)
+}
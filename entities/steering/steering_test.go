@@ -0,0 +1,208 @@
+package steering
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/entities"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/key"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+const dt = 1.0 / 60
+
+func testBody() Body {
+	return Body{MaxSpeed: 100, MaxForce: 400}
+}
+
+func simulate(b *Body, steps int, steer func(Body) floatgeom.Point2) {
+	for i := 0; i < steps; i++ {
+		Integrate(b, steer(*b), dt)
+	}
+}
+
+func TestSeekFleeArrive(t *testing.T) {
+	target := floatgeom.Point2{100, 0}
+	b := testBody()
+	simulate(&b, 30, func(b Body) floatgeom.Point2 { return Seek(b, target) })
+	if b.Position.X() <= 0 || math.Abs(b.Position.Y()) > 1e-9 {
+		t.Fatalf("seek did not move toward target: %v", b.Position)
+	}
+	if b.Velocity.Magnitude() > b.MaxSpeed+1e-9 {
+		t.Fatalf("body exceeded its max speed: %v", b.Velocity)
+	}
+
+	b = testBody()
+	simulate(&b, 30, func(b Body) floatgeom.Point2 { return Flee(b, target, 0) })
+	if b.Position.X() >= 0 {
+		t.Fatalf("flee did not move away from threat: %v", b.Position)
+	}
+	if f := Flee(testBody(), target, 50); f != (floatgeom.Point2{}) {
+		t.Fatalf("fled from threat beyond panic distance: %v", f)
+	}
+
+	b = testBody()
+	simulate(&b, 600, func(b Body) floatgeom.Point2 { return Arrive(b, target, 50) })
+	if b.Position.Distance(target) > 1 || b.Velocity.Magnitude() > 1 {
+		t.Fatalf("arrive did not stop at target: %v %v", b.Position, b.Velocity)
+	}
+}
+
+func TestPursueEvade(t *testing.T) {
+	b := testBody()
+	target, velocity := floatgeom.Point2{100, 0}, floatgeom.Point2{0, 50}
+	if p := Pursue(b, target, velocity); p.Y() <= 0 {
+		t.Fatalf("pursue did not lead its target: %v", p)
+	}
+	if e := Evade(b, target, velocity, 0); e.Y() >= 0 || e.X() >= 0 {
+		t.Fatalf("evade did not flee from the predicted position: %v", e)
+	}
+	if e := Evade(b, target, velocity, 50); e != (floatgeom.Point2{}) {
+		t.Fatalf("evaded threat beyond panic distance: %v", e)
+	}
+}
+
+func TestWander(t *testing.T) {
+	positions := [2]floatgeom.Point2{}
+	for i := range positions {
+		w := NewWander(20, 10, .5)
+		w.Rand = rand.New(rand.NewSource(1))
+		b := testBody()
+		simulate(&b, 120, w.Steer)
+		positions[i] = b.Position
+	}
+	if positions[0] != positions[1] {
+		t.Fatalf("seeded wanders diverged: %v", positions)
+	}
+	if positions[0].Magnitude() < 10 {
+		t.Fatalf("wander did not move: %v", positions[0])
+	}
+}
+
+func TestPathFollower(t *testing.T) {
+	pf := NewPathFollower(5, floatgeom.Point2{50, 0}, floatgeom.Point2{50, 50}, floatgeom.Point2{0, 50})
+	b := testBody()
+	simulate(&b, 600, pf.Steer)
+	if !pf.Done(b) || pf.Waypoint() != 2 {
+		t.Fatalf("path was not completed: at %v, waypoint %d", b.Position, pf.Waypoint())
+	}
+	pf = NewPathFollower(5, floatgeom.Point2{50, 0}, floatgeom.Point2{0, 0})
+	pf.Loop = true
+	visited := map[int]bool{}
+	simulate(&b, 600, func(b Body) floatgeom.Point2 {
+		visited[pf.Waypoint()] = true
+		return pf.Steer(b)
+	})
+	if pf.Done(b) || len(visited) != 2 {
+		t.Fatalf("looping path did not visit both waypoints: %v", visited)
+	}
+}
+
+func TestAvoidObstacles(t *testing.T) {
+	tree := collision.NewTree()
+	tree.Add(collision.NewLabeledSpace(40, -12, 10, 20, 1))
+	b := testBody()
+	b.Velocity = floatgeom.Point2{100, 0}
+	dims := floatgeom.Point2{10, 10}
+	if a := AvoidObstacles(b, tree, nil, dims, 60); a.Y() <= 0 {
+		t.Fatalf("expected to steer down, away from the obstacle, got %v", a)
+	}
+	if a := AvoidObstacles(b, tree, nil, dims, 60, 2); a != (floatgeom.Point2{}) {
+		t.Fatalf("avoided obstacle without a matching label: %v", a)
+	}
+	if a := AvoidObstacles(b, tree, nil, dims, 20); a != (floatgeom.Point2{}) {
+		t.Fatalf("avoided obstacle beyond lookahead: %v", a)
+	}
+}
+
+func TestFlock(t *testing.T) {
+	tree := collision.NewTree()
+	f := NewFlock(tree, 4, 100)
+	spaces := make([]*collision.Space, 3)
+	bodies := make([]*Body, 3)
+	for i := range spaces {
+		x := float64(i) * 20
+		spaces[i] = collision.NewUnassignedSpace(x-1, -1, 2, 2)
+		bodies[i] = &Body{Position: floatgeom.Point2{x, 0}, Velocity: floatgeom.Point2{0, 10 * float64(i)}, MaxSpeed: 100, MaxForce: 400}
+		tree.Add(spaces[i])
+		f.Add(spaces[i], bodies[i])
+	}
+	outsider := collision.NewUnassignedSpace(9, 9, 2, 2)
+	tree.Add(outsider)
+	if ns := f.NeighborsOf(spaces[0]); len(ns) != 2 {
+		t.Fatalf("expected two neighbors, got %d", len(ns))
+	}
+	if c := f.Steer(spaces[0], FlockWeights{Cohesion: 1}); c.X() <= 0 {
+		t.Fatalf("expected cohesion toward the flock, got %v", c)
+	}
+	if s := f.Steer(spaces[0], FlockWeights{Separation: 1}); s.X() >= 0 {
+		t.Fatalf("expected separation away from the flock, got %v", s)
+	}
+	if a := f.Steer(spaces[0], FlockWeights{Alignment: 1}); a.Y() <= 0 {
+		t.Fatalf("expected alignment with the flock's heading, got %v", a)
+	}
+	f.Remove(spaces[1], spaces[2])
+	if ns := f.NeighborsOf(spaces[0]); len(ns) != 0 {
+		t.Fatalf("expected removed bodies not to be neighbors, got %d", len(ns))
+	}
+}
+
+func TestFlockNeighborsPastOutsiders(t *testing.T) {
+	tree := collision.NewTree()
+	f := NewFlock(tree, 3, 100)
+	self := collision.NewUnassignedSpace(-1, -1, 2, 2)
+	tree.Add(self)
+	f.Add(self, &Body{})
+	// crowd the body with spaces that are not in the flock
+	for i := 0; i < 50; i++ {
+		tree.Add(collision.NewUnassignedSpace(float64(i%10)*2, float64(i/10)*2+4, 1, 1))
+	}
+	for i := 0; i < 3; i++ {
+		x := float64(i)*10 + 40
+		s := collision.NewUnassignedSpace(x-1, -1, 2, 2)
+		tree.Add(s)
+		f.Add(s, &Body{Position: floatgeom.Point2{x, 0}})
+	}
+	far := collision.NewUnassignedSpace(199, -1, 2, 2)
+	tree.Add(far)
+	f.Add(far, &Body{Position: floatgeom.Point2{200, 0}})
+	if ns := f.NeighborsOf(self); len(ns) != 3 {
+		t.Fatalf("expected three neighbors past the outsiders, got %d", len(ns))
+	}
+	f.Neighbors = 5
+	if ns := f.NeighborsOf(self); len(ns) != 3 {
+		t.Fatalf("expected only the three neighbors within radius, got %d", len(ns))
+	}
+}
+
+func TestAgent(t *testing.T) {
+	cm := event.NewCallerMap()
+	ks := key.NewState()
+	ctx := &scene.Context{
+		CallerMap:     cm,
+		Handler:       event.NewBus(cm),
+		DrawStack:     render.NewDrawStack(render.NewDynamicHeap()),
+		State:         &ks,
+		CollisionTree: collision.NewTree(),
+	}
+	e := entities.New(ctx, entities.WithRect(floatgeom.NewRect2WH(0, 0, 10, 10)), entities.WithColor(color.RGBA{255, 0, 0, 255}))
+	a := NewAgent(e, 100, 400)
+	target := floatgeom.Point2{105, 5}
+	a.Steer = func(a *Agent) floatgeom.Point2 { return Arrive(a.Body, target, 40) }
+	for i := 0; i < 300; i++ {
+		a.Update(time.Second / 60)
+	}
+	if d := e.Rect.Center().Distance(target); d > 1 {
+		t.Fatalf("agent did not arrive at target, was %v away", d)
+	}
+	if s := e.Space; math.Abs(s.X()-e.X()) > 1e-9 {
+		t.Fatalf("entity space did not move with agent: %v", s.Location)
+	}
+}
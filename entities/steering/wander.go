@@ -0,0 +1,46 @@
+package steering
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/oakmound/oak/v4/alg"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// A Wander steers a body randomly but smoothly, by seeking a point on a circle
+// projected in front of it which drifts around the circle each step.
+type Wander struct {
+	// Distance is how far ahead of the body the circle is, and Radius is its size.
+	Distance float64
+	Radius   float64
+	// Jitter is the most the point may move around the circle each step, in
+	// radians.
+	Jitter float64
+	// Rand, if set, is used for randomness. Otherwise, math/rand is used.
+	Rand alg.Float64Generator
+
+	angle float64
+}
+
+// NewWander creates a wander with the given circle distance, circle radius, and
+// jitter.
+func NewWander(distance, radius, jitter float64) *Wander {
+	return &Wander{Distance: distance, Radius: radius, Jitter: jitter}
+}
+
+// Steer returns the acceleration for the next step of wandering.
+func (w *Wander) Steer(b Body) floatgeom.Point2 {
+	r := rand.Float64
+	if w.Rand != nil {
+		r = w.Rand.Float64
+	}
+	w.angle += (r()*2 - 1) * w.Jitter
+	heading := b.Velocity.Normalize()
+	if heading == (floatgeom.Point2{}) {
+		heading = floatgeom.Point2{1, 0}
+	}
+	center := b.Position.Add(heading.MulConst(w.Distance))
+	offset := floatgeom.Point2{math.Cos(w.angle), math.Sin(w.angle)}.MulConst(w.Radius)
+	return Seek(b, center.Add(offset))
+}
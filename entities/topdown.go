@@ -0,0 +1,204 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/joystick"
+	"github.com/oakmound/oak/v4/key"
+)
+
+// A TopDownController moves its entity in eight or more directions with
+// acceleration and friction, sliding along the spaces in the entity's collision tree.
+type TopDownController struct {
+	*Entity
+
+	// SolidLabels are the labels of spaces which block movement. If empty, all
+	// spaces block movement.
+	SolidLabels []collision.Label
+
+	// Input is called each frame to get the direction the entity should move in.
+	// Its magnitude should be at most 1; lesser magnitudes, as from analog sticks,
+	// move the entity more slowly.
+	Input func() floatgeom.Point2
+	// InputCurve, if set, remaps the magnitude of input before it is applied,
+	// e.g. to give finer control with small stick movements.
+	InputCurve func(float64) float64
+
+	// Velocity is the entity's current velocity, in units per second.
+	Velocity floatgeom.Point2
+
+	// MaxSpeed is the entity's speed at full input.
+	MaxSpeed float64
+	// Acceleration is how quickly the entity reaches its target velocity while
+	// there is input, and Friction is how quickly it stops while there is not, in
+	// units per second squared. Zero values change velocity instantly.
+	Acceleration float64
+	Friction     float64
+}
+
+// NewTopDownController creates a top down controller for an entity, reading input
+// from W, A, S, and D. The entity's Speed is not used.
+func NewTopDownController(e *Entity) *TopDownController {
+	tdc := &TopDownController{
+		Entity:       e,
+		MaxSpeed:     200,
+		Acceleration: 1500,
+		Friction:     1500,
+	}
+	tdc.Input = KeyDirection(e.ctx.State, key.W, key.S, key.A, key.D)
+	return tdc
+}
+
+// KeyDirection returns an input function for the given keys. Diagonal input is
+// normalized, so it is no faster than input along one axis.
+func KeyDirection(ks *key.State, up, down, left, right key.Code) func() floatgeom.Point2 {
+	return func() floatgeom.Point2 {
+		dir := floatgeom.Point2{}
+		if ks.IsDown(up) {
+			dir[1]--
+		}
+		if ks.IsDown(down) {
+			dir[1]++
+		}
+		if ks.IsDown(left) {
+			dir[0]--
+		}
+		if ks.IsDown(right) {
+			dir[0]++
+		}
+		if dir == (floatgeom.Point2{}) {
+			return dir
+		}
+		return dir.Normalize()
+	}
+}
+
+// AnalogDirection converts an analog stick's axes to a direction with a magnitude of
+// at most 1, oriented with positive y downwards. Stick positions within deadzone,
+// from 0 to 1, of the center are treated as centered, and magnitudes past the
+// deadzone are rescaled to start from 0.
+func AnalogDirection(x, y int16, deadzone float64) floatgeom.Point2 {
+	dir := floatgeom.Point2{float64(x) / math.MaxInt16, -float64(y) / math.MaxInt16}
+	mag := dir.Magnitude()
+	if mag <= deadzone || deadzone >= 1 {
+		return floatgeom.Point2{}
+	}
+	scaled := math.Min((mag-deadzone)/(1-deadzone), 1)
+	return dir.MulConst(scaled / mag)
+}
+
+// LeftStick returns an input function reading the left analog stick of the joystick
+// states returned by getState, which may return nil if no state is available.
+func LeftStick(getState func() *joystick.State, deadzone float64) func() floatgeom.Point2 {
+	return func() floatgeom.Point2 {
+		st := getState()
+		if st == nil {
+			return floatgeom.Point2{}
+		}
+		return AnalogDirection(st.StickLX, st.StickLY, deadzone)
+	}
+}
+
+// RightStick acts like LeftStick, reading the right analog stick.
+func RightStick(getState func() *joystick.State, deadzone float64) func() floatgeom.Point2 {
+	return func() floatgeom.Point2 {
+		st := getState()
+		if st == nil {
+			return floatgeom.Point2{}
+		}
+		return AnalogDirection(st.StickRX, st.StickRY, deadzone)
+	}
+}
+
+// Bind causes this controller to Update every frame, until the returned binding
+// is unbound or the entity's bindings are unbound.
+func (tdc *TopDownController) Bind() event.Binding {
+	return tdc.ctx.UnsafeBind(event.Enter.UnsafeEventID, tdc.CID(), func(_ event.CallerID, _ event.Handler, payload interface{}) event.Response {
+		tdc.Update(payload.(event.EnterPayload).SinceLastFrame)
+		return 0
+	})
+}
+
+// Update moves the entity by the given elapsed time.
+func (tdc *TopDownController) Update(elapsed time.Duration) {
+	dt := elapsed.Seconds()
+	dir := floatgeom.Point2{}
+	if tdc.Input != nil {
+		dir = tdc.Input()
+	}
+	mag := dir.Magnitude()
+	if mag > 1 {
+		dir = dir.DivConst(mag)
+		mag = 1
+	}
+	if mag > 0 && tdc.InputCurve != nil {
+		dir = dir.MulConst(tdc.InputCurve(mag) / mag)
+	}
+	if mag > 0 {
+		tdc.Velocity = approachPoint(tdc.Velocity, dir.MulConst(tdc.MaxSpeed), tdc.Acceleration*dt)
+	} else {
+		tdc.Velocity = approachPoint(tdc.Velocity, floatgeom.Point2{}, tdc.Friction*dt)
+	}
+	tdc.Delta = tdc.Velocity.MulConst(dt)
+	tdc.slide()
+}
+
+func approachPoint(v, target floatgeom.Point2, step float64) floatgeom.Point2 {
+	diff := target.Sub(v)
+	dist := diff.Magnitude()
+	if step <= 0 || dist <= step {
+		return target
+	}
+	return v.Add(diff.MulConst(step / dist))
+}
+
+// slide moves the entity by its Delta one axis at a time, stopping at blocking
+// spaces on each axis so the entity slides along walls it moves diagonally into.
+func (tdc *TopDownController) slide() {
+	if dx := tdc.Delta.X(); dx != 0 {
+		next := tdc.Rect.Shift(floatgeom.Point2{dx, 0})
+		probe := floatgeom.NewRect2(next.Min.X(), next.Min.Y()+collisionSkin, next.Max.X(), next.Max.Y()-collisionSkin)
+		x := next.Min.X()
+		blocked := false
+		for _, s := range tdc.hitsAt(probe) {
+			if !s.MatchesLabels(tdc.SolidLabels...) {
+				continue
+			}
+			blocked = true
+			if dx > 0 {
+				x = math.Min(x, s.Location.Min.X()-tdc.W())
+			} else {
+				x = math.Max(x, s.Location.Max.X())
+			}
+		}
+		if blocked {
+			tdc.Velocity[0] = 0
+		}
+		tdc.SetX(x)
+	}
+	if dy := tdc.Delta.Y(); dy != 0 {
+		next := tdc.Rect.Shift(floatgeom.Point2{0, dy})
+		probe := floatgeom.NewRect2(next.Min.X()+collisionSkin, next.Min.Y(), next.Max.X()-collisionSkin, next.Max.Y())
+		y := next.Min.Y()
+		blocked := false
+		for _, s := range tdc.hitsAt(probe) {
+			if !s.MatchesLabels(tdc.SolidLabels...) {
+				continue
+			}
+			blocked = true
+			if dy > 0 {
+				y = math.Min(y, s.Location.Min.Y()-tdc.H())
+			} else {
+				y = math.Max(y, s.Location.Max.Y())
+			}
+		}
+		if blocked {
+			tdc.Velocity[1] = 0
+		}
+		tdc.SetY(y)
+	}
+}
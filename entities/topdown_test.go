@@ -0,0 +1,160 @@
+package entities
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+func TestAnalogDirection(t *testing.T) {
+	const half = math.MaxInt16 / 2
+	tests := []struct {
+		name     string
+		x, y     int16
+		deadzone float64
+		want     floatgeom.Point2
+	}{
+		{"centered", 0, 0, .2, floatgeom.Point2{}},
+		{"inside deadzone", 3000, -3000, .2, floatgeom.Point2{}},
+		{"full right", math.MaxInt16, 0, .2, floatgeom.Point2{1, 0}},
+		{"full up is negative y", 0, math.MaxInt16, .2, floatgeom.Point2{0, -1}},
+		{"full down clamps", 0, math.MinInt16, .2, floatgeom.Point2{0, 1}},
+		{"half right rescaled", half, 0, .2, floatgeom.Point2{(.5 - .2) / .8, 0}},
+		{"half right no deadzone", half, 0, 0, floatgeom.Point2{.5, 0}},
+		{"deadzone of one", math.MaxInt16, 0, 1, floatgeom.Point2{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AnalogDirection(tc.x, tc.y, tc.deadzone)
+			if got.Sub(tc.want).Magnitude() > .001 {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalogDirectionMagnitude(t *testing.T) {
+	// Full diagonals are past the unit circle, and must be clamped.
+	got := AnalogDirection(math.MaxInt16, math.MaxInt16, .1)
+	if mag := got.Magnitude(); math.Abs(mag-1) > .001 {
+		t.Fatalf("expected diagonal magnitude 1, got %v", mag)
+	}
+}
+
+func newTopDownTest(solids ...floatgeom.Rect2) (*TopDownController, *floatgeom.Point2) {
+	ctx := testContext()
+	for _, r := range solids {
+		ctx.CollisionTree.Add(collision.NewSpace(r.Min.X(), r.Min.Y(), r.W(), r.H(), 0))
+	}
+	tdc := NewTopDownController(testEntity(ctx, 0, 0, 10, 10))
+	in := new(floatgeom.Point2)
+	tdc.Input = func() floatgeom.Point2 {
+		return *in
+	}
+	return tdc, in
+}
+
+func TestTopDownAcceleration(t *testing.T) {
+	tests := []struct {
+		name          string
+		accel         float64
+		friction      float64
+		input         floatgeom.Point2
+		startVelocity floatgeom.Point2
+		elapsed       time.Duration
+		want          floatgeom.Point2
+	}{
+		{"accelerating", 1000, 1000, floatgeom.Point2{1, 0}, floatgeom.Point2{}, 100 * time.Millisecond, floatgeom.Point2{100, 0}},
+		{"reaches max speed", 1000, 1000, floatgeom.Point2{1, 0}, floatgeom.Point2{}, time.Second, floatgeom.Point2{200, 0}},
+		{"partial input", 1000, 1000, floatgeom.Point2{0, .5}, floatgeom.Point2{}, time.Second, floatgeom.Point2{0, 100}},
+		{"instant acceleration", 0, 1000, floatgeom.Point2{-1, 0}, floatgeom.Point2{}, frame, floatgeom.Point2{-200, 0}},
+		{"turning", 1000, 1000, floatgeom.Point2{-1, 0}, floatgeom.Point2{200, 0}, 100 * time.Millisecond, floatgeom.Point2{100, 0}},
+		{"friction", 1000, 500, floatgeom.Point2{}, floatgeom.Point2{200, 0}, 100 * time.Millisecond, floatgeom.Point2{150, 0}},
+		{"friction stops", 1000, 500, floatgeom.Point2{}, floatgeom.Point2{0, -30}, 100 * time.Millisecond, floatgeom.Point2{}},
+		{"instant friction", 1000, 0, floatgeom.Point2{}, floatgeom.Point2{200, 200}, frame, floatgeom.Point2{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tdc, in := newTopDownTest()
+			tdc.Acceleration = tc.accel
+			tdc.Friction = tc.friction
+			tdc.Velocity = tc.startVelocity
+			*in = tc.input
+			tdc.Update(tc.elapsed)
+			if tdc.Velocity.Sub(tc.want).Magnitude() > .001 {
+				t.Fatalf("expected velocity %v, got %v", tc.want, tdc.Velocity)
+			}
+			moved := tdc.Velocity.MulConst(tc.elapsed.Seconds())
+			if got := (floatgeom.Point2{tdc.X(), tdc.Y()}); got.Sub(moved).Magnitude() > .001 {
+				t.Fatalf("expected to move to %v, at %v", moved, got)
+			}
+		})
+	}
+}
+
+func TestTopDownInputCurve(t *testing.T) {
+	tdc, in := newTopDownTest()
+	tdc.Acceleration = 0
+	tdc.InputCurve = func(f float64) float64 {
+		return f * f
+	}
+	*in = floatgeom.Point2{.5, 0}
+	tdc.Update(frame)
+	if want := (floatgeom.Point2{50, 0}); tdc.Velocity.Sub(want).Magnitude() > .001 {
+		t.Fatalf("expected curved velocity %v, got %v", want, tdc.Velocity)
+	}
+}
+
+func TestTopDownWallSlide(t *testing.T) {
+	tests := []struct {
+		name  string
+		wall  floatgeom.Rect2
+		input floatgeom.Point2
+		// blocked is the axis the wall blocks, which should stop at wallAt.
+		blocked int
+		wallAt  float64
+	}{
+		{"right wall", floatgeom.NewRect2WH(20, -100, 10, 200), floatgeom.Point2{1, 1}, 0, 10},
+		{"left wall", floatgeom.NewRect2WH(-30, -100, 10, 200), floatgeom.Point2{-1, -1}, 0, -20},
+		{"floor", floatgeom.NewRect2WH(-100, 20, 200, 10), floatgeom.Point2{-1, 1}, 1, 10},
+		{"ceiling", floatgeom.NewRect2WH(-100, -30, 200, 10), floatgeom.Point2{1, -1}, 1, -20},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tdc, in := newTopDownTest(tc.wall)
+			tdc.Acceleration = 0
+			*in = tc.input.Normalize()
+			for i := 0; i < 30; i++ {
+				tdc.Update(frame)
+			}
+			pos := floatgeom.Point2{tdc.X(), tdc.Y()}
+			free := 1 - tc.blocked
+			if math.Abs(pos[tc.blocked]-tc.wallAt) > .001 {
+				t.Fatalf("expected to stop against the wall at %v, at %v", tc.wallAt, pos)
+			}
+			if tdc.Velocity[tc.blocked] != 0 {
+				t.Fatalf("expected velocity into the wall to stop, got %v", tdc.Velocity)
+			}
+			if math.Abs(pos[free]) < 50 || math.Signbit(pos[free]) != math.Signbit(tc.input[free]) {
+				t.Fatalf("expected to slide along the wall, at %v", pos)
+			}
+		})
+	}
+}
+
+func TestTopDownSolidLabels(t *testing.T) {
+	tdc, in := newTopDownTest()
+	tdc.ctx.CollisionTree.Add(collision.NewLabeledSpace(20, -100, 10, 200, 5))
+	tdc.SolidLabels = []collision.Label{6}
+	tdc.Acceleration = 0
+	*in = floatgeom.Point2{1, 0}
+	for i := 0; i < 30; i++ {
+		tdc.Update(frame)
+	}
+	if tdc.X() < 30 {
+		t.Fatalf("expected to pass through a space without a solid label, at %v", tdc.X())
+	}
+}
@@ -0,0 +1,331 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/render/mod"
+)
+
+// A Transform places an entity relative to a parent transform with a local
+// position, rotation, and scale. Changing a transform moves its entity's rect,
+// renderable, and space, and those of every transform beneath it.
+//
+// Positions are of entity centers, and rotations are in degrees, clockwise on
+// screen. Entity spaces stay axis aligned, bounding the rotated and scaled entity.
+// Rotation and scale are drawn by reverting and modifying the entity's Renderable
+// as a *render.Reverting. NewTransform wraps modifiable renderables drawn by New
+// in a Reverting; entities drawing their own renderables should give them a
+// Reverting for rotation and scale to be drawn. Other renderables are only kept
+// centered on their entity.
+//
+// Entities in a transform hierarchy should be moved through their transforms,
+// not by shifting the entities directly.
+type Transform struct {
+	// Entity is the entity this transform places. It may be nil, for transforms
+	// which only group their children.
+	Entity *Entity
+
+	position floatgeom.Point2
+	rotation float64
+	scale    floatgeom.Point2
+
+	// size is the unscaled dimensions of the entity.
+	size floatgeom.Point2
+
+	// drawnRotation and drawnScale are the rotation and scale last applied to the entity's renderable.
+	drawnRotation float64
+	drawnScale    floatgeom.Point2
+
+	parent   *Transform
+	children []*Transform
+}
+
+// NewTransform creates a root transform for an entity, at the entity's current
+// center with no rotation and a scale of one. The entity's Transform is set to
+// the new transform, and its Renderable may be replaced by a redrawn
+// *render.Reverting. If e is nil, the transform will be at (0,0).
+func NewTransform(e *Entity) *Transform {
+	t := &Transform{
+		Entity:     e,
+		scale:      floatgeom.Point2{1, 1},
+		drawnScale: floatgeom.Point2{1, 1},
+	}
+	if e != nil {
+		t.position = e.Rect.Center()
+		t.size = floatgeom.Point2{e.W(), e.H()}
+		e.Transform = t
+		e.makeReverting()
+	}
+	return t
+}
+
+// makeReverting replaces an entity's renderable drawn by New with a Reverting copy,
+// so its transform can rotate and scale it.
+func (e *Entity) makeReverting() {
+	m, ok := e.Renderable.(render.Modifiable)
+	if !ok || len(e.drawLayers) == 0 {
+		return
+	}
+	if _, ok := m.(*render.Reverting); ok {
+		return
+	}
+	// The Reverting shares its layer with what it wraps, so it must wrap a copy
+	// for the original to be undrawn.
+	rv := render.NewReverting(m.Copy())
+	m.Undraw()
+	e.ctx.Draw(rv, e.drawLayers...)
+	e.Renderable = rv
+}
+
+// Parent returns this transform's parent, or nil if it is a root.
+func (t *Transform) Parent() *Transform {
+	return t.parent
+}
+
+// Children returns the transforms whose parent is this transform.
+func (t *Transform) Children() []*Transform {
+	return append([]*Transform{}, t.children...)
+}
+
+// SetParent moves this transform beneath a new parent, or makes it a root if
+// parent is nil. If keepWorld is true, the transform's local values are changed
+// so that it stays where it is in the world; otherwise its local values are kept
+// and it moves with its new parent. Parenting a transform to itself or one of
+// its descendants returns an InvalidInput error.
+func (t *Transform) SetParent(parent *Transform, keepWorld bool) error {
+	for p := parent; p != nil; p = p.parent {
+		if p == t {
+			return oakerr.InvalidInput{InputName: "parent"}
+		}
+	}
+	pos, rot, scale := t.WorldPosition(), t.WorldRotation(), t.WorldScale()
+	t.detach()
+	t.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, t)
+	}
+	if keepWorld {
+		t.position = t.parentToLocal(pos)
+		t.rotation = rot - t.parentRotation()
+		t.scale = unscale(scale, t.parentScale())
+	}
+	t.apply()
+	return nil
+}
+
+// AddChild parents c to this transform. See SetParent.
+func (t *Transform) AddChild(c *Transform, keepWorld bool) error {
+	return c.SetParent(t, keepWorld)
+}
+
+func (t *Transform) detach() {
+	if t.parent == nil {
+		return
+	}
+	siblings := t.parent.children
+	for i, c := range siblings {
+		if c == t {
+			t.parent.children = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+	t.parent = nil
+}
+
+// Position returns this transform's position relative to its parent.
+func (t *Transform) Position() floatgeom.Point2 {
+	return t.position
+}
+
+// Rotation returns this transform's rotation relative to its parent.
+func (t *Transform) Rotation() float64 {
+	return t.rotation
+}
+
+// Scale returns this transform's scale relative to its parent.
+func (t *Transform) Scale() floatgeom.Point2 {
+	return t.scale
+}
+
+// SetPosition sets this transform's position relative to its parent.
+func (t *Transform) SetPosition(p floatgeom.Point2) {
+	t.position = p
+	t.apply()
+}
+
+// ShiftPosition moves this transform by delta, relative to its parent.
+func (t *Transform) ShiftPosition(delta floatgeom.Point2) {
+	t.SetPosition(t.position.Add(delta))
+}
+
+// SetRotation sets this transform's rotation relative to its parent.
+func (t *Transform) SetRotation(degrees float64) {
+	t.rotation = degrees
+	t.apply()
+}
+
+// Rotate rotates this transform by some degrees.
+func (t *Transform) Rotate(degrees float64) {
+	t.SetRotation(t.rotation + degrees)
+}
+
+// SetScale sets this transform's scale relative to its parent.
+func (t *Transform) SetScale(s floatgeom.Point2) {
+	t.scale = s
+	t.apply()
+}
+
+// WorldPosition returns this transform's position in the world.
+func (t *Transform) WorldPosition() floatgeom.Point2 {
+	if t.parent == nil {
+		return t.position
+	}
+	return t.parent.ToWorld(t.position)
+}
+
+// WorldRotation returns this transform's rotation in the world.
+func (t *Transform) WorldRotation() float64 {
+	return t.parentRotation() + t.rotation
+}
+
+// WorldScale returns this transform's scale in the world.
+func (t *Transform) WorldScale() floatgeom.Point2 {
+	return t.parentScale().Mul(t.scale)
+}
+
+// SetWorldPosition moves this transform to a position in the world.
+func (t *Transform) SetWorldPosition(p floatgeom.Point2) {
+	t.SetPosition(t.parentToLocal(p))
+}
+
+// SetWorldRotation sets this transform's rotation in the world.
+func (t *Transform) SetWorldRotation(degrees float64) {
+	t.SetRotation(degrees - t.parentRotation())
+}
+
+// ToWorld converts a point relative to this transform to a point in the world.
+func (t *Transform) ToWorld(local floatgeom.Point2) floatgeom.Point2 {
+	return t.WorldPosition().Add(rotate(local.Mul(t.WorldScale()), t.WorldRotation()))
+}
+
+// ToLocal converts a point in the world to a point relative to this transform.
+func (t *Transform) ToLocal(world floatgeom.Point2) floatgeom.Point2 {
+	return unscale(rotate(world.Sub(t.WorldPosition()), -t.WorldRotation()), t.WorldScale())
+}
+
+func (t *Transform) parentToLocal(world floatgeom.Point2) floatgeom.Point2 {
+	if t.parent == nil {
+		return world
+	}
+	return t.parent.ToLocal(world)
+}
+
+func (t *Transform) parentRotation() float64 {
+	if t.parent == nil {
+		return 0
+	}
+	return t.parent.WorldRotation()
+}
+
+func (t *Transform) parentScale() floatgeom.Point2 {
+	if t.parent == nil {
+		return floatgeom.Point2{1, 1}
+	}
+	return t.parent.WorldScale()
+}
+
+// Destroy destroys this transform's entity and every transform beneath it, and
+// removes this transform from its parent.
+func (t *Transform) Destroy() {
+	if t.Entity != nil {
+		// Entity.Destroy destroys this transform's descendants
+		t.Entity.Destroy()
+		return
+	}
+	t.destroy()
+}
+
+func (t *Transform) destroy() {
+	children := t.children
+	t.children = nil
+	for _, c := range children {
+		c.parent = nil
+		c.Destroy()
+	}
+	t.detach()
+}
+
+// apply moves this transform's entity, and those of its descendants, to their
+// world positions.
+func (t *Transform) apply() {
+	if e := t.Entity; e != nil {
+		center := t.WorldPosition()
+		rot := t.WorldRotation()
+		scale := t.WorldScale()
+		w, h := bounds(t.size.Mul(scale), rot)
+		e.Rect = floatgeom.NewRect2WH(center.X()-w/2, center.Y()-h/2, w, h)
+		if e.Space != nil {
+			e.updateSpace()
+		}
+		if e.Renderable != nil {
+			if rv, ok := e.Renderable.(*render.Reverting); ok && (rot != t.drawnRotation || scale != t.drawnScale) {
+				t.drawnRotation, t.drawnScale = rot, scale
+				if rot == 0 && scale == (floatgeom.Point2{1, 1}) {
+					rv.RevertAll()
+				} else {
+					rv.RevertAndModify(math.MaxInt32, transformMods(rot, scale)...)
+				}
+			}
+			rw, rh := e.Renderable.GetDims()
+			e.Renderable.SetPos(center.X()-float64(rw)/2, center.Y()-float64(rh)/2)
+		}
+	}
+	for _, c := range t.children {
+		c.apply()
+	}
+}
+
+func transformMods(rot float64, scale floatgeom.Point2) []mod.Mod {
+	mods := []mod.Mod{mod.Scale(math.Abs(scale.X()), math.Abs(scale.Y()))}
+	if scale.X() < 0 {
+		mods = append(mods, mod.FlipX)
+	}
+	if scale.Y() < 0 {
+		mods = append(mods, mod.FlipY)
+	}
+	if rot != 0 {
+		// mod.Rotate rotates counter-clockwise
+		mods = append(mods, mod.Rotate(float32(-rot)))
+	}
+	return mods
+}
+
+// rotate rotates p about the origin, clockwise on screen.
+func rotate(p floatgeom.Point2, degrees float64) floatgeom.Point2 {
+	if degrees == 0 {
+		return p
+	}
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return floatgeom.Point2{p.X()*cos - p.Y()*sin, p.X()*sin + p.Y()*cos}
+}
+
+func unscale(p, scale floatgeom.Point2) floatgeom.Point2 {
+	for i := range p {
+		if scale[i] != 0 {
+			p[i] /= scale[i]
+		}
+	}
+	return p
+}
+
+// bounds returns the dimensions of the axis aligned box bounding a rect of size
+// dims rotated by some degrees.
+func bounds(dims floatgeom.Point2, degrees float64) (w, h float64) {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	w, h = math.Abs(dims.X()), math.Abs(dims.Y())
+	return w*cos + h*sin, w*sin + h*cos
+}
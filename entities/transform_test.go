@@ -0,0 +1,189 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+	"github.com/oakmound/oak/v4/render"
+)
+
+func pointsNear(a, b floatgeom.Point2) bool {
+	return a.Sub(b).Magnitude() < .0001
+}
+
+func TestTransformToWorld(t *testing.T) {
+	parent := NewTransform(nil)
+	parent.SetPosition(floatgeom.Point2{100, 50})
+	parent.SetRotation(90)
+	parent.SetScale(floatgeom.Point2{2, 2})
+	child := NewTransform(nil)
+	if err := child.SetParent(parent, false); err != nil {
+		t.Fatalf("failed to parent: %v", err)
+	}
+	child.SetPosition(floatgeom.Point2{10, 0})
+
+	tests := []struct {
+		name  string
+		t     *Transform
+		local floatgeom.Point2
+		world floatgeom.Point2
+	}{
+		{"parent origin", parent, floatgeom.Point2{}, floatgeom.Point2{100, 50}},
+		{"parent x axis", parent, floatgeom.Point2{5, 0}, floatgeom.Point2{100, 60}},
+		{"parent y axis", parent, floatgeom.Point2{0, 5}, floatgeom.Point2{90, 50}},
+		{"child origin", child, floatgeom.Point2{}, floatgeom.Point2{100, 70}},
+		{"child x axis", child, floatgeom.Point2{1, 0}, floatgeom.Point2{100, 72}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.t.ToWorld(tc.local); !pointsNear(got, tc.world) {
+				t.Fatalf("ToWorld(%v): expected %v, got %v", tc.local, tc.world, got)
+			}
+			if got := tc.t.ToLocal(tc.world); !pointsNear(got, tc.local) {
+				t.Fatalf("ToLocal(%v): expected %v, got %v", tc.world, tc.local, got)
+			}
+		})
+	}
+	if got := child.WorldRotation(); got != 90 {
+		t.Fatalf("expected child world rotation 90, got %v", got)
+	}
+	if got := child.WorldScale(); got != (floatgeom.Point2{2, 2}) {
+		t.Fatalf("expected child world scale 2, got %v", got)
+	}
+}
+
+func TestTransformSetParent(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepWorld bool
+		wantWorld floatgeom.Point2
+		wantLocal floatgeom.Point2
+	}{
+		{"keep world", true, floatgeom.Point2{15, 15}, floatgeom.Point2{5, -5}},
+		{"keep local", false, floatgeom.Point2{-5, 25}, floatgeom.Point2{15, 15}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := testContext()
+			parent := NewTransform(testEntity(ctx, 0, 0, 10, 10))
+			parent.SetPosition(floatgeom.Point2{10, 10})
+			parent.SetRotation(90)
+			e := testEntity(ctx, 10, 10, 10, 10)
+			child := NewTransform(e)
+			if err := child.SetParent(parent, tc.keepWorld); err != nil {
+				t.Fatalf("failed to parent: %v", err)
+			}
+			if got := child.WorldPosition(); !pointsNear(got, tc.wantWorld) {
+				t.Fatalf("expected world position %v, got %v", tc.wantWorld, got)
+			}
+			if got := child.Position(); !pointsNear(got, tc.wantLocal) {
+				t.Fatalf("expected local position %v, got %v", tc.wantLocal, got)
+			}
+			if got := e.Rect.Center(); !pointsNear(got, tc.wantWorld) {
+				t.Fatalf("expected entity centered at %v, got %v", tc.wantWorld, got)
+			}
+			wantRot := 90.0
+			if tc.keepWorld {
+				wantRot = 0
+			}
+			if got := child.WorldRotation(); math.Abs(got-wantRot) > .0001 {
+				t.Fatalf("expected world rotation %v, got %v", wantRot, got)
+			}
+			if len(parent.Children()) != 1 {
+				t.Fatalf("expected parent to have one child, had %v", len(parent.Children()))
+			}
+
+			// Moving the parent moves the child with it.
+			parent.ShiftPosition(floatgeom.Point2{5, 0})
+			if got := e.Rect.Center(); !pointsNear(got, tc.wantWorld.Add(floatgeom.Point2{5, 0})) {
+				t.Fatalf("expected child to follow its parent, at %v", got)
+			}
+
+			if err := child.SetParent(nil, true); err != nil {
+				t.Fatalf("failed to unparent: %v", err)
+			}
+			if len(parent.Children()) != 0 || child.Parent() != nil {
+				t.Fatalf("expected child to be detached")
+			}
+		})
+	}
+}
+
+func TestTransformSetParentCycle(t *testing.T) {
+	a, b := NewTransform(nil), NewTransform(nil)
+	if err := b.SetParent(a, false); err != nil {
+		t.Fatalf("failed to parent: %v", err)
+	}
+	for _, err := range []error{a.SetParent(a, false), a.SetParent(b, false)} {
+		if !errors.As(err, &oakerr.InvalidInput{}) {
+			t.Fatalf("expected invalid input parenting to a descendant, got %v", err)
+		}
+	}
+}
+
+func TestTransformRotatesRenderable(t *testing.T) {
+	ctx := testContext()
+	e := testEntity(ctx, 0, 0, 20, 10)
+	original := e.Renderable
+	tr := NewTransform(e)
+	if _, ok := e.Renderable.(*render.Reverting); !ok {
+		t.Fatalf("expected the entity's renderable to be made reverting, got %T", e.Renderable)
+	}
+	if original.GetLayer() != render.Undraw {
+		t.Fatalf("expected the original renderable to be undrawn")
+	}
+	tr.SetRotation(90)
+	if w, h := e.W(), e.H(); math.Abs(w-10) > .0001 || math.Abs(h-20) > .0001 {
+		t.Fatalf("expected rotated entity to be 10x20, was %vx%v", w, h)
+	}
+	if w, h := e.Renderable.GetDims(); w != 10 || h != 20 {
+		t.Fatalf("expected rotated renderable to be 10x20, was %vx%v", w, h)
+	}
+	tr.SetRotation(0)
+	tr.SetScale(floatgeom.Point2{2, 3})
+	if w, h := e.Renderable.GetDims(); w != 40 || h != 30 {
+		t.Fatalf("expected scaled renderable to be 40x30, was %vx%v", w, h)
+	}
+}
+
+func TestTransformDestroy(t *testing.T) {
+	ctx := testContext()
+	root := NewTransform(testEntity(ctx, 0, 0, 10, 10))
+	group := NewTransform(nil)
+	leaf := NewTransform(testEntity(ctx, 20, 0, 10, 10))
+	sibling := NewTransform(testEntity(ctx, 40, 0, 10, 10))
+	must(t, group.SetParent(root, true))
+	must(t, leaf.SetParent(group, true))
+	must(t, sibling.SetParent(root, true))
+
+	group.Destroy()
+	if leaf.Entity.Renderable.GetLayer() != render.Undraw {
+		t.Fatalf("expected descendant entity to be undrawn")
+	}
+	if ctx.CollisionTree.Remove(leaf.Entity.Space) != 0 {
+		t.Fatalf("expected descendant entity's space to be removed")
+	}
+	if len(root.Children()) != 1 || group.Parent() != nil {
+		t.Fatalf("expected destroyed transform to be detached")
+	}
+
+	root.Destroy()
+	for _, e := range []*Entity{root.Entity, sibling.Entity} {
+		if e.Renderable.GetLayer() != render.Undraw {
+			t.Fatalf("expected entity to be undrawn")
+		}
+	}
+	if len(root.Children()) != 0 {
+		t.Fatalf("expected destroyed transform to have no children")
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
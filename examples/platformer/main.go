@@ -2,14 +2,12 @@ package main
 
 import (
 	"image/color"
-	"math"
 
 	"github.com/oakmound/oak/v4/alg/floatgeom"
 
 	"github.com/oakmound/oak/v4/collision"
 
 	"github.com/oakmound/oak/v4/event"
-	"github.com/oakmound/oak/v4/key"
 
 	oak "github.com/oakmound/oak/v4"
 	"github.com/oakmound/oak/v4/entities"
@@ -17,9 +15,10 @@ import (
 )
 
 const (
-	// The only collision label we need for this demo is 'ground',
-	// indicating something we shouldn't be able to fall or walk through
+	// Ground is something we shouldn't be able to fall or walk through
 	Ground collision.Label = 1
+	// Ledge is a platform we can jump up through and stand on
+	Ledge collision.Label = 2
 )
 
 func main() {
@@ -28,89 +27,21 @@ func main() {
 		char := entities.New(ctx,
 			entities.WithRect(floatgeom.NewRect2WH(100, 100, 16, 32)),
 			entities.WithColor(color.RGBA{255, 0, 0, 255}),
-			entities.WithSpeed(floatgeom.Point2{3, 7}),
 		)
 
-		const fallSpeed = .2
+		// The platformer controller handles gravity, jumping, and stopping at
+		// walls. Move with A and D, jump with Space, and drop through ledges with S.
+		ctrl := entities.NewPlatformer(char)
+		ctrl.SolidLabels = []collision.Label{Ground}
+		ctrl.OneWayLabels = []collision.Label{Ledge}
+		ctrl.Bind()
 
 		event.Bind(ctx, event.Enter, char, func(c *entities.Entity, ev event.EnterPayload) event.Response {
-
-			// Move left and right with A and D
-			if oak.IsDown(key.A) {
-				char.Delta[0] = -char.Speed.X()
-			} else if oak.IsDown(key.D) {
-				char.Delta[0] = char.Speed.X()
-			} else {
-				char.Delta[0] = (0)
+			// Restart when below ground
+			if char.Y() > 500 {
+				ctrl.Velocity = floatgeom.Point2{}
+				char.SetPos(floatgeom.Point2{100, 100})
 			}
-			oldX, oldY := char.X(), char.Y()
-			char.ShiftDelta()
-			aboveGround := false
-
-			hit := collision.HitLabel(char.Space, Ground)
-
-			// If we've moved in y value this frame and in the last frame,
-			// we were below what we're trying to hit, we are still falling
-			if hit != nil && !(oldY != char.Y() && oldY+char.H() > hit.Y()) {
-				// Correct our y if we started falling into the ground
-				char.SetY(hit.Y() - char.H())
-				// Stop falling
-				char.Delta[1] = 0
-				// Jump with Space when on the ground
-				if oak.IsDown(key.Spacebar) {
-					char.Delta[1] -= char.Speed.Y()
-				}
-				aboveGround = true
-			} else {
-				//Restart when is below ground
-				if char.Y() > 500 {
-					char.Delta[1] = 0
-					char.SetY(100)
-					char.SetX(100)
-
-				}
-
-				// Fall if there's no ground
-				char.Delta[1] += fallSpeed
-			}
-
-			if hit != nil {
-				// If we walked into a piece of ground, move back
-				xover, yover := char.Space.Overlap(hit)
-				// We, perhaps unintuitively, need to check the Y overlap, not
-				// the x overlap
-				// if the y overlap exceeds a superficial value, that suggests
-				// we're in a state like
-				//
-				// G = Ground, C = Character
-				//
-				// GG C
-				// GG C
-				//
-				// moving to the left
-				if math.Abs(yover) > 1 {
-					// We add a buffer so this doesn't retrigger immediately
-					xbump := 1.0
-					if xover > 0 {
-						xbump = -1
-					}
-					char.SetX(oldX + xbump)
-					if char.Delta.Y() < 0 {
-						char.Delta[1] = 0
-					}
-				}
-
-				// If we're below what we hit and we have significant xoverlap, by contrast,
-				// then we're about to jump from below into the ground, and we
-				// should stop the character.
-				if !aboveGround && math.Abs(xover) > 1 {
-					// We add a buffer so this doesn't retrigger immediately
-					char.SetY(oldY + 1)
-					char.Delta[1] = fallSpeed
-				}
-
-			}
-
 			return 0
 		})
 
@@ -128,6 +59,12 @@ func main() {
 			)
 		}
 
+		entities.New(ctx,
+			entities.WithRect(floatgeom.NewRect2WH(180, 330, 80, 4)),
+			entities.WithColor(color.RGBA{0, 200, 255, 255}),
+			entities.WithLabel(Ledge),
+		)
+
 	}})
 	oak.Init("platformer")
 }
@@ -16,7 +16,6 @@ import (
 	"github.com/oakmound/oak/v4/dlog"
 	"github.com/oakmound/oak/v4/entities"
 	"github.com/oakmound/oak/v4/event"
-	"github.com/oakmound/oak/v4/key"
 	"github.com/oakmound/oak/v4/mouse"
 	"github.com/oakmound/oak/v4/render"
 	"github.com/oakmound/oak/v4/scene"
@@ -24,6 +23,7 @@ import (
 
 const (
 	Enemy collision.Label = 1
+	Wall  collision.Label = 2
 )
 
 var (
@@ -66,7 +66,6 @@ func main() {
 		char := entities.New(ctx,
 			entities.WithRect(floatgeom.NewRect2WH(100, 100, 32, 32)),
 			entities.WithRenderable(playerR),
-			entities.WithSpeed(floatgeom.Point2{3, 3}),
 			entities.WithDrawLayers([]int{1, 2}),
 		)
 
@@ -75,38 +74,27 @@ func main() {
 
 		screenCenter := ctx.Window.Bounds().DivConst(2)
 
+		// Move with WASD
+		ctrl := entities.NewTopDownController(char)
+		ctrl.MaxSpeed = 180
+		ctrl.SolidLabels = []collision.Label{Wall}
+		// Walls around the field keep the player in bounds
+		for _, r := range []floatgeom.Rect2{
+			floatgeom.NewRect2(-10, -10, fieldWidth+10, 0),
+			floatgeom.NewRect2(-10, fieldHeight, fieldWidth+10, fieldHeight+10),
+			floatgeom.NewRect2(-10, 0, 0, fieldHeight),
+			floatgeom.NewRect2(fieldWidth, 0, fieldWidth+10, fieldHeight),
+		} {
+			ctx.CollisionTree.Add(collision.NewLabeledSpace(r.Min.X(), r.Min.Y(), r.W(), r.H(), Wall))
+		}
+
 		event.Bind(ctx, event.Enter, char, func(char *entities.Entity, ev event.EnterPayload) event.Response {
-			if oak.IsDown(key.W) {
-				char.Delta[1] += (-char.Speed.Y() * ev.TickPercent)
-			}
-			if oak.IsDown(key.A) {
-				char.Delta[0] += (-char.Speed.X() * ev.TickPercent)
-			}
-			if oak.IsDown(key.S) {
-				char.Delta[1] += (char.Speed.Y() * ev.TickPercent)
-			}
-			if oak.IsDown(key.D) {
-				char.Delta[0] += (char.Speed.X() * ev.TickPercent)
-			}
 			ctx.Window.(*oak.Window).DoBetweenDraws(func() {
-				char.ShiftDelta()
+				ctrl.Update(ev.SinceLastFrame)
 				oak.SetViewport(
 					intgeom.Point2{int(char.X()), int(char.Y())}.Sub(screenCenter),
 				)
-				char.Delta = floatgeom.Point2{}
 			})
-			// Don't go out of bounds
-			if char.X() < 0 {
-				char.SetX(0)
-			} else if char.X() > fieldWidth-char.W() {
-				char.SetX(fieldWidth - char.W())
-			}
-			if char.Y() < 0 {
-				char.SetY(0)
-			} else if char.Y() > fieldHeight-char.H() {
-				char.SetY(fieldHeight - char.H())
-			}
-
 			hit := char.HitLabel(Enemy)
 			if hit != nil {
 				ctx.Window.NextScene()
@@ -114,11 +102,11 @@ func main() {
 
 			// update animation
 			swtch := char.Renderable.(*render.Switch)
-			if char.Delta.X() > 0 {
+			if ctrl.Velocity.X() > 0 {
 				if swtch.Get() == "left" {
 					swtch.Set("right")
 				}
-			} else if char.Delta.X() < 0 {
+			} else if ctrl.Velocity.X() < 0 {
 				if swtch.Get() == "right" {
 					swtch.Set("left")
 				}
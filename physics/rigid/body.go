@@ -0,0 +1,171 @@
+package rigid
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// A BodyType determines how a Body is moved by a World.
+type BodyType int
+
+// BodyTypes
+const (
+	// Static bodies never move and have infinite mass.
+	Static BodyType = iota
+	// Kinematic bodies move by their velocity but are not affected by
+	// forces or collisions, and have infinite mass.
+	Kinematic
+	// Dynamic bodies are moved by forces, gravity, and collisions.
+	Dynamic
+)
+
+// A Body is a rigid body simulated by a World. Its shape and position are those
+// of its collision Space.
+type Body struct {
+	Space *collision.Space
+	Type  BodyType
+
+	Velocity floatgeom.Point2
+	// Restitution is how bouncy this body is, from 0 (no bounce) to 1 (perfectly
+	// elastic). The lesser restitution of two colliding bodies is used.
+	Restitution float64
+	// Friction is this body's friction coefficient. The geometric mean of the
+	// friction of two colliding bodies is used.
+	Friction float64
+	// GravityScale multiplies the world's gravity for this body.
+	GravityScale float64
+	// LinearDamping reduces this body's velocity over time, proportional to
+	// its velocity.
+	LinearDamping float64
+
+	// Angle is this body's rotation in radians. Collision spaces are axis aligned,
+	// so a body's angle does not rotate its space or affect its collisions; it
+	// positions the anchors of joints on the body, and can be used to rotate the
+	// body's renderable.
+	Angle float64
+	// AngularVelocity is how quickly Angle changes, in radians per second.
+	AngularVelocity float64
+	// FixedRotation bodies are never rotated by joints.
+	FixedRotation bool
+
+	mass, invMass       float64
+	inertia, invInertia float64
+	force               floatgeom.Point2
+	sleeping            bool
+	restingTime         float64
+}
+
+// NewBody creates a body of the given type around a space. Dynamic bodies
+// are given a mass of 1.
+func NewBody(s *collision.Space, typ BodyType) *Body {
+	b := &Body{
+		Space:        s,
+		Type:         typ,
+		Friction:     .2,
+		GravityScale: 1,
+	}
+	if typ == Dynamic {
+		b.setMass(1)
+	}
+	return b
+}
+
+// setMass sets the mass of a body and its moment of inertia, treating the body
+// as a uniform rectangle the size of its space.
+func (b *Body) setMass(mass float64) {
+	b.mass = mass
+	b.invMass = 1 / mass
+	w, h := b.Space.W(), b.Space.H()
+	b.inertia = mass * (w*w + h*h) / 12
+	b.invInertia = 0
+	if b.inertia > 0 {
+		b.invInertia = 1 / b.inertia
+	}
+}
+
+// SetMass sets the mass of a body. Mass must be positive, and only dynamic
+// bodies can have their mass set.
+func (b *Body) SetMass(mass float64) error {
+	if mass <= 0 {
+		return oakerr.InvalidInput{InputName: "mass"}
+	}
+	if b.Type != Dynamic {
+		return oakerr.InvalidInput{InputName: "Type"}
+	}
+	b.setMass(mass)
+	return nil
+}
+
+// Mass returns the mass of this body. Static and kinematic bodies have zero mass,
+// representing infinite mass.
+func (b *Body) Mass() float64 {
+	return b.mass
+}
+
+// Inertia returns the moment of inertia of this body, which resists changes to its
+// angular velocity. Static and kinematic bodies have zero inertia, representing
+// infinite inertia.
+func (b *Body) Inertia() float64 {
+	return b.inertia
+}
+
+func (b *Body) invI() float64 {
+	if b.FixedRotation {
+		return 0
+	}
+	return b.invInertia
+}
+
+// Position returns the top left corner of this body's space.
+func (b *Body) Position() floatgeom.Point2 {
+	return floatgeom.Point2{b.Space.X(), b.Space.Y()}
+}
+
+// Center returns the center of this body's space.
+func (b *Body) Center() floatgeom.Point2 {
+	return b.Space.Location.ProjectZ().Center()
+}
+
+// ApplyForce adds a force to be applied to this body over the next step.
+func (b *Body) ApplyForce(f floatgeom.Point2) {
+	b.force = b.force.Add(f)
+	b.Wake()
+}
+
+// ApplyImpulse immediately changes this body's velocity by an impulse.
+func (b *Body) ApplyImpulse(j floatgeom.Point2) {
+	b.Velocity = b.Velocity.Add(j.MulConst(b.invMass))
+	b.Wake()
+}
+
+// Sleeping returns whether this body is asleep. Sleeping bodies are not moved
+// until they are woken, either explicitly or by an awake body colliding with them.
+func (b *Body) Sleeping() bool {
+	return b.sleeping
+}
+
+// Wake wakes this body if it is sleeping.
+func (b *Body) Wake() {
+	b.sleeping = false
+	b.restingTime = 0
+}
+
+func (b *Body) sleep() {
+	b.sleeping = true
+	b.Velocity = floatgeom.Point2{}
+	b.AngularVelocity = 0
+	b.force = floatgeom.Point2{}
+}
+
+func (b *Body) moves() bool {
+	return b.Type != Static && !b.sleeping
+}
+
+// speed returns how fast the fastest moving point of this body is moving.
+func (b *Body) speed() float64 {
+	radius := math.Hypot(b.Space.W(), b.Space.H()) / 2
+	return b.Velocity.Magnitude() + math.Abs(b.AngularVelocity)*radius
+}
@@ -0,0 +1,2 @@
+// Package rigid provides a rigid body physics world built on collision spaces.
+package rigid
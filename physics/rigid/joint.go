@@ -0,0 +1,567 @@
+package rigid
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// A Joint constrains the relative motion of two bodies. Joints are solved
+// iteratively alongside contacts by a World.
+type Joint interface {
+	// Bodies returns the bodies this joint connects. The second body may be nil
+	// if the joint is anchored to a fixed point in the world.
+	Bodies() (a, b *Body)
+	// Anchors returns the current world positions of the joint's anchors on each body.
+	Anchors() (a, b floatgeom.Point2)
+	// PreSolve is called once per step, before velocities are solved.
+	PreSolve(dt float64)
+	// SolveVelocity is called World.Iterations times per step and should adjust
+	// the velocities of the joint's bodies towards satisfying the joint.
+	SolveVelocity(dt float64)
+	// SolvePosition is called World.Iterations times per step, after bodies have
+	// moved, and should correct any error in the positions of the joint's bodies by
+	// calling shift.
+	SolvePosition(shift func(*Body, floatgeom.Point2))
+}
+
+// jointCorrection is the fraction of a joint's position error corrected by each
+// call to SolvePosition.
+const jointCorrection = .8
+
+// An anchor is a point fixed relative to a body's center and angle, or a fixed
+// point in the world if it has no body.
+type anchor struct {
+	body  *Body
+	local floatgeom.Point2
+}
+
+func newAnchor(b *Body, world floatgeom.Point2) anchor {
+	if b == nil {
+		return anchor{local: world}
+	}
+	return anchor{body: b, local: world.Sub(b.Center()).RotateRadians(-b.Angle)}
+}
+
+// offset returns the current offset of the anchor from its body's center.
+func (an anchor) offset() floatgeom.Point2 {
+	if an.body == nil {
+		return floatgeom.Point2{}
+	}
+	return an.local.RotateRadians(an.body.Angle)
+}
+
+func (an anchor) world() floatgeom.Point2 {
+	if an.body == nil {
+		return an.local
+	}
+	return an.body.Center().Add(an.offset())
+}
+
+func (an anchor) angle() float64 {
+	if an.body == nil {
+		return 0
+	}
+	return an.body.Angle
+}
+
+func (an anchor) invMass() float64 {
+	if an.body == nil {
+		return 0
+	}
+	return an.body.invMass
+}
+
+func (an anchor) invInertia() float64 {
+	if an.body == nil {
+		return 0
+	}
+	return an.body.invI()
+}
+
+// invMassAlong returns the inverse of the mass the anchor has when pushed along n,
+// which is lowered by the body rotating as well as moving.
+func (an anchor) invMassAlong(n floatgeom.Point2) float64 {
+	rn := an.offset().Cross(n)
+	return an.invMass() + an.invInertia()*rn*rn
+}
+
+func (an anchor) velocity() floatgeom.Point2 {
+	if an.body == nil {
+		return floatgeom.Point2{}
+	}
+	r := an.offset()
+	return an.body.Velocity.Add(floatgeom.Point2{-r.Y(), r.X()}.MulConst(an.body.AngularVelocity))
+}
+
+func (an anchor) angularVelocity() float64 {
+	if an.body == nil {
+		return 0
+	}
+	return an.body.AngularVelocity
+}
+
+func (an anchor) applyImpulse(j floatgeom.Point2) {
+	if an.body == nil {
+		return
+	}
+	an.body.AngularVelocity += an.invInertia() * an.offset().Cross(j)
+	an.body.Velocity = an.body.Velocity.Add(j.MulConst(an.body.invMass))
+}
+
+func (an anchor) applyAngularImpulse(l float64) {
+	if an.body == nil {
+		return
+	}
+	an.body.AngularVelocity += an.invInertia() * l
+}
+
+// move moves and rotates the anchor's body as an impulse of j at the anchor would,
+// for correcting position error.
+func (an anchor) move(j floatgeom.Point2, shift func(*Body, floatgeom.Point2)) {
+	if an.body == nil {
+		return
+	}
+	an.body.Angle += an.invInertia() * an.offset().Cross(j)
+	if an.body.invMass != 0 {
+		shift(an.body, j.MulConst(an.body.invMass))
+	}
+}
+
+type jointBase struct {
+	a, b anchor
+}
+
+// Bodies returns the bodies this joint connects.
+func (jb *jointBase) Bodies() (*Body, *Body) {
+	return jb.a.body, jb.b.body
+}
+
+// Anchors returns the world positions of this joint's anchors.
+func (jb *jointBase) Anchors() (floatgeom.Point2, floatgeom.Point2) {
+	return jb.a.world(), jb.b.world()
+}
+
+// PreSolve does nothing for joints which only act on velocities and positions.
+func (jb *jointBase) PreSolve(float64) {}
+
+// solveAxis applies an impulse along n to remove the relative velocity of the
+// anchors along n. c is the position error along n. If sign is zero, c should be
+// kept at zero. If sign is positive, c should be kept at or above zero, and if
+// negative, c should be kept at or below zero; in both cases the anchors may
+// freely move towards their limit as long as they will not pass it this step.
+func (jb *jointBase) solveAxis(n floatgeom.Point2, c, dt float64, sign int) {
+	invMassSum := jb.a.invMassAlong(n) + jb.b.invMassAlong(n)
+	if invMassSum == 0 {
+		return
+	}
+	vrel := jb.b.velocity().Sub(jb.a.velocity()).Dot(n)
+	lambda := -(vrel + allowedSpeed(c, dt, sign)) / invMassSum
+	if (sign > 0 && lambda < 0) || (sign < 0 && lambda > 0) {
+		return
+	}
+	impulse := n.MulConst(lambda)
+	jb.a.applyImpulse(impulse.MulConst(-1))
+	jb.b.applyImpulse(impulse)
+}
+
+// correctAxis moves the anchors along n to reduce the position error c, with
+// sign as in solveAxis.
+func (jb *jointBase) correctAxis(n floatgeom.Point2, c float64, sign int, shift func(*Body, floatgeom.Point2)) {
+	if (sign > 0 && c >= 0) || (sign < 0 && c <= 0) {
+		return
+	}
+	invMassSum := jb.a.invMassAlong(n) + jb.b.invMassAlong(n)
+	if invMassSum == 0 {
+		return
+	}
+	correction := n.MulConst(-c * jointCorrection / invMassSum)
+	jb.a.move(correction.MulConst(-1), shift)
+	jb.b.move(correction, shift)
+}
+
+// relativeAngle returns how far the second body is rotated from the first.
+func (jb *jointBase) relativeAngle() float64 {
+	return jb.b.angle() - jb.a.angle()
+}
+
+// solveAngle applies an angular impulse to remove the relative angular velocity
+// of the bodies. c is the error in the relative angle of the bodies, and sign is
+// as in solveAxis.
+func (jb *jointBase) solveAngle(c, dt float64, sign int) {
+	invInertiaSum := jb.a.invInertia() + jb.b.invInertia()
+	if invInertiaSum == 0 {
+		return
+	}
+	wrel := jb.b.angularVelocity() - jb.a.angularVelocity()
+	lambda := -(wrel + allowedSpeed(c, dt, sign)) / invInertiaSum
+	if (sign > 0 && lambda < 0) || (sign < 0 && lambda > 0) {
+		return
+	}
+	jb.a.applyAngularImpulse(-lambda)
+	jb.b.applyAngularImpulse(lambda)
+}
+
+// correctAngle rotates the bodies to reduce the angle error c, with sign as in
+// solveAxis.
+func (jb *jointBase) correctAngle(c float64, sign int) {
+	if (sign > 0 && c >= 0) || (sign < 0 && c <= 0) {
+		return
+	}
+	invA, invB := jb.a.invInertia(), jb.b.invInertia()
+	if invA+invB == 0 {
+		return
+	}
+	correction := -c * jointCorrection / (invA + invB)
+	if invA != 0 {
+		jb.a.body.Angle -= correction * invA
+	}
+	if invB != 0 {
+		jb.b.body.Angle += correction * invB
+	}
+}
+
+// allowedSpeed returns how fast a limited constraint's error c may move towards its
+// limit this step without passing it.
+func allowedSpeed(c, dt float64, sign int) float64 {
+	if sign > 0 {
+		return math.Max(c, 0) / dt
+	} else if sign < 0 {
+		return math.Min(c, 0) / dt
+	}
+	return 0
+}
+
+func (jb *jointBase) separation() (n floatgeom.Point2, length float64) {
+	pa, pb := jb.Anchors()
+	d := pb.Sub(pa)
+	length = d.Magnitude()
+	if length == 0 {
+		return floatgeom.Point2{}, 0
+	}
+	return d.DivConst(length), length
+}
+
+// A DistanceJoint keeps its anchors at a fixed distance from each other, like
+// a rigid rod.
+type DistanceJoint struct {
+	jointBase
+	Length float64
+}
+
+// NewDistanceJoint creates a distance joint between two world-space anchor points
+// on a and b, keeping them at their current distance. b may be nil to anchor a to
+// a point in the world.
+func NewDistanceJoint(a, b *Body, anchorA, anchorB floatgeom.Point2) *DistanceJoint {
+	return &DistanceJoint{
+		jointBase: jointBase{newAnchor(a, anchorA), newAnchor(b, anchorB)},
+		Length:    anchorA.Distance(anchorB),
+	}
+}
+
+// SolveVelocity pushes or pulls the anchors towards being Length apart.
+func (dj *DistanceJoint) SolveVelocity(dt float64) {
+	n, l := dj.separation()
+	if l == 0 {
+		return
+	}
+	dj.solveAxis(n, l-dj.Length, dt, 0)
+}
+
+// SolvePosition moves the anchors towards being Length apart.
+func (dj *DistanceJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	n, l := dj.separation()
+	if l == 0 {
+		return
+	}
+	dj.correctAxis(n, l-dj.Length, 0, shift)
+}
+
+// A RopeJoint prevents its anchors from moving further than MaxLength apart, but
+// allows them to move closer together.
+type RopeJoint struct {
+	jointBase
+	MaxLength float64
+}
+
+// NewRopeJoint creates a rope joint between two world-space anchor points on a
+// and b. b may be nil to hang a from a point in the world.
+func NewRopeJoint(a, b *Body, anchorA, anchorB floatgeom.Point2, maxLength float64) *RopeJoint {
+	return &RopeJoint{
+		jointBase: jointBase{newAnchor(a, anchorA), newAnchor(b, anchorB)},
+		MaxLength: maxLength,
+	}
+}
+
+// SolveVelocity stops the anchors from moving apart if the rope is taut.
+func (rj *RopeJoint) SolveVelocity(dt float64) {
+	n, l := rj.separation()
+	if l == 0 {
+		return
+	}
+	rj.solveAxis(n, l-rj.MaxLength, dt, -1)
+}
+
+// SolvePosition pulls the anchors together if they are further than MaxLength apart.
+func (rj *RopeJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	n, l := rj.separation()
+	if l == 0 {
+		return
+	}
+	rj.correctAxis(n, l-rj.MaxLength, -1, shift)
+}
+
+type pinJoint struct {
+	jointBase
+}
+
+// SolveVelocity removes the relative velocity of the anchors.
+func (pj *pinJoint) SolveVelocity(dt float64) {
+	pj.solveAxis(floatgeom.Point2{1, 0}, 0, dt, 0)
+	pj.solveAxis(floatgeom.Point2{0, 1}, 0, dt, 0)
+}
+
+// SolvePosition moves the anchors towards each other.
+func (pj *pinJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	pa, pb := pj.Anchors()
+	pj.correctAxis(floatgeom.Point2{1, 0}, pb.X()-pa.X(), 0, shift)
+	pa, pb = pj.Anchors()
+	pj.correctAxis(floatgeom.Point2{0, 1}, pb.Y()-pa.Y(), 0, shift)
+}
+
+// A RevoluteJoint pins two bodies together at a shared anchor point, around which
+// they may rotate relative to each other. The angle between the bodies may be
+// limited, and may be driven by a motor.
+type RevoluteJoint struct {
+	pinJoint
+	referenceAngle float64
+	// If Limited, the joint's Angle is kept between Lower and Upper radians.
+	Limited      bool
+	Lower, Upper float64
+	// If MotorEnabled, the joint drives its Speed towards MotorSpeed radians per
+	// second, applying no more than MaxMotorTorque to do so.
+	MotorEnabled   bool
+	MotorSpeed     float64
+	MaxMotorTorque float64
+
+	motorImpulse float64
+}
+
+// NewRevoluteJoint creates a revolute joint between a and b around a world-space
+// anchor point. Either body may be nil to pin the other to the world.
+func NewRevoluteJoint(a, b *Body, anchor floatgeom.Point2) *RevoluteJoint {
+	rj := &RevoluteJoint{pinJoint: pinJoint{jointBase{newAnchor(a, anchor), newAnchor(b, anchor)}}}
+	rj.referenceAngle = rj.relativeAngle()
+	return rj
+}
+
+// Angle returns how far the second body has rotated relative to the first since
+// the joint was created, in radians.
+func (rj *RevoluteJoint) Angle() float64 {
+	return rj.relativeAngle() - rj.referenceAngle
+}
+
+// Speed returns how quickly the joint's Angle is changing, in radians per second.
+func (rj *RevoluteJoint) Speed() float64 {
+	return rj.b.angularVelocity() - rj.a.angularVelocity()
+}
+
+// PreSolve resets the torque the motor has applied this step.
+func (rj *RevoluteJoint) PreSolve(float64) {
+	rj.motorImpulse = 0
+}
+
+// SolveVelocity drives the motor, enforces limits, and removes the relative
+// velocity of the anchors.
+func (rj *RevoluteJoint) SolveVelocity(dt float64) {
+	if rj.MotorEnabled {
+		rj.solveMotor(dt)
+	}
+	rj.pinJoint.SolveVelocity(dt)
+	if rj.Limited {
+		angle := rj.Angle()
+		rj.solveAngle(angle-rj.Lower, dt, 1)
+		rj.solveAngle(angle-rj.Upper, dt, -1)
+	}
+}
+
+func (rj *RevoluteJoint) solveMotor(dt float64) {
+	invInertiaSum := rj.a.invInertia() + rj.b.invInertia()
+	if invInertiaSum == 0 {
+		return
+	}
+	lambda := -(rj.Speed() - rj.MotorSpeed) / invInertiaSum
+	maxImpulse := rj.MaxMotorTorque * dt
+	prev := rj.motorImpulse
+	rj.motorImpulse = math.Max(-maxImpulse, math.Min(maxImpulse, prev+lambda))
+	lambda = rj.motorImpulse - prev
+	rj.a.applyAngularImpulse(-lambda)
+	rj.b.applyAngularImpulse(lambda)
+}
+
+// SolvePosition moves the anchors towards each other and rotates the bodies back
+// within limits.
+func (rj *RevoluteJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	rj.pinJoint.SolvePosition(shift)
+	if rj.Limited {
+		angle := rj.Angle()
+		rj.correctAngle(angle-rj.Lower, 1)
+		rj.correctAngle(angle-rj.Upper, -1)
+	}
+}
+
+// A WeldJoint fixes two bodies at their current offset and angle from each other.
+type WeldJoint struct {
+	pinJoint
+	referenceAngle float64
+}
+
+// NewWeldJoint creates a weld joint between a and b at their current positions.
+func NewWeldJoint(a, b *Body) *WeldJoint {
+	mid := a.Center().Add(b.Center()).DivConst(2)
+	wj := &WeldJoint{pinJoint: pinJoint{jointBase{newAnchor(a, mid), newAnchor(b, mid)}}}
+	wj.referenceAngle = wj.relativeAngle()
+	return wj
+}
+
+// SolveVelocity removes the relative velocity and angular velocity of the bodies.
+func (wj *WeldJoint) SolveVelocity(dt float64) {
+	wj.solveAngle(wj.relativeAngle()-wj.referenceAngle, dt, 0)
+	wj.pinJoint.SolveVelocity(dt)
+}
+
+// SolvePosition moves and rotates the bodies back to their welded offset.
+func (wj *WeldJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	wj.correctAngle(wj.relativeAngle()-wj.referenceAngle, 0)
+	wj.pinJoint.SolvePosition(shift)
+}
+
+// A PrismaticJoint allows its bodies to slide relative to each other only along
+// an axis, optionally between limits. The bodies may not rotate relative to each
+// other.
+type PrismaticJoint struct {
+	jointBase
+	referenceAngle float64
+	// Axis is the unit direction bodies may slide along.
+	Axis floatgeom.Point2
+	// If Limited, the second anchor may only be between Lower and Upper units
+	// along Axis from the first anchor.
+	Limited      bool
+	Lower, Upper float64
+}
+
+// NewPrismaticJoint creates a prismatic joint between a and b sharing a world-space
+// anchor point, sliding along axis. b may be nil to slide a along a fixed track.
+func NewPrismaticJoint(a, b *Body, anchor, axis floatgeom.Point2) *PrismaticJoint {
+	pj := &PrismaticJoint{
+		jointBase: jointBase{newAnchor(a, anchor), newAnchor(b, anchor)},
+		Axis:      axis.Normalize(),
+	}
+	pj.referenceAngle = pj.relativeAngle()
+	return pj
+}
+
+// Translation returns how far the second anchor is from the first along Axis.
+func (pj *PrismaticJoint) Translation() float64 {
+	pa, pb := pj.Anchors()
+	return pb.Sub(pa).Dot(pj.Axis)
+}
+
+func (pj *PrismaticJoint) perp() floatgeom.Point2 {
+	return floatgeom.Point2{-pj.Axis.Y(), pj.Axis.X()}
+}
+
+// SolveVelocity removes relative rotation and motion off of Axis, and enforces limits.
+func (pj *PrismaticJoint) SolveVelocity(dt float64) {
+	pj.solveAngle(pj.relativeAngle()-pj.referenceAngle, dt, 0)
+	pj.solveAxis(pj.perp(), 0, dt, 0)
+	if !pj.Limited {
+		return
+	}
+	t := pj.Translation()
+	pj.solveAxis(pj.Axis, t-pj.Lower, dt, 1)
+	pj.solveAxis(pj.Axis, t-pj.Upper, dt, -1)
+}
+
+// SolvePosition rotates the bodies back to their original relative angle, and
+// moves the anchors back onto Axis and within limits.
+func (pj *PrismaticJoint) SolvePosition(shift func(*Body, floatgeom.Point2)) {
+	pj.correctAngle(pj.relativeAngle()-pj.referenceAngle, 0)
+	pa, pb := pj.Anchors()
+	perp := pj.perp()
+	pj.correctAxis(perp, pb.Sub(pa).Dot(perp), 0, shift)
+	if !pj.Limited {
+		return
+	}
+	t := pj.Translation()
+	pj.correctAxis(pj.Axis, t-pj.Lower, 1, shift)
+	pj.correctAxis(pj.Axis, t-pj.Upper, -1, shift)
+}
+
+// A SpringJoint is a damped spring pulling its anchors towards RestLength apart.
+// Unlike other joints it is soft: it applies a force rather than strictly
+// constraining its bodies.
+type SpringJoint struct {
+	jointBase
+	RestLength float64
+	// Stiffness is the spring constant, in force per unit of stretch.
+	Stiffness float64
+	// Damping resists the anchors' relative velocity along the spring.
+	Damping float64
+}
+
+// NewSpringJoint creates a damped spring between two world-space anchor points on
+// a and b, resting at their current distance. b may be nil to attach a to a point
+// in the world.
+func NewSpringJoint(a, b *Body, anchorA, anchorB floatgeom.Point2, stiffness, damping float64) *SpringJoint {
+	return &SpringJoint{
+		jointBase:  jointBase{newAnchor(a, anchorA), newAnchor(b, anchorB)},
+		RestLength: anchorA.Distance(anchorB),
+		Stiffness:  stiffness,
+		Damping:    damping,
+	}
+}
+
+// PreSolve applies the spring's force for this step.
+func (sj *SpringJoint) PreSolve(dt float64) {
+	n, l := sj.separation()
+	if l == 0 {
+		return
+	}
+	vrel := sj.b.velocity().Sub(sj.a.velocity()).Dot(n)
+	f := -sj.Stiffness*(l-sj.RestLength) - sj.Damping*vrel
+	impulse := n.MulConst(f * dt)
+	sj.a.applyImpulse(impulse.MulConst(-1))
+	sj.b.applyImpulse(impulse)
+}
+
+// SolveVelocity does nothing; springs act only in PreSolve.
+func (sj *SpringJoint) SolveVelocity(float64) {}
+
+// SolvePosition does nothing; springs act only in PreSolve.
+func (sj *SpringJoint) SolvePosition(func(*Body, floatgeom.Point2)) {}
+
+// active returns whether a body is moving under the simulation.
+func active(b *Body) bool {
+	if b == nil || b.sleeping {
+		return false
+	}
+	switch b.Type {
+	case Dynamic:
+		return true
+	case Kinematic:
+		return b.Velocity != (floatgeom.Point2{}) || b.AngularVelocity != 0
+	}
+	return false
+}
+
+// wakeJoined wakes sleeping bodies joined to active bodies.
+func wakeJoined(j Joint) {
+	a, b := j.Bodies()
+	if active(a) && b != nil && b.sleeping {
+		b.Wake()
+	}
+	if active(b) && a != nil && a.sleeping {
+		a.Wake()
+	}
+}
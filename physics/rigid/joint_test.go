@@ -0,0 +1,211 @@
+package rigid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+)
+
+func TestDistanceJoint(t *testing.T) {
+	w := newTestWorld()
+	bob := NewBody(collision.NewUnassignedSpace(95, 0, 10, 10), Dynamic)
+	pivot := floatgeom.Point2{0, 5}
+	j := NewDistanceJoint(bob, nil, bob.Center(), pivot)
+	w.Add(bob)
+	w.AddJoint(j)
+	lowest := 0.0
+	for i := 0; i < 120; i++ {
+		w.Step()
+		if d := bob.Center().Distance(pivot); math.Abs(d-100) > 2 {
+			t.Fatalf("step %d: pendulum length drifted to %v", i, d)
+		}
+		lowest = math.Max(lowest, bob.Center().Y())
+	}
+	if lowest < 95 {
+		t.Fatalf("pendulum did not swing down, lowest point was %v", lowest)
+	}
+}
+
+func TestRopeJoint(t *testing.T) {
+	w := newTestWorld()
+	bob := NewBody(collision.NewUnassignedSpace(-5, 15, 10, 10), Dynamic)
+	pivot := floatgeom.Point2{0, 0}
+	w.Add(bob)
+	w.AddJoint(NewRopeJoint(bob, nil, bob.Center(), pivot, 50))
+	for i := 0; i < 10; i++ {
+		w.Step()
+	}
+	if d := bob.Center().Distance(pivot); d >= 50 {
+		t.Fatalf("slack rope should not constrain body yet, distance was %v", d)
+	}
+	for i := 0; i < 200; i++ {
+		w.Step()
+	}
+	if d := bob.Center().Distance(pivot); math.Abs(d-50) > 1 {
+		t.Fatalf("expected body to hang at rope length, distance was %v", d)
+	}
+}
+
+func TestRevoluteJoint(t *testing.T) {
+	w := newTestWorld()
+	bob := NewBody(collision.NewUnassignedSpace(15, 0, 10, 10), Dynamic)
+	pivot := floatgeom.Point2{0, 5}
+	rj := NewRevoluteJoint(nil, bob, pivot)
+	w.Add(bob)
+	w.AddJoint(rj)
+	for i := 0; i < 30; i++ {
+		w.Step()
+		_, pb := rj.Anchors()
+		if d := pb.Distance(pivot); d > 1 {
+			t.Fatalf("step %d: anchor drifted %v from pivot", i, d)
+		}
+		arm := bob.Center().Sub(pivot)
+		if math.Abs(arm.Magnitude()-20) > 1 {
+			t.Fatalf("step %d: arm length drifted to %v", i, arm.Magnitude())
+		}
+		if math.Abs(arm.ToRadians()-rj.Angle()) > .05 {
+			t.Fatalf("step %d: body angle %v did not follow arm angle %v", i, rj.Angle(), arm.ToRadians())
+		}
+	}
+	if rj.Angle() < math.Pi/4 {
+		t.Fatalf("body did not swing down, angle was %v", rj.Angle())
+	}
+}
+
+func TestRevoluteJointLimits(t *testing.T) {
+	w := newTestWorld()
+	bob := NewBody(collision.NewUnassignedSpace(15, 0, 10, 10), Dynamic)
+	rj := NewRevoluteJoint(nil, bob, floatgeom.Point2{0, 5})
+	rj.Limited = true
+	rj.Lower = -math.Pi / 4
+	rj.Upper = math.Pi / 4
+	w.Add(bob)
+	w.AddJoint(rj)
+	for i := 0; i < 120; i++ {
+		w.Step()
+		if a := rj.Angle(); a > rj.Upper+.05 {
+			t.Fatalf("step %d: angle %v passed upper limit", i, a)
+		}
+	}
+	if a := rj.Angle(); math.Abs(a-rj.Upper) > .05 {
+		t.Fatalf("expected body to rest at upper limit, angle was %v", a)
+	}
+}
+
+func TestRevoluteJointMotor(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{}
+	wheel := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Dynamic)
+	rj := NewRevoluteJoint(nil, wheel, wheel.Center())
+	rj.MotorEnabled = true
+	rj.MotorSpeed = 2
+	rj.MaxMotorTorque = 1000
+	w.Add(wheel)
+	w.AddJoint(rj)
+	for i := 0; i < 60; i++ {
+		w.Step()
+	}
+	if s := rj.Speed(); math.Abs(s-2) > .01 {
+		t.Fatalf("expected motor to reach its speed, speed was %v", s)
+	}
+	if a := rj.Angle(); math.Abs(a-2) > .1 {
+		t.Fatalf("expected wheel to turn 2 radians in a second, turned %v", a)
+	}
+	if c := wheel.Center(); c.Distance(floatgeom.Point2{5, 5}) > .01 {
+		t.Fatalf("wheel left its axle, center was %v", c)
+	}
+
+	rj.MotorSpeed = -2
+	rj.MaxMotorTorque = 1
+	w.Step()
+	// the motor may only change the wheel's speed by torque * dt / inertia per step
+	maxChange := rj.MaxMotorTorque * w.Timestep.Seconds() / wheel.Inertia()
+	if s := rj.Speed(); s < 2-maxChange-1e-9 {
+		t.Fatalf("motor exceeded its max torque, speed was %v", s)
+	}
+}
+
+func TestWeldJoint(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{}
+	a := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Dynamic)
+	b := NewBody(collision.NewUnassignedSpace(20, 0, 10, 10), Dynamic)
+	c := NewBody(collision.NewUnassignedSpace(40, 0, 10, 10), Dynamic)
+	w.Add(a, b, c)
+	w.AddJoint(NewWeldJoint(a, b), NewWeldJoint(b, c))
+	a.Velocity = floatgeom.Point2{0, 60}
+	a.AngularVelocity = 1
+	for i := 0; i < 60; i++ {
+		w.Step()
+	}
+	for _, pair := range [][2]*Body{{a, b}, {b, c}} {
+		want := floatgeom.Point2{20, 0}.RotateRadians(pair[0].Angle)
+		if d := pair[1].Center().Sub(pair[0].Center()); d.Distance(want) > 1 {
+			t.Fatalf("weld joint did not hold offset, was %v, expected %v", d, want)
+		}
+		if math.Abs(pair[1].Angle-pair[0].Angle) > .01 {
+			t.Fatalf("weld joint did not hold angle, was %v and %v", pair[0].Angle, pair[1].Angle)
+		}
+	}
+	if b.Angle == 0 {
+		t.Fatalf("welded bodies did not rotate together")
+	}
+	if b.Center().Y() < 10 {
+		t.Fatalf("welded bodies did not move together: %v", b.Center())
+	}
+}
+
+func TestPrismaticJoint(t *testing.T) {
+	w := newTestWorld()
+	slider := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Dynamic)
+	w.Add(slider)
+	pj := NewPrismaticJoint(nil, slider, slider.Center(), floatgeom.Point2{1, 0})
+	pj.Limited = true
+	pj.Lower = -10
+	pj.Upper = 30
+	w.AddJoint(pj)
+	slider.Velocity = floatgeom.Point2{120, 0}
+	for i := 0; i < 120; i++ {
+		w.Step()
+	}
+	if y := slider.Center().Y(); math.Abs(y-5) > 1 {
+		t.Fatalf("slider left its axis, y was %v", y)
+	}
+	if tr := pj.Translation(); math.Abs(tr-30) > 1 {
+		t.Fatalf("expected slider to stop at upper limit, translation was %v", tr)
+	}
+}
+
+func TestSpringJoint(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{0, 100}
+	bob := NewBody(collision.NewUnassignedSpace(-5, 45, 10, 10), Dynamic)
+	anchor := floatgeom.Point2{0, 0}
+	w.Add(bob)
+	w.AddJoint(NewSpringJoint(nil, bob, anchor, bob.Center(), 10, 4))
+	for i := 0; i < 900; i++ {
+		w.Step()
+	}
+	// the spring should stretch by mg/k
+	if d := bob.Center().Distance(anchor); math.Abs(d-60) > 1 {
+		t.Fatalf("expected spring to settle at length 60, was %v", d)
+	}
+}
+
+func TestWorldRemoveDropsJoints(t *testing.T) {
+	w := newTestWorld()
+	a := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Dynamic)
+	b := NewBody(collision.NewUnassignedSpace(20, 0, 10, 10), Dynamic)
+	w.Add(a, b)
+	w.AddJoint(NewWeldJoint(a, b), NewRopeJoint(b, nil, b.Center(), floatgeom.Point2{}, 40))
+	w.Remove(a)
+	if js := w.Joints(); len(js) != 1 {
+		t.Fatalf("expected one joint to remain, got %d", len(js))
+	}
+	w.RemoveJoint(w.Joints()...)
+	if js := w.Joints(); len(js) != 0 {
+		t.Fatalf("expected no joints to remain, got %d", len(js))
+	}
+}
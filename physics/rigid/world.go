@@ -0,0 +1,412 @@
+package rigid
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A World steps a set of rigid bodies through time with a fixed timestep,
+// resolving their collisions with impulses.
+type World struct {
+	Tree    *collision.Tree
+	Handler event.Handler
+
+	// Gravity is the acceleration applied to all dynamic bodies, in units per second squared.
+	Gravity floatgeom.Point2
+	// Timestep is the fixed duration of a single simulation step.
+	Timestep time.Duration
+	// MaxSteps limits how many steps a single call to Advance may run, to avoid
+	// falling further and further behind when steps are slow.
+	MaxSteps int
+	// Iterations is how many times contact impulses and joints are resolved per step.
+	// Higher values give more stable stacks at a higher cost.
+	Iterations int
+	// CorrectionPercent and CorrectionSlop control positional correction. Each
+	// step, overlap beyond the slop is reduced by this percent.
+	CorrectionPercent float64
+	CorrectionSlop    float64
+	// Dynamic bodies with no point moving faster than SleepVelocity for SleepTime
+	// seconds fall asleep.
+	// A SleepTime of zero disables sleeping.
+	SleepVelocity float64
+	SleepTime     float64
+
+	mu          sync.Mutex
+	bodies      []*Body
+	joints      []Joint
+	bySpace     map[*collision.Space]*Body
+	contacts    map[bodyPair]collision.Manifold
+	accumulated time.Duration
+}
+
+type bodyPair struct {
+	a, b *Body
+}
+
+type contact struct {
+	bodyPair
+	collision.Manifold
+}
+
+// NewWorld creates a world operating on the given collision tree and triggering
+// contact events on the given handler. If tree is nil, the DefaultTree will be used.
+// If handler is nil, the DefaultBus will be used.
+func NewWorld(tree *collision.Tree, handler event.Handler) *World {
+	if tree == nil {
+		tree = collision.DefaultTree
+	}
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	return &World{
+		Tree:              tree,
+		Handler:           handler,
+		Gravity:           floatgeom.Point2{0, 500},
+		Timestep:          time.Second / 60,
+		MaxSteps:          5,
+		Iterations:        8,
+		CorrectionPercent: .8,
+		CorrectionSlop:    .01,
+		SleepVelocity:     1,
+		SleepTime:         .5,
+		bySpace:           make(map[*collision.Space]*Body),
+		contacts:          make(map[bodyPair]collision.Manifold),
+	}
+}
+
+// Add adds bodies to the world, and their spaces to the world's tree.
+func (w *World) Add(bs ...*Body) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range bs {
+		if _, ok := w.bySpace[b.Space]; ok {
+			continue
+		}
+		w.bodies = append(w.bodies, b)
+		w.bySpace[b.Space] = b
+		w.Tree.Add(b.Space)
+	}
+}
+
+// Remove removes bodies from the world, and their spaces from the world's tree.
+// Joints attached to removed bodies are also removed.
+func (w *World) Remove(bs ...*Body) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range bs {
+		if _, ok := w.bySpace[b.Space]; !ok {
+			continue
+		}
+		delete(w.bySpace, b.Space)
+		for i, b2 := range w.bodies {
+			if b2 == b {
+				w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+				break
+			}
+		}
+		for p := range w.contacts {
+			if p.a == b || p.b == b {
+				delete(w.contacts, p)
+			}
+		}
+		joints := w.joints[:0]
+		for _, j := range w.joints {
+			ja, jb := j.Bodies()
+			if ja != b && jb != b {
+				joints = append(joints, j)
+			}
+		}
+		w.joints = joints
+		w.Tree.Remove(b.Space)
+	}
+}
+
+// AddJoint adds joints to the world. The bodies of a joint should also be
+// added to the world.
+func (w *World) AddJoint(js ...Joint) {
+	w.mu.Lock()
+	w.joints = append(w.joints, js...)
+	w.mu.Unlock()
+}
+
+// RemoveJoint removes joints from the world.
+func (w *World) RemoveJoint(js ...Joint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, j := range js {
+		for i, j2 := range w.joints {
+			if j2 == j {
+				w.joints = append(w.joints[:i], w.joints[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Joints returns the joints in this world.
+func (w *World) Joints() []Joint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Joint{}, w.joints...)
+}
+
+// Bodies returns the bodies in this world.
+func (w *World) Bodies() []*Body {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]*Body{}, w.bodies...)
+}
+
+// Advance accumulates elapsed time and runs as many fixed steps as fit within it,
+// up to MaxSteps.
+func (w *World) Advance(elapsed time.Duration) {
+	w.mu.Lock()
+	w.accumulated += elapsed
+	steps := 0
+	for w.accumulated >= w.Timestep && steps < w.MaxSteps {
+		w.accumulated -= w.Timestep
+		steps++
+	}
+	if steps == w.MaxSteps {
+		w.accumulated = 0
+	}
+	w.mu.Unlock()
+	for i := 0; i < steps; i++ {
+		w.Step()
+	}
+}
+
+// Bind causes this world to Advance by the time since the last frame at the start
+// of every frame, until the returned binding is unbound.
+func (w *World) Bind() event.Binding {
+	return event.GlobalBind(w.Handler, event.Enter, func(ev event.EnterPayload) event.Response {
+		w.Advance(ev.SinceLastFrame)
+		return 0
+	})
+}
+
+// Step runs a single fixed timestep of the simulation.
+func (w *World) Step() {
+	w.mu.Lock()
+	dt := w.Timestep.Seconds()
+
+	w.integrateVelocities(dt)
+	contacts := w.findContacts()
+	for _, j := range w.joints {
+		wakeJoined(j)
+		j.PreSolve(dt)
+	}
+	for i := 0; i < w.Iterations; i++ {
+		for _, c := range contacts {
+			w.resolveVelocity(c)
+		}
+		for _, j := range w.joints {
+			j.SolveVelocity(dt)
+		}
+	}
+	w.integratePositions(dt)
+	for _, c := range contacts {
+		w.correctPosition(c)
+	}
+	for i := 0; i < w.Iterations; i++ {
+		for _, j := range w.joints {
+			j.SolvePosition(w.shift)
+		}
+	}
+	w.updateSleep(dt)
+	events := w.diffContacts(contacts)
+	w.mu.Unlock()
+
+	for _, ev := range events {
+		if ev.c.Space.CID != event.Global {
+			event.TriggerForCallerOn(w.Handler, ev.c.Space.CID, ev.ev, ev.c)
+		}
+	}
+}
+
+func (w *World) integrateVelocities(dt float64) {
+	for _, b := range w.bodies {
+		if b.Type != Dynamic || b.sleeping {
+			continue
+		}
+		accel := w.Gravity.MulConst(b.GravityScale).Add(b.force.MulConst(b.invMass))
+		b.Velocity = b.Velocity.Add(accel.MulConst(dt))
+		if b.LinearDamping > 0 {
+			b.Velocity = b.Velocity.MulConst(1 / (1 + dt*b.LinearDamping))
+		}
+		b.force = floatgeom.Point2{}
+	}
+}
+
+func (w *World) integratePositions(dt float64) {
+	for _, b := range w.bodies {
+		if !b.moves() {
+			continue
+		}
+		if b.Velocity != (floatgeom.Point2{}) {
+			w.shift(b, b.Velocity.MulConst(dt))
+		}
+		b.Angle += b.AngularVelocity * dt
+	}
+}
+
+func (w *World) shift(b *Body, delta floatgeom.Point2) {
+	w.Tree.ShiftSpace(delta.X(), delta.Y(), b.Space)
+}
+
+func (w *World) findContacts() []contact {
+	var contacts []contact
+	seen := make(map[bodyPair]struct{})
+	for _, a := range w.bodies {
+		if a.Type == Static || a.sleeping {
+			continue
+		}
+		for _, s := range w.Tree.Hits(a.Space) {
+			b, ok := w.bySpace[s]
+			if !ok || (a.Type != Dynamic && b.Type != Dynamic) {
+				continue
+			}
+			p := bodyPair{a, b}
+			if _, ok := seen[bodyPair{b, a}]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			if b.sleeping {
+				b.Wake()
+			}
+			contacts = append(contacts, contact{
+				bodyPair: p,
+				Manifold: collision.NewManifold(a.Space, b.Space),
+			})
+		}
+	}
+	return contacts
+}
+
+// normal returns the unit normal pointing from a towards b.
+func (c contact) normal() floatgeom.Point2 {
+	return c.Normal.MulConst(-1)
+}
+
+func (w *World) resolveVelocity(c contact) {
+	a, b := c.a, c.b
+	invMassSum := a.invMass + b.invMass
+	if invMassSum == 0 {
+		return
+	}
+	n := c.normal()
+	rv := b.Velocity.Sub(a.Velocity)
+	velAlongNormal := rv.Dot(n)
+	if velAlongNormal > 0 {
+		return
+	}
+	e := math.Min(a.Restitution, b.Restitution)
+	j := -(1 + e) * velAlongNormal / invMassSum
+	impulse := n.MulConst(j)
+	a.Velocity = a.Velocity.Sub(impulse.MulConst(a.invMass))
+	b.Velocity = b.Velocity.Add(impulse.MulConst(b.invMass))
+
+	// Friction acts along the contact tangent, bounded by the normal impulse
+	rv = b.Velocity.Sub(a.Velocity)
+	tangent := rv.Sub(n.MulConst(rv.Dot(n)))
+	if tangent.Magnitude() < 1e-9 {
+		return
+	}
+	tangent = tangent.Normalize()
+	jt := -rv.Dot(tangent) / invMassSum
+	mu := math.Sqrt(a.Friction * b.Friction)
+	if math.Abs(jt) > j*mu {
+		jt = math.Copysign(j*mu, jt)
+	}
+	frictionImpulse := tangent.MulConst(jt)
+	a.Velocity = a.Velocity.Sub(frictionImpulse.MulConst(a.invMass))
+	b.Velocity = b.Velocity.Add(frictionImpulse.MulConst(b.invMass))
+}
+
+func (w *World) correctPosition(c contact) {
+	a, b := c.a, c.b
+	invMassSum := a.invMass + b.invMass
+	if invMassSum == 0 {
+		return
+	}
+	m := collision.NewManifold(a.Space, b.Space)
+	depth := m.Depth - w.CorrectionSlop
+	if depth <= 0 {
+		return
+	}
+	correction := m.Normal.MulConst(-1 * depth / invMassSum * w.CorrectionPercent)
+	if a.invMass != 0 {
+		w.shift(a, correction.MulConst(-a.invMass))
+	}
+	if b.invMass != 0 {
+		w.shift(b, correction.MulConst(b.invMass))
+	}
+}
+
+func (w *World) updateSleep(dt float64) {
+	if w.SleepTime <= 0 {
+		return
+	}
+	for _, b := range w.bodies {
+		if b.Type != Dynamic || b.sleeping {
+			continue
+		}
+		if b.speed() < w.SleepVelocity {
+			b.restingTime += dt
+			if b.restingTime >= w.SleepTime {
+				b.sleep()
+			}
+		} else {
+			b.restingTime = 0
+		}
+	}
+}
+
+type contactEvent struct {
+	ev event.EventID[collision.Contact]
+	c  collision.Contact
+}
+
+// diffContacts records the contacts found this step and returns contact events for
+// both bodies of every contact which began, continued, or ended. Contacts involving
+// sleeping bodies are kept alive until a body wakes.
+func (w *World) diffContacts(contacts []contact) []contactEvent {
+	var events []contactEvent
+	add := func(ev event.EventID[collision.Contact], p bodyPair, m collision.Manifold) {
+		events = append(events, contactEvent{ev, collision.Contact{Space: p.a.Space, Other: p.b.Space, Manifold: m}})
+		flipped := m
+		flipped.Normal = m.Normal.MulConst(-1)
+		events = append(events, contactEvent{ev, collision.Contact{Space: p.b.Space, Other: p.a.Space, Manifold: flipped}})
+	}
+	next := make(map[bodyPair]collision.Manifold, len(contacts))
+	for _, c := range contacts {
+		p := c.bodyPair
+		if _, ok := w.contacts[p]; ok {
+			add(collision.ContactStay, p, c.Manifold)
+		} else if _, ok := w.contacts[bodyPair{p.b, p.a}]; ok {
+			p = bodyPair{p.b, p.a}
+			c.Manifold.Normal = c.Manifold.Normal.MulConst(-1)
+			add(collision.ContactStay, p, c.Manifold)
+		} else {
+			add(collision.ContactEnter, p, c.Manifold)
+		}
+		next[p] = c.Manifold
+	}
+	for p, m := range w.contacts {
+		if _, ok := next[p]; ok {
+			continue
+		}
+		if (p.a.sleeping || p.a.Type != Dynamic) && (p.b.sleeping || p.b.Type != Dynamic) {
+			next[p] = m
+			continue
+		}
+		add(collision.ContactExit, p, m)
+	}
+	w.contacts = next
+	return events
+}
@@ -0,0 +1,141 @@
+package rigid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+)
+
+func newTestWorld() *World {
+	return NewWorld(collision.NewTree(), event.NewBus(event.NewCallerMap()))
+}
+
+func TestWorldRestsOnFloor(t *testing.T) {
+	w := newTestWorld()
+	floor := NewBody(collision.NewUnassignedSpace(0, 100, 200, 20), Static)
+	box := NewBody(collision.NewUnassignedSpace(50, 0, 10, 10), Dynamic)
+	w.Add(floor, box)
+	for i := 0; i < 300; i++ {
+		w.Step()
+	}
+	if bottom := box.Space.Location.Max.Y(); math.Abs(bottom-100) > 1 {
+		t.Fatalf("expected box to rest on floor at y=100, bottom was %v", bottom)
+	}
+	if floor.Position() != (floatgeom.Point2{0, 100}) {
+		t.Fatalf("static floor moved to %v", floor.Position())
+	}
+	if !box.Sleeping() {
+		t.Fatalf("resting box should have fallen asleep")
+	}
+	box.ApplyImpulse(floatgeom.Point2{0, -100})
+	if box.Sleeping() {
+		t.Fatalf("impulse should wake box")
+	}
+	w.Step()
+	if box.Space.Location.Max.Y() >= 100 {
+		t.Fatalf("box did not move up after impulse")
+	}
+}
+
+func TestWorldRestitution(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{}
+	wall := NewBody(collision.NewUnassignedSpace(100, 0, 10, 100), Static)
+	ball := NewBody(collision.NewUnassignedSpace(80, 40, 10, 10), Dynamic)
+	ball.Restitution = 1
+	wall.Restitution = 1
+	ball.Velocity = floatgeom.Point2{120, 0}
+	w.Add(wall, ball)
+	for i := 0; i < 30; i++ {
+		w.Step()
+	}
+	if ball.Velocity.X() > -119 {
+		t.Fatalf("expected ball to bounce back elastically, velocity was %v", ball.Velocity)
+	}
+}
+
+func TestWorldKinematicPushesDynamic(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{}
+	w.SleepTime = 0
+	pusher := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Kinematic)
+	pusher.Velocity = floatgeom.Point2{60, 0}
+	crate := NewBody(collision.NewUnassignedSpace(12, 0, 10, 10), Dynamic)
+	w.Add(pusher, crate)
+	for i := 0; i < 60; i++ {
+		w.Step()
+	}
+	if pusher.Velocity != (floatgeom.Point2{60, 0}) {
+		t.Fatalf("kinematic body velocity was changed by collision: %v", pusher.Velocity)
+	}
+	if crate.Position().X() < pusher.Space.Location.Max.X()-1 {
+		t.Fatalf("crate was not pushed ahead of kinematic body")
+	}
+}
+
+func TestWorldMassRatio(t *testing.T) {
+	w := newTestWorld()
+	w.Gravity = floatgeom.Point2{}
+	light := NewBody(collision.NewUnassignedSpace(0, 0, 10, 10), Dynamic)
+	heavy := NewBody(collision.NewUnassignedSpace(9, 0, 10, 10), Dynamic)
+	if err := heavy.SetMass(3); err != nil {
+		t.Fatalf("set mass failed: %v", err)
+	}
+	if err := heavy.SetMass(-1); err == nil {
+		t.Fatalf("negative mass should fail")
+	}
+	if err := NewBody(collision.NewUnassignedSpace(0, 0, 1, 1), Static).SetMass(1); err == nil {
+		t.Fatalf("setting mass of static body should fail")
+	}
+	light.Velocity = floatgeom.Point2{40, 0}
+	w.Add(light, heavy)
+	w.Step()
+	momentum := light.Velocity.X()*light.Mass() + heavy.Velocity.X()*heavy.Mass()
+	if math.Abs(momentum-40) > 1e-6 {
+		t.Fatalf("momentum was not conserved: %v", momentum)
+	}
+}
+
+type testEntity struct {
+	event.CallerID
+}
+
+func (te *testEntity) CID() event.CallerID {
+	return te.CallerID
+}
+
+func TestWorldEvents(t *testing.T) {
+	w := newTestWorld()
+	te := &testEntity{}
+	te.CallerID = w.Handler.GetCallerMap().Register(te)
+	entered := make(chan collision.Contact, 10)
+	<-event.Bind(w.Handler, collision.ContactEnter, te, func(_ *testEntity, c collision.Contact) event.Response {
+		entered <- c
+		return 0
+	}).Bound
+
+	floor := NewBody(collision.NewUnassignedSpace(0, 20, 100, 10), Static)
+	box := NewBody(collision.NewSpace(0, 9.5, 10, 10, te.CallerID), Dynamic)
+	w.Add(floor, box)
+	w.Add(box)
+	if len(w.Bodies()) != 2 {
+		t.Fatalf("adding a body twice should not duplicate it")
+	}
+	w.Advance(time.Second)
+	select {
+	case c := <-entered:
+		if c.Other != floor.Space || c.Normal != (floatgeom.Point2{0, -1}) {
+			t.Fatalf("unexpected contact %v", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for contact event")
+	}
+	w.Remove(box)
+	if len(w.Bodies()) != 1 || w.Tree.Size() != 1 {
+		t.Fatalf("remove did not remove body from world and tree")
+	}
+}
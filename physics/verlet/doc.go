@@ -0,0 +1,3 @@
+// Package verlet provides a lightweight Verlet point and stick simulation for
+// cosmetic physics like rope, hair, and cloth.
+package verlet
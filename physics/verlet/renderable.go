@@ -0,0 +1,63 @@
+package verlet
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/oakmound/oak/v4/render"
+)
+
+// A Renderable draws the sticks of a System as lines. Points are drawn relative
+// to the renderable's position, so a renderable at (0,0) draws points at their
+// simulated positions.
+type Renderable struct {
+	render.LayeredPoint
+	System    *System
+	Color     color.Color
+	Thickness int
+}
+
+// NewRenderable creates a renderable drawing the sticks of a system as lines of the
+// given color and thickness.
+func NewRenderable(sys *System, c color.Color, thickness int) *Renderable {
+	return &Renderable{
+		LayeredPoint: render.NewLayeredPoint(0, 0, 0),
+		System:       sys,
+		Color:        c,
+		Thickness:    thickness,
+	}
+}
+
+// GetDims returns the extent of the system's sticks from this renderable's position.
+func (r *Renderable) GetDims() (int, int) {
+	w, h := 0, 0
+	for _, seg := range r.System.segments() {
+		for _, p := range seg {
+			if x := int(p.X()-r.X()) + r.Thickness + 1; x > w {
+				w = x
+			}
+			if y := int(p.Y()-r.Y()) + r.Thickness + 1; y > h {
+				h = y
+			}
+		}
+	}
+	return w, h
+}
+
+// Draw draws each stick in the system. Buffers which are not *image.RGBA are not
+// drawn to.
+func (r *Renderable) Draw(buff draw.Image, xOff, yOff float64) {
+	rgba, ok := buff.(*image.RGBA)
+	if !ok {
+		return
+	}
+	xOff += r.X()
+	yOff += r.Y()
+	for _, seg := range r.System.segments() {
+		render.DrawThickLine(rgba,
+			int(seg[0].X()+xOff), int(seg[0].Y()+yOff),
+			int(seg[1].X()+xOff), int(seg[1].Y()+yOff),
+			r.Color, r.Thickness)
+	}
+}
@@ -0,0 +1,309 @@
+package verlet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+)
+
+// A Point is a particle moved by a System. Its velocity is implied by the
+// difference between its current and previous positions.
+type Point struct {
+	Position floatgeom.Point2
+	Previous floatgeom.Point2
+	// Pinned points are never moved by their system.
+	Pinned bool
+}
+
+// Pin fixes a point in place at the given position.
+func (p *Point) Pin(at floatgeom.Point2) {
+	p.Position = at
+	p.Previous = at
+	p.Pinned = true
+}
+
+// Velocity returns how far this point moved in its last step.
+func (p *Point) Velocity() floatgeom.Point2 {
+	return p.Position.Sub(p.Previous)
+}
+
+// A Stick keeps two points at a fixed distance from one another.
+type Stick struct {
+	A, B   *Point
+	Length float64
+	// Stiffness is how much of the stick's error is corrected each iteration,
+	// from 0 (not at all) to 1 (fully rigid).
+	Stiffness float64
+}
+
+func (s *Stick) satisfy() {
+	if s.A.Pinned && s.B.Pinned {
+		return
+	}
+	d := s.B.Position.Sub(s.A.Position)
+	dist := d.Magnitude()
+	if dist == 0 {
+		return
+	}
+	correction := d.MulConst((dist - s.Length) / dist * s.Stiffness)
+	switch {
+	case s.A.Pinned:
+		s.B.Position = s.B.Position.Sub(correction)
+	case s.B.Pinned:
+		s.A.Position = s.A.Position.Add(correction)
+	default:
+		half := correction.DivConst(2)
+		s.A.Position = s.A.Position.Add(half)
+		s.B.Position = s.B.Position.Sub(half)
+	}
+}
+
+// A System simulates points connected by sticks with Verlet integration. It is
+// intended for cosmetic effects like rope, hair, and cloth; points collide with
+// the spaces of a collision tree but do not push them.
+type System struct {
+	// Tree, if not nil, is the tree points collide with.
+	Tree *collision.Tree
+	// Labels, if non-empty, restricts the spaces points collide with to
+	// those with one of these labels.
+	Labels  []collision.Label
+	Handler event.Handler
+
+	// Gravity and Wind are accelerations applied to all points, in units per
+	// second squared.
+	Gravity floatgeom.Point2
+	Wind    floatgeom.Point2
+	// Damping is the fraction of each point's velocity kept each step.
+	Damping float64
+	// Radius is how far points are kept from the spaces they collide with.
+	Radius float64
+	// Timestep is the fixed duration of a single simulation step.
+	Timestep time.Duration
+	// MaxSteps limits how many steps a single call to Advance may run.
+	MaxSteps int
+	// Iterations is how many times sticks and collisions are resolved per step.
+	Iterations int
+
+	mu          sync.Mutex
+	points      []*Point
+	sticks      []*Stick
+	accumulated time.Duration
+}
+
+// NewSystem creates a system colliding with the given tree and binding to the
+// given handler. If tree is nil, points will not collide with anything. If
+// handler is nil, the DefaultBus will be used.
+func NewSystem(tree *collision.Tree, handler event.Handler) *System {
+	if handler == nil {
+		handler = event.DefaultBus
+	}
+	return &System{
+		Tree:       tree,
+		Handler:    handler,
+		Gravity:    floatgeom.Point2{0, 500},
+		Damping:    .99,
+		Radius:     1,
+		Timestep:   time.Second / 60,
+		MaxSteps:   5,
+		Iterations: 8,
+	}
+}
+
+// AddPoint adds a new point to the system at the given position.
+func (sys *System) AddPoint(at floatgeom.Point2) *Point {
+	p := &Point{Position: at, Previous: at}
+	sys.mu.Lock()
+	sys.points = append(sys.points, p)
+	sys.mu.Unlock()
+	return p
+}
+
+// AddStick adds a new, fully stiff stick to the system between two points, at
+// their current distance.
+func (sys *System) AddStick(a, b *Point) *Stick {
+	s := &Stick{A: a, B: b, Length: a.Position.Distance(b.Position), Stiffness: 1}
+	sys.mu.Lock()
+	sys.sticks = append(sys.sticks, s)
+	sys.mu.Unlock()
+	return s
+}
+
+// AddRope adds a chain of points between from and to, split into the given number
+// of segments, connected by sticks. The first point is pinned.
+func (sys *System) AddRope(from, to floatgeom.Point2, segments int) []*Point {
+	if segments < 1 {
+		segments = 1
+	}
+	pts := make([]*Point, segments+1)
+	step := to.Sub(from).DivConst(float64(segments))
+	for i := range pts {
+		pts[i] = sys.AddPoint(from.Add(step.MulConst(float64(i))))
+		if i > 0 {
+			sys.AddStick(pts[i-1], pts[i])
+		}
+	}
+	pts[0].Pinned = true
+	return pts
+}
+
+// AddCloth adds a grid of points with the given number of columns and rows, spaced
+// apart by spacing, with its top left point at origin. Adjacent points are connected
+// by sticks, and the top row is pinned. Points are returned indexed by row, then
+// column.
+func (sys *System) AddCloth(origin floatgeom.Point2, cols, rows int, spacing float64) [][]*Point {
+	grid := make([][]*Point, rows)
+	for y := range grid {
+		grid[y] = make([]*Point, cols)
+		for x := range grid[y] {
+			p := sys.AddPoint(origin.Add(floatgeom.Point2{float64(x) * spacing, float64(y) * spacing}))
+			if x > 0 {
+				sys.AddStick(grid[y][x-1], p)
+			}
+			if y > 0 {
+				sys.AddStick(grid[y-1][x], p)
+			} else {
+				p.Pinned = true
+			}
+			grid[y][x] = p
+		}
+	}
+	return grid
+}
+
+// RemovePoint removes points from the system, along with any sticks attached to them.
+func (sys *System) RemovePoint(ps ...*Point) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	removed := make(map[*Point]struct{}, len(ps))
+	for _, p := range ps {
+		removed[p] = struct{}{}
+	}
+	points := sys.points[:0]
+	for _, p := range sys.points {
+		if _, ok := removed[p]; !ok {
+			points = append(points, p)
+		}
+	}
+	sys.points = points
+	sticks := sys.sticks[:0]
+	for _, s := range sys.sticks {
+		_, okA := removed[s.A]
+		_, okB := removed[s.B]
+		if !okA && !okB {
+			sticks = append(sticks, s)
+		}
+	}
+	sys.sticks = sticks
+}
+
+// RemoveStick removes sticks from the system.
+func (sys *System) RemoveStick(ss ...*Stick) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	for _, s := range ss {
+		for i, s2 := range sys.sticks {
+			if s2 == s {
+				sys.sticks = append(sys.sticks[:i], sys.sticks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Points returns the points in this system.
+func (sys *System) Points() []*Point {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	return append([]*Point{}, sys.points...)
+}
+
+// Sticks returns the sticks in this system.
+func (sys *System) Sticks() []*Stick {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	return append([]*Stick{}, sys.sticks...)
+}
+
+// segments returns the current endpoints of each stick.
+func (sys *System) segments() [][2]floatgeom.Point2 {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	segs := make([][2]floatgeom.Point2, len(sys.sticks))
+	for i, s := range sys.sticks {
+		segs[i] = [2]floatgeom.Point2{s.A.Position, s.B.Position}
+	}
+	return segs
+}
+
+// Advance accumulates elapsed time and runs as many fixed steps as fit within it,
+// up to MaxSteps.
+func (sys *System) Advance(elapsed time.Duration) {
+	sys.mu.Lock()
+	sys.accumulated += elapsed
+	steps := 0
+	for sys.accumulated >= sys.Timestep && steps < sys.MaxSteps {
+		sys.accumulated -= sys.Timestep
+		steps++
+	}
+	if steps == sys.MaxSteps {
+		sys.accumulated = 0
+	}
+	sys.mu.Unlock()
+	for i := 0; i < steps; i++ {
+		sys.Step()
+	}
+}
+
+// Bind causes this system to Advance by the time since the last frame at the start
+// of every frame, until the returned binding is unbound.
+func (sys *System) Bind() event.Binding {
+	return event.GlobalBind(sys.Handler, event.Enter, func(ev event.EnterPayload) event.Response {
+		sys.Advance(ev.SinceLastFrame)
+		return 0
+	})
+}
+
+// Step runs a single fixed timestep of the simulation.
+func (sys *System) Step() {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+	dt := sys.Timestep.Seconds()
+	accel := sys.Gravity.Add(sys.Wind).MulConst(dt * dt)
+	for _, p := range sys.points {
+		if p.Pinned {
+			continue
+		}
+		v := p.Velocity().MulConst(sys.Damping)
+		p.Previous = p.Position
+		p.Position = p.Position.Add(v, accel)
+	}
+	for i := 0; i < sys.Iterations; i++ {
+		for _, s := range sys.sticks {
+			s.satisfy()
+		}
+		if sys.Tree != nil {
+			for _, p := range sys.points {
+				if !p.Pinned {
+					sys.collide(p)
+				}
+			}
+		}
+	}
+}
+
+func (sys *System) collide(p *Point) {
+	probe := collision.NewUnassignedSpace(p.Position.X()-sys.Radius, p.Position.Y()-sys.Radius, 2*sys.Radius, 2*sys.Radius)
+	hits := sys.Tree.Hits(probe)
+	if len(sys.Labels) != 0 {
+		hits = collision.WithLabels(sys.Labels...)(hits)
+	}
+	for _, s := range hits {
+		m := collision.NewManifold(probe, s)
+		push := m.Normal.MulConst(m.Depth)
+		p.Position = p.Position.Add(push)
+		probe.Location = probe.Location.Shift(floatgeom.Point3{push.X(), push.Y()})
+	}
+}
@@ -0,0 +1,109 @@
+package verlet
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/collision"
+	"github.com/oakmound/oak/v4/event"
+)
+
+func newTestSystem(tree *collision.Tree) *System {
+	return NewSystem(tree, event.NewBus(event.NewCallerMap()))
+}
+
+func TestSystemRope(t *testing.T) {
+	sys := newTestSystem(nil)
+	rope := sys.AddRope(floatgeom.Point2{0, 0}, floatgeom.Point2{100, 0}, 10)
+	for i := 0; i < 600; i++ {
+		sys.Step()
+	}
+	if rope[0].Position != (floatgeom.Point2{0, 0}) {
+		t.Fatalf("pinned point moved to %v", rope[0].Position)
+	}
+	end := rope[len(rope)-1].Position
+	if math.Abs(end.X()) > 5 || math.Abs(end.Y()-100) > 5 {
+		t.Fatalf("expected rope to hang straight down, end was at %v", end)
+	}
+	for _, s := range sys.Sticks() {
+		if d := s.A.Position.Distance(s.B.Position); math.Abs(d-s.Length) > .5 {
+			t.Fatalf("stick stretched from %v to %v", s.Length, d)
+		}
+	}
+}
+
+func TestSystemWind(t *testing.T) {
+	sys := newTestSystem(nil)
+	sys.Wind = floatgeom.Point2{500, 0}
+	cloth := sys.AddCloth(floatgeom.Point2{0, 0}, 5, 5, 10)
+	for i := 0; i < 300; i++ {
+		sys.Step()
+	}
+	bottom := cloth[4][0].Position
+	if bottom.X() < 20 {
+		t.Fatalf("expected wind to blow cloth sideways, bottom corner was at %v", bottom)
+	}
+	for _, p := range cloth[0] {
+		if p.Velocity() != (floatgeom.Point2{}) {
+			t.Fatalf("pinned top row moved")
+		}
+	}
+}
+
+func TestSystemCollision(t *testing.T) {
+	tree := collision.NewTree()
+	floor := collision.NewLabeledSpace(-50, 50, 100, 10, 1)
+	tree.Add(floor)
+	sys := newTestSystem(tree)
+	p := sys.AddPoint(floatgeom.Point2{0, 0})
+	for i := 0; i < 120; i++ {
+		sys.Step()
+	}
+	if y := p.Position.Y(); y > 50-sys.Radius+.01 || y < 45 {
+		t.Fatalf("expected point to rest on floor, y was %v", y)
+	}
+
+	sys.Labels = []collision.Label{2}
+	for i := 0; i < 30; i++ {
+		sys.Step()
+	}
+	if p.Position.Y() < 60 {
+		t.Fatalf("point should ignore spaces without a matching label, y was %v", p.Position.Y())
+	}
+}
+
+func TestSystemRemovePoint(t *testing.T) {
+	sys := newTestSystem(nil)
+	rope := sys.AddRope(floatgeom.Point2{0, 0}, floatgeom.Point2{30, 0}, 3)
+	sys.RemovePoint(rope[1])
+	if len(sys.Points()) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(sys.Points()))
+	}
+	if len(sys.Sticks()) != 1 {
+		t.Fatalf("expected 1 stick, got %d", len(sys.Sticks()))
+	}
+	sys.RemoveStick(sys.Sticks()...)
+	if len(sys.Sticks()) != 0 {
+		t.Fatalf("expected no sticks, got %d", len(sys.Sticks()))
+	}
+}
+
+func TestRenderable(t *testing.T) {
+	sys := newTestSystem(nil)
+	sys.AddRope(floatgeom.Point2{5, 5}, floatgeom.Point2{25, 5}, 2)
+	r := NewRenderable(sys, color.RGBA{255, 0, 0, 255}, 0)
+	if w, h := r.GetDims(); w != 26 || h != 6 {
+		t.Fatalf("unexpected dims %v,%v", w, h)
+	}
+	buff := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	r.Draw(buff, 0, 0)
+	if buff.RGBAAt(15, 5) != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("expected line to be drawn through 15,5")
+	}
+	if buff.RGBAAt(15, 20) != (color.RGBA{}) {
+		t.Fatalf("unexpected pixel drawn at 15,20")
+	}
+}
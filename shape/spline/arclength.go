@@ -0,0 +1,98 @@
+package spline
+
+import (
+	"sort"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+)
+
+// DefaultSamples is how many times a curve is sampled when no sample count is given.
+const DefaultSamples = 256
+
+// An ArcLength table maps distances along a curve to the curve's t values, so the
+// curve can be traveled at a constant speed.
+type ArcLength struct {
+	Curve Curve
+
+	ts    []float64
+	dists []float64
+}
+
+// NewArcLength measures a curve by sampling it evenly samples times. More samples
+// are more accurate. If samples is less than one, DefaultSamples is used.
+func NewArcLength(c Curve, samples int) *ArcLength {
+	if samples < 1 {
+		samples = DefaultSamples
+	}
+	al := &ArcLength{
+		Curve: c,
+		ts:    make([]float64, samples+1),
+		dists: make([]float64, samples+1),
+	}
+	last := At(c, 0)
+	for i := 1; i <= samples; i++ {
+		t := float64(i) / float64(samples)
+		pt := At(c, t)
+		al.ts[i] = t
+		al.dists[i] = al.dists[i-1] + pt.Distance(last)
+		last = pt
+	}
+	return al
+}
+
+// Length returns the total length of the curve.
+func (al *ArcLength) Length() float64 {
+	return al.dists[len(al.dists)-1]
+}
+
+// T returns the t value that is the given distance along the curve. Distances
+// outside of the curve's length are clamped.
+func (al *ArcLength) T(distance float64) float64 {
+	if distance <= 0 {
+		return 0
+	}
+	if distance >= al.Length() {
+		return 1
+	}
+	i := sort.SearchFloat64s(al.dists, distance)
+	d0, d1 := al.dists[i-1], al.dists[i]
+	if d1 == d0 {
+		return al.ts[i]
+	}
+	return al.ts[i-1] + (al.ts[i]-al.ts[i-1])*(distance-d0)/(d1-d0)
+}
+
+// Distance returns how far along the curve t is.
+func (al *ArcLength) Distance(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return al.Length()
+	}
+	f := t * float64(len(al.ts)-1)
+	i := int(f)
+	return al.dists[i] + (al.dists[i+1]-al.dists[i])*(f-float64(i))
+}
+
+// At returns the position the given distance along the curve.
+func (al *ArcLength) At(distance float64) floatgeom.Point2 {
+	return At(al.Curve, al.T(distance))
+}
+
+// Tangent returns the unit direction of travel the given distance along the curve.
+func (al *ArcLength) Tangent(distance float64) floatgeom.Point2 {
+	return Tangent(al.Curve, al.T(distance))
+}
+
+// Normal returns the unit normal the given distance along the curve, as Normal.
+func (al *ArcLength) Normal(distance float64) floatgeom.Point2 {
+	return Normal(al.Curve, al.T(distance))
+}
+
+// Nearest returns the distance along the curve and position of the point on the
+// curve closest to p.
+func (al *ArcLength) Nearest(p floatgeom.Point2) (float64, floatgeom.Point2) {
+	t, pt := Nearest(al.Curve, p, len(al.ts)-1)
+	return al.Distance(t), pt
+}
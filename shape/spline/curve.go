@@ -0,0 +1,108 @@
+package spline
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/shape"
+)
+
+// A Curve is a path through 2D space parameterized from 0 at its start to 1 at its
+// end. Every Curve is also a shape.Bezier, so it can be drawn with render.BezierLine.
+type Curve interface {
+	shape.Bezier
+	// Derivative returns the rate of change of the curve's position at t.
+	Derivative(t float64) floatgeom.Point2
+}
+
+// A Knotted curve can report the t values where its segments join. For paths
+// through points, these are the t values of each point.
+type Knotted interface {
+	Knots() []float64
+}
+
+// At returns the position of a curve at t.
+func At(c Curve, t float64) floatgeom.Point2 {
+	x, y := c.Pos(t)
+	return floatgeom.Point2{x, y}
+}
+
+// Tangent returns the unit direction of travel along a curve at t. Where the curve's
+// derivative is zero, the direction is estimated from nearby positions.
+func Tangent(c Curve, t float64) floatgeom.Point2 {
+	d := c.Derivative(t)
+	if d.Magnitude() != 0 {
+		return d.Normalize()
+	}
+	const step = 1e-4
+	if t+step <= 1 {
+		return At(c, t+step).Sub(At(c, t)).Normalize()
+	}
+	return At(c, t).Sub(At(c, t-step)).Normalize()
+}
+
+// Normal returns the unit tangent of a curve at t rotated a quarter turn clockwise
+// on screen, where y increases downward. It points to the right of the direction
+// of travel.
+func Normal(c Curve, t float64) floatgeom.Point2 {
+	tan := Tangent(c, t)
+	return floatgeom.Point2{-tan.Y(), tan.X()}
+}
+
+// Nearest returns the t value and position of the point on a curve closest to p.
+// The curve is sampled evenly samples times, then the closest sample is refined.
+// If samples is less than one, DefaultSamples is used.
+func Nearest(c Curve, p floatgeom.Point2, samples int) (float64, floatgeom.Point2) {
+	if samples < 1 {
+		samples = DefaultSamples
+	}
+	dist := func(t float64) float64 {
+		d := At(c, t).Sub(p)
+		return d.Dot(d)
+	}
+	best, bestDist := 0.0, math.Inf(1)
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / float64(samples)
+		if d := dist(t); d < bestDist {
+			best, bestDist = t, d
+		}
+	}
+	// Golden section search between the neighboring samples.
+	const phi = 0.6180339887498949
+	lo := math.Max(0, best-1/float64(samples))
+	hi := math.Min(1, best+1/float64(samples))
+	a := hi - phi*(hi-lo)
+	b := lo + phi*(hi-lo)
+	da, db := dist(a), dist(b)
+	for i := 0; i < 48; i++ {
+		if da < db {
+			hi, b, db = b, a, da
+			a = hi - phi*(hi-lo)
+			da = dist(a)
+		} else {
+			lo, a, da = a, b, db
+			b = lo + phi*(hi-lo)
+			db = dist(b)
+		}
+	}
+	t := (lo + hi) / 2
+	if dist(t) > bestDist {
+		t = best
+	}
+	return t, At(c, t)
+}
+
+// Bezier adapts a shape.Bezier into a Curve, estimating its derivative from
+// nearby positions.
+type Bezier struct {
+	shape.Bezier
+}
+
+// Derivative returns the rate of change of the bezier's position at t.
+func (b Bezier) Derivative(t float64) floatgeom.Point2 {
+	const h = 1e-5
+	lo, hi := math.Max(0, t-h), math.Min(1, t+h)
+	x1, y1 := b.Pos(lo)
+	x2, y2 := b.Pos(hi)
+	return floatgeom.Point2{x2 - x1, y2 - y1}.DivConst(hi - lo)
+}
@@ -0,0 +1,4 @@
+// Package spline provides smooth paths through points, arc-length
+// parameterization for constant speed travel along those paths, and a Follower
+// to move positions along them.
+package spline
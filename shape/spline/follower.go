@@ -0,0 +1,151 @@
+package spline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+)
+
+var (
+	// WaypointReached is triggered on a follower's caller when it passes one of its
+	// waypoints. Events for waypoints passed in the same update may be handled in
+	// any order.
+	WaypointReached = event.RegisterEvent[Waypoint]()
+	// PathEnd is triggered on a follower's caller when it reaches the end of a path
+	// that does not loop.
+	PathEnd = event.RegisterEvent[*Follower]()
+)
+
+// A Waypoint describes a waypoint that a follower has passed.
+type Waypoint struct {
+	// Index is the position of this waypoint in the follower's Waypoints.
+	Index    int
+	Distance float64
+	Point    floatgeom.Point2
+}
+
+// A Follower moves something along a path at a constant speed.
+type Follower struct {
+	Path *ArcLength
+	// Speed is how far along the path the follower travels each second.
+	Speed float64
+	// Loop causes the follower to return to the start of the path when it reaches
+	// the end, instead of stopping. Waypoints at the end of a looping path are
+	// skipped, as they are passed at the same time as those at its start.
+	Loop bool
+	// Waypoints are distances along the path, in increasing order, at which to
+	// trigger WaypointReached.
+	Waypoints []float64
+	// Move is called with each new position of the follower.
+	Move func(floatgeom.Point2)
+	// Distance is how far along the path the follower is.
+	Distance float64
+
+	cid  event.CallerID
+	next int
+	done bool
+}
+
+// NewFollower creates a follower that moves along a path, triggering its events on
+// the given caller. If the path's curve is Knotted, the follower's waypoints are
+// its knots. Entities can be moved by passing their SetPos method, and renderables
+// with MovePositional.
+func NewFollower(cid event.CallerID, path *ArcLength, speed float64, move func(floatgeom.Point2)) *Follower {
+	f := &Follower{
+		Path:  path,
+		Speed: speed,
+		Move:  move,
+		cid:   cid,
+	}
+	if k, ok := path.Curve.(Knotted); ok {
+		for _, t := range k.Knots() {
+			f.Waypoints = append(f.Waypoints, path.Distance(t))
+		}
+	}
+	return f
+}
+
+// MovePositional returns a function which sets the position of p, for use with a
+// Follower.
+func MovePositional(p render.Positional) func(floatgeom.Point2) {
+	return func(pt floatgeom.Point2) {
+		p.SetPos(pt.X(), pt.Y())
+	}
+}
+
+// Bind causes this follower to Update every frame on the given handler, until the
+// returned binding is unbound or the caller's bindings are unbound.
+func (f *Follower) Bind(h event.Handler) event.Binding {
+	return h.UnsafeBind(event.Enter.UnsafeEventID, f.cid, func(_ event.CallerID, h event.Handler, payload interface{}) event.Response {
+		f.Update(h, payload.(event.EnterPayload).SinceLastFrame)
+		return 0
+	})
+}
+
+// Done returns whether this follower has reached the end of its path.
+func (f *Follower) Done() bool {
+	return f.done
+}
+
+// Reset returns this follower to the start of its path.
+func (f *Follower) Reset() {
+	f.Distance = 0
+	f.next = 0
+	f.done = false
+}
+
+// Update advances the follower for the elapsed time, moving it and triggering any
+// events it has passed on the given handler.
+func (f *Follower) Update(h event.Handler, elapsed time.Duration) {
+	if f.done {
+		return
+	}
+	length := f.Path.Length()
+	f.Distance += f.Speed * elapsed.Seconds()
+	if f.Loop && length > 0 {
+		for f.Distance >= length {
+			f.reach(h, length, false)
+			f.Distance -= length
+			f.next = 0
+		}
+	} else if f.Distance >= length {
+		f.Distance = length
+		f.done = true
+	}
+	f.reach(h, f.Distance, true)
+	if f.Move != nil {
+		f.Move(f.Path.At(f.Distance))
+	}
+	if f.done {
+		event.TriggerForCallerOn(h, f.cid, PathEnd, f)
+	}
+}
+
+// reach triggers every waypoint not yet passed up to the given distance.
+func (f *Follower) reach(h event.Handler, distance float64, inclusive bool) {
+	for f.next < len(f.Waypoints) {
+		d := f.Waypoints[f.next]
+		if d > distance || (!inclusive && d >= distance) {
+			return
+		}
+		event.TriggerForCallerOn(h, f.cid, WaypointReached, Waypoint{
+			Index:    f.next,
+			Distance: d,
+			Point:    f.Path.At(d),
+		})
+		f.next++
+	}
+}
+
+// SetWaypoints sets this follower's waypoints from t values on its path's curve.
+func (f *Follower) SetWaypoints(ts ...float64) {
+	f.Waypoints = f.Waypoints[:0]
+	for _, t := range ts {
+		f.Waypoints = append(f.Waypoints, f.Path.Distance(t))
+	}
+	sort.Float64s(f.Waypoints)
+	f.next = sort.SearchFloat64s(f.Waypoints, f.Distance)
+}
@@ -0,0 +1,121 @@
+package spline
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/render"
+)
+
+type walker struct {
+	event.CallerID
+}
+
+func (w *walker) CID() event.CallerID {
+	return w.CallerID
+}
+
+func TestFollower(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	w := &walker{}
+	w.CallerID = b.GetCallerMap().Register(w)
+
+	p, _ := NewCatmullRom(Centripetal, false, floatgeom.Point2{0, 0}, floatgeom.Point2{10, 0}, floatgeom.Point2{20, 0})
+	al := NewArcLength(p, 0)
+	sp := render.NewEmptySprite(0, 0, 1, 1)
+	f := NewFollower(w.CallerID, al, 4, MovePositional(sp))
+
+	reached := make(chan Waypoint, 10)
+	ended := make(chan *Follower, 10)
+	<-event.Bind(b, WaypointReached, w, func(_ *walker, wp Waypoint) event.Response {
+		reached <- wp
+		return 0
+	}).Bound
+	<-event.Bind(b, PathEnd, w, func(_ *walker, f *Follower) event.Response {
+		ended <- f
+		return 0
+	}).Bound
+
+	f.Update(b, time.Second)
+	if math.Abs(sp.X()-4) > 0.01 {
+		t.Fatalf("follower moved to %v, expected 4", sp.X())
+	}
+	f.Update(b, 2*time.Second)
+	if math.Abs(sp.X()-12) > 0.01 {
+		t.Fatalf("follower moved to %v, expected 12", sp.X())
+	}
+	f.Update(b, 10*time.Second)
+	if !f.Done() || sp.X() != 20 {
+		t.Fatalf("follower should have stopped at the end, at %v", sp.X())
+	}
+	// waypoints passed in one update are triggered concurrently, so may arrive in
+	// any order
+	wps := waitFor(t, reached, 3)
+	sort.Slice(wps, func(i, j int) bool { return wps[i].Index < wps[j].Index })
+	for i, wp := range wps {
+		if wp.Index != i || math.Abs(wp.Point.X()-float64(i*10)) > 1e-6 {
+			t.Errorf("waypoint %d was %+v", i, wp)
+		}
+	}
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for path end")
+	}
+}
+
+func TestFollowerLoop(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	w := &walker{}
+	w.CallerID = b.GetCallerMap().Register(w)
+
+	p, _ := NewCatmullRom(Centripetal, true,
+		floatgeom.Point2{0, 0}, floatgeom.Point2{10, 0}, floatgeom.Point2{10, 10}, floatgeom.Point2{0, 10},
+	)
+	al := NewArcLength(p, 0)
+	var pos floatgeom.Point2
+	f := NewFollower(w.CallerID, al, al.Length()*0.55, func(p floatgeom.Point2) { pos = p })
+	f.Loop = true
+
+	reached := make(chan Waypoint, 20)
+	<-event.Bind(b, WaypointReached, w, func(_ *walker, wp Waypoint) event.Response {
+		reached <- wp
+		return 0
+	}).Bound
+	<-f.Bind(b).Bound
+
+	for i := 0; i < 5; i++ {
+		<-event.TriggerOn(b, event.Enter, event.EnterPayload{SinceLastFrame: time.Second})
+	}
+	if f.Done() {
+		t.Fatalf("looping follower should not finish")
+	}
+	if !near(pos, floatgeom.Point2{0, 10}, 1e-6) {
+		t.Errorf("follower was at %v after two and three quarter laps", pos)
+	}
+	// Four waypoints per lap, with the end of each lap sharing the start's.
+	waitFor(t, reached, 12)
+	if len(reached) != 0 {
+		t.Errorf("expected 12 waypoints, got %d", 12+len(reached))
+	}
+}
+
+// waitFor receives n waypoints, failing if they do not all arrive within a second.
+func waitFor(t *testing.T, reached chan Waypoint, n int) []Waypoint {
+	t.Helper()
+	deadline := time.After(time.Second)
+	wps := make([]Waypoint, 0, n)
+	for len(wps) < n {
+		select {
+		case wp := <-reached:
+			wps = append(wps, wp)
+		case <-deadline:
+			t.Fatalf("expected %d waypoints, got %d", n, len(wps))
+		}
+	}
+	return wps
+}
@@ -0,0 +1,194 @@
+package spline
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/oakerr"
+)
+
+// Alpha values for NewCatmullRom.
+const (
+	// Uniform Catmull-Rom curves space their knots evenly. They may loop or
+	// overshoot around points that are close together.
+	Uniform = 0.0
+	// Centripetal Catmull-Rom curves never form loops or cusps within a segment.
+	Centripetal = 0.5
+	// Chordal Catmull-Rom curves space their knots by the distance between points.
+	Chordal = 1.0
+)
+
+// A cubic is one segment of a Path, from p0 to p1 leaving p0 with derivative m0
+// and arriving at p1 with derivative m1.
+type cubic struct {
+	p0, m0, p1, m1 floatgeom.Point2
+}
+
+func (c cubic) eval(u float64) (pos, d floatgeom.Point2) {
+	u2 := u * u
+	u3 := u2 * u
+	h00 := 2*u3 - 3*u2 + 1
+	h10 := u3 - 2*u2 + u
+	h01 := -2*u3 + 3*u2
+	h11 := u3 - u2
+	pos = c.p0.MulConst(h00).Add(c.m0.MulConst(h10), c.p1.MulConst(h01), c.m1.MulConst(h11))
+	dh00 := 6*u2 - 6*u
+	dh10 := 3*u2 - 4*u + 1
+	dh01 := -6*u2 + 6*u
+	dh11 := 3*u2 - 2*u
+	d = c.p0.MulConst(dh00).Add(c.m0.MulConst(dh10), c.p1.MulConst(dh01), c.m1.MulConst(dh11))
+	return pos, d
+}
+
+// A Path is a Curve made of cubic segments joined end to end, each covering an
+// equal share of t. Hermite, Catmull-Rom and B-spline paths are all Paths.
+type Path struct {
+	segments []cubic
+}
+
+// NewHermite returns a path through each point, leaving each point with its
+// matching tangent.
+func NewHermite(points, tangents []floatgeom.Point2) (*Path, error) {
+	if len(points) < 2 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 2, InputName: "points"}
+	}
+	if len(tangents) != len(points) {
+		return nil, oakerr.InvalidInput{InputName: "tangents"}
+	}
+	p := &Path{segments: make([]cubic, len(points)-1)}
+	for i := range p.segments {
+		p.segments[i] = cubic{points[i], tangents[i], points[i+1], tangents[i+1]}
+	}
+	return p, nil
+}
+
+// NewCatmullRom returns a path through each point, with tangents chosen from
+// neighboring points. Alpha controls how knots are spaced, from Uniform through
+// Centripetal to Chordal. A closed path returns from its last point to its first.
+func NewCatmullRom(alpha float64, closed bool, points ...floatgeom.Point2) (*Path, error) {
+	if closed && len(points) < 3 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "points"}
+	}
+	if len(points) < 2 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 2, InputName: "points"}
+	}
+	n := len(points)
+	at := func(i int) floatgeom.Point2 {
+		if closed {
+			return points[((i%n)+n)%n]
+		}
+		// Open ends are extended by reflecting their neighbor.
+		if i < 0 {
+			return points[0].MulConst(2).Sub(points[1])
+		}
+		if i >= n {
+			return points[n-1].MulConst(2).Sub(points[n-2])
+		}
+		return points[i]
+	}
+	knot := func(a, b floatgeom.Point2) float64 {
+		d := math.Pow(a.Distance(b), alpha)
+		if d == 0 {
+			return 1
+		}
+		return d
+	}
+	segs := n - 1
+	if closed {
+		segs = n
+	}
+	p := &Path{segments: make([]cubic, segs)}
+	for i := range p.segments {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		d01, d12, d23 := knot(p0, p1), knot(p1, p2), knot(p2, p3)
+		m1 := p1.Sub(p0).DivConst(d01).
+			Sub(p2.Sub(p0).DivConst(d01 + d12)).
+			Add(p2.Sub(p1).DivConst(d12)).MulConst(d12)
+		m2 := p2.Sub(p1).DivConst(d12).
+			Sub(p3.Sub(p1).DivConst(d12 + d23)).
+			Add(p3.Sub(p2).DivConst(d23)).MulConst(d12)
+		p.segments[i] = cubic{p1, m1, p2, m2}
+	}
+	return p, nil
+}
+
+// NewBSpline returns a uniform cubic B-spline path guided by the given control
+// points. The path passes near, but not through, its control points. An open path
+// is clamped to begin at its first point and end at its last. A closed path loops
+// smoothly without reaching any of its points.
+func NewBSpline(closed bool, points ...floatgeom.Point2) (*Path, error) {
+	if closed && len(points) < 3 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "points"}
+	}
+	if len(points) < 2 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 2, InputName: "points"}
+	}
+	n := len(points)
+	at := func(i int) floatgeom.Point2 {
+		if closed {
+			return points[i%n]
+		}
+		// Open ends are clamped by repeating their end points.
+		i -= 2
+		if i < 0 {
+			i = 0
+		} else if i >= n {
+			i = n - 1
+		}
+		return points[i]
+	}
+	segs := n + 1
+	if closed {
+		segs = n
+	}
+	p := &Path{segments: make([]cubic, segs)}
+	for i := range p.segments {
+		q0, q1, q2, q3 := at(i), at(i+1), at(i+2), at(i+3)
+		p.segments[i] = cubic{
+			p0: q0.Add(q1.MulConst(4), q2).DivConst(6),
+			m0: q2.Sub(q0).DivConst(2),
+			p1: q1.Add(q2.MulConst(4), q3).DivConst(6),
+			m1: q3.Sub(q1).DivConst(2),
+		}
+	}
+	return p, nil
+}
+
+// Segments returns how many cubic segments make up this path.
+func (p *Path) Segments() int {
+	return len(p.segments)
+}
+
+func (p *Path) locate(t float64) (cubic, float64) {
+	n := len(p.segments)
+	f := math.Max(0, math.Min(1, t)) * float64(n)
+	i := int(f)
+	if i >= n {
+		i = n - 1
+	}
+	return p.segments[i], f - float64(i)
+}
+
+// Pos returns the position of this path at t.
+func (p *Path) Pos(t float64) (x, y float64) {
+	c, u := p.locate(t)
+	pos, _ := c.eval(u)
+	return pos.X(), pos.Y()
+}
+
+// Derivative returns the rate of change of this path's position at t.
+func (p *Path) Derivative(t float64) floatgeom.Point2 {
+	c, u := p.locate(t)
+	_, d := c.eval(u)
+	return d.MulConst(float64(len(p.segments)))
+}
+
+// Knots returns the t values where this path's segments join, including 0 and 1.
+// For Hermite and Catmull-Rom paths, these are the t values of each point.
+func (p *Path) Knots() []float64 {
+	knots := make([]float64, len(p.segments)+1)
+	for i := range knots {
+		knots[i] = float64(i) / float64(len(p.segments))
+	}
+	return knots
+}
@@ -0,0 +1,146 @@
+package spline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/shape"
+)
+
+func near(a, b floatgeom.Point2, eps float64) bool {
+	return a.Distance(b) <= eps
+}
+
+func TestPathsThroughPoints(t *testing.T) {
+	pts := []floatgeom.Point2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	for _, alpha := range []float64{Uniform, Centripetal, Chordal} {
+		for _, closed := range []bool{false, true} {
+			p, err := NewCatmullRom(alpha, closed, pts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			knots := p.Knots()
+			for i, k := range knots {
+				want := pts[i%len(pts)]
+				if got := At(p, k); !near(got, want, 1e-9) {
+					t.Errorf("alpha %v closed %v: knot %d at %v, expected %v", alpha, closed, i, got, want)
+				}
+			}
+		}
+	}
+	h, err := NewHermite(pts[:2], []floatgeom.Point2{{0, 30}, {0, 30}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := Tangent(h, 0); !near(got, floatgeom.Point2{0, 1}, 1e-9) {
+		t.Errorf("hermite tangent %v, expected straight down", got)
+	}
+	if got := Normal(h, 0); !near(got, floatgeom.Point2{-1, 0}, 1e-9) {
+		t.Errorf("hermite normal %v, expected left", got)
+	}
+}
+
+func TestPathErrors(t *testing.T) {
+	pts := []floatgeom.Point2{{0, 0}, {1, 1}}
+	if _, err := NewCatmullRom(Uniform, false, pts[:1]...); err == nil {
+		t.Errorf("expected error for one point")
+	}
+	if _, err := NewCatmullRom(Uniform, true, pts...); err == nil {
+		t.Errorf("expected error for closed path of two points")
+	}
+	if _, err := NewBSpline(true, pts...); err == nil {
+		t.Errorf("expected error for closed b-spline of two points")
+	}
+	if _, err := NewHermite(pts, pts[:1]); err == nil {
+		t.Errorf("expected error for mismatched tangents")
+	}
+}
+
+func TestDerivative(t *testing.T) {
+	pts := []floatgeom.Point2{{0, 0}, {10, 5}, {20, -5}, {25, 10}, {40, 0}}
+	cr, _ := NewCatmullRom(Centripetal, false, pts...)
+	bs, _ := NewBSpline(false, pts...)
+	bz, _ := shape.BezierCurve(0, 0, 10, 20, 30, 0)
+	for name, c := range map[string]Curve{"catmull-rom": cr, "b-spline": bs, "bezier": Bezier{bz}} {
+		for _, tv := range []float64{0.1, 0.33, 0.45, 0.71, 0.9} {
+			const h = 1e-6
+			want := At(c, tv+h).Sub(At(c, tv-h)).DivConst(2 * h)
+			if got := c.Derivative(tv); !near(got, want, 1e-3*math.Max(1, want.Magnitude())) {
+				t.Errorf("%s: derivative at %v was %v, expected %v", name, tv, got, want)
+			}
+		}
+	}
+}
+
+func TestBSplineClamped(t *testing.T) {
+	pts := []floatgeom.Point2{{0, 0}, {10, 20}, {20, -20}, {30, 0}}
+	bs, err := NewBSpline(false, pts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := At(bs, 0); !near(got, pts[0], 1e-9) {
+		t.Errorf("b-spline started at %v", got)
+	}
+	if got := At(bs, 1); !near(got, pts[3], 1e-9) {
+		t.Errorf("b-spline ended at %v", got)
+	}
+	if got := Tangent(bs, 0); got.Magnitude() < .99 {
+		t.Errorf("b-spline had no tangent at its clamped start: %v", got)
+	}
+	closed, _ := NewBSpline(true, pts...)
+	if got := At(closed, 0); !near(got, At(closed, 1), 1e-9) {
+		t.Errorf("closed b-spline did not loop: %v vs %v", got, At(closed, 1))
+	}
+}
+
+func TestArcLength(t *testing.T) {
+	// A straight line with bunched up knots moves at uneven speed in t.
+	p, _ := NewHermite(
+		[]floatgeom.Point2{{0, 0}, {10, 0}, {100, 0}},
+		[]floatgeom.Point2{{0, 0}, {10, 0}, {0, 0}},
+	)
+	al := NewArcLength(p, 1024)
+	if l := al.Length(); math.Abs(l-100) > 1e-6 {
+		t.Fatalf("length was %v, expected 100", l)
+	}
+	for _, d := range []float64{0, 5, 25, 50, 75, 99, 100} {
+		if got := al.At(d); math.Abs(got.X()-d) > 0.05 {
+			t.Errorf("point %v along line was %v", d, got)
+		}
+		if got := al.Distance(al.T(d)); math.Abs(got-d) > 1e-6 {
+			t.Errorf("distance of T(%v) was %v", d, got)
+		}
+	}
+	if al.T(-5) != 0 || al.T(500) != 1 {
+		t.Errorf("distances outside the curve were not clamped")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	circle, _ := NewCatmullRom(Centripetal, true,
+		floatgeom.Point2{10, 0}, floatgeom.Point2{0, 10},
+		floatgeom.Point2{-10, 0}, floatgeom.Point2{0, -10},
+	)
+	for _, p := range []floatgeom.Point2{{20, 1}, {3, 3}, {-1, -30}} {
+		tv, got := Nearest(circle, p, 64)
+		if !near(got, At(circle, tv), 1e-9) {
+			t.Fatalf("nearest point %v does not match t %v", got, tv)
+		}
+		best := math.Inf(1)
+		for i := 0; i <= 10000; i++ {
+			best = math.Min(best, At(circle, float64(i)/10000).Distance(p))
+		}
+		if d := got.Distance(p); d > best+1e-6 {
+			t.Errorf("nearest to %v was %v away, brute force found %v", p, d, best)
+		}
+	}
+	al := NewArcLength(circle, 0)
+	d, pt := al.Nearest(floatgeom.Point2{20, 0})
+	if d > 1e-6 && math.Abs(d-al.Length()) > 1e-6 {
+		t.Errorf("nearest distance to start was %v", d)
+	}
+	if !near(pt, floatgeom.Point2{10, 0}, 1e-6) {
+		t.Errorf("nearest point to start was %v", pt)
+	}
+}
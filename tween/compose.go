@@ -0,0 +1,176 @@
+package tween
+
+import "time"
+
+func clamp(at, d time.Duration) time.Duration {
+	if at < 0 {
+		return 0
+	}
+	if at > d {
+		return d
+	}
+	return at
+}
+
+func add(a, b time.Duration) time.Duration {
+	if a > Forever-b {
+		return Forever
+	}
+	return a + b
+}
+
+type delay time.Duration
+
+// Delay returns a tween which does nothing for the duration d, to wait between
+// other tweens in a sequence.
+func Delay(d time.Duration) Tween {
+	return delay(d)
+}
+
+func (d delay) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (delay) Seek(time.Duration) {}
+
+type sequence struct {
+	tweens []Tween
+	last   time.Duration
+}
+
+// Sequence returns a tween which plays each of the given tweens one after another.
+func Sequence(ts ...Tween) Tween {
+	return &sequence{tweens: ts}
+}
+
+func (s *sequence) Duration() time.Duration {
+	var d time.Duration
+	for _, t := range s.tweens {
+		d = add(d, t.Duration())
+	}
+	return d
+}
+
+// Seek applies each tween passed since the last seek in the order they were
+// passed, so that tweens of the same property leave it as the latest one set it.
+func (s *sequence) Seek(at time.Duration) {
+	at = clamp(at, s.Duration())
+	starts := make([]time.Duration, len(s.tweens))
+	var start time.Duration
+	for i, t := range s.tweens {
+		starts[i] = start
+		start = add(start, t.Duration())
+	}
+	if at >= s.last {
+		for i, t := range s.tweens {
+			if starts[i] <= at && add(starts[i], t.Duration()) >= s.last {
+				t.Seek(at - starts[i])
+			}
+		}
+	} else {
+		for i := len(s.tweens) - 1; i >= 0; i-- {
+			t := s.tweens[i]
+			if starts[i] <= s.last && add(starts[i], t.Duration()) >= at {
+				t.Seek(at - starts[i])
+			}
+		}
+	}
+	s.last = at
+}
+
+type parallel []Tween
+
+// Parallel returns a tween which plays each of the given tweens at the same time.
+// It lasts as long as the longest of them.
+func Parallel(ts ...Tween) Tween {
+	return parallel(ts)
+}
+
+func (p parallel) Duration() time.Duration {
+	var d time.Duration
+	for _, t := range p {
+		if td := t.Duration(); td > d {
+			d = td
+		}
+	}
+	return d
+}
+
+func (p parallel) Seek(at time.Duration) {
+	for _, t := range p {
+		t.Seek(clamp(at, t.Duration()))
+	}
+}
+
+type repeat struct {
+	Tween
+	count int
+	cycle time.Duration
+}
+
+// Repeat returns a tween which plays t count times. If count is less than one,
+// t repeats forever.
+func Repeat(t Tween, count int) Tween {
+	return &repeat{Tween: t, count: count}
+}
+
+func (r *repeat) Duration() time.Duration {
+	d := r.Tween.Duration()
+	if r.count < 1 {
+		if d == 0 {
+			return 0
+		}
+		return Forever
+	}
+	if d > Forever/time.Duration(r.count) {
+		return Forever
+	}
+	return d * time.Duration(r.count)
+}
+
+func (r *repeat) Seek(at time.Duration) {
+	d := r.Tween.Duration()
+	if d == 0 {
+		r.Tween.Seek(0)
+		return
+	}
+	at = clamp(at, r.Duration())
+	cycle := at / d
+	offset := at - cycle*d
+	if r.count > 0 && cycle >= time.Duration(r.count) {
+		cycle, offset = time.Duration(r.count)-1, d
+	}
+	// Finish or rewind the previous cycle so the tween is consistent when it
+	// begins a new one.
+	if cycle > r.cycle {
+		r.Tween.Seek(d)
+	} else if cycle < r.cycle {
+		r.Tween.Seek(0)
+	}
+	r.cycle = cycle
+	r.Tween.Seek(offset)
+}
+
+type yoyo struct {
+	Tween
+}
+
+// Yoyo returns a tween which plays t forward and then backward, lasting twice as
+// long as t.
+func Yoyo(t Tween) Tween {
+	return yoyo{t}
+}
+
+func (y yoyo) Duration() time.Duration {
+	return add(y.Tween.Duration(), y.Tween.Duration())
+}
+
+func (y yoyo) Seek(at time.Duration) {
+	d := y.Tween.Duration()
+	at = clamp(at, y.Duration())
+	if at <= d {
+		y.Tween.Seek(at)
+		return
+	}
+	y.Tween.Seek(2*d - at)
+}
@@ -0,0 +1,112 @@
+package tween
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func seekAll(tw Tween, step time.Duration, until time.Duration) {
+	for at := time.Duration(0); at <= until; at += step {
+		tw.Seek(at)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var v float64
+	seq := Sequence(
+		Float(&v, 0, 10, time.Second, nil),
+		Delay(time.Second),
+		Float(&v, 10, 0, time.Second, nil),
+	)
+	if seq.Duration() != 3*time.Second {
+		t.Fatalf("duration was %v", seq.Duration())
+	}
+	tests := []struct {
+		at   time.Duration
+		want float64
+	}{
+		{500 * time.Millisecond, 5},
+		{1500 * time.Millisecond, 10},
+		{2500 * time.Millisecond, 5},
+		{3 * time.Second, 0},
+		// Seeking backward rewinds through earlier tweens.
+		{1200 * time.Millisecond, 10},
+		{100 * time.Millisecond, 1},
+		// Skipping over tweens still applies them in order.
+		{2900 * time.Millisecond, 1},
+	}
+	for _, tc := range tests {
+		seq.Seek(tc.at)
+		if math.Abs(v-tc.want) > 1e-9 {
+			t.Errorf("at %v got %v, expected %v", tc.at, v, tc.want)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var a, b float64
+	par := Parallel(
+		Float(&a, 0, 10, time.Second, nil),
+		Float(&b, 0, 10, 2*time.Second, nil),
+	)
+	if par.Duration() != 2*time.Second {
+		t.Fatalf("duration was %v", par.Duration())
+	}
+	par.Seek(1500 * time.Millisecond)
+	if a != 10 || b != 7.5 {
+		t.Errorf("got %v and %v, expected 10 and 7.5", a, b)
+	}
+}
+
+func TestRepeatYoyo(t *testing.T) {
+	var v float64
+	tw := Repeat(Yoyo(Float(&v, 0, 10, time.Second, nil)), 2)
+	if tw.Duration() != 4*time.Second {
+		t.Fatalf("duration was %v", tw.Duration())
+	}
+	tests := []struct {
+		at   time.Duration
+		want float64
+	}{
+		{500 * time.Millisecond, 5},
+		{1500 * time.Millisecond, 5},
+		{2250 * time.Millisecond, 2.5},
+		{3 * time.Second, 10},
+		{10 * time.Second, 0},
+	}
+	for _, tc := range tests {
+		tw.Seek(tc.at)
+		if math.Abs(v-tc.want) > 1e-9 {
+			t.Errorf("at %v got %v, expected %v", tc.at, v, tc.want)
+		}
+	}
+	forever := Repeat(Float(&v, 0, 10, time.Second, nil), 0)
+	if forever.Duration() != Forever {
+		t.Fatalf("endless repeat lasted %v", forever.Duration())
+	}
+	forever.Seek(time.Hour + 300*time.Millisecond)
+	if math.Abs(v-3) > 1e-9 {
+		t.Errorf("endless repeat was at %v", v)
+	}
+	if Sequence(forever, Delay(time.Second)).Duration() != Forever {
+		t.Errorf("sequence containing an endless tween should be endless")
+	}
+}
+
+func TestRepeatSequence(t *testing.T) {
+	var v float64
+	tw := Repeat(Sequence(
+		Float(&v, 0, 10, time.Second, nil),
+		Float(&v, 10, 20, time.Second, nil),
+	), 3)
+	seekAll(tw, 300*time.Millisecond, 5*time.Second)
+	tw.Seek(5100 * time.Millisecond)
+	if math.Abs(v-11) > 1e-9 {
+		t.Errorf("third cycle value was %v, expected 11", v)
+	}
+	tw.Seek(200 * time.Millisecond)
+	if math.Abs(v-2) > 1e-9 {
+		t.Errorf("rewound value was %v, expected 2", v)
+	}
+}
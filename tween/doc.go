@@ -0,0 +1,3 @@
+// Package tween provides time based animation of positions, colors, fades and
+// arbitrary values, composed into sequences and groups and played each frame.
+package tween
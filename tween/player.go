@@ -0,0 +1,121 @@
+package tween
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+// Complete is triggered on a player's caller when its tween finishes. It is not
+// triggered for players that are stopped.
+var Complete = event.RegisterEvent[*Player]()
+
+// A Player plays a tween as time passes.
+type Player struct {
+	Tween Tween
+	// Elapsed is how far into its tween the player is.
+	Elapsed time.Duration
+	// Speed scales how quickly time passes for the player. 1 is normal speed.
+	Speed float64
+	// Paused players do not advance when updated.
+	Paused bool
+
+	cid      event.CallerID
+	stopOnce sync.Once
+	stop     chan struct{}
+	doneOnce sync.Once
+	done     chan struct{}
+}
+
+// NewPlayer creates a player for a tween, which triggers its events on the given
+// caller.
+func NewPlayer(cid event.CallerID, t Tween) *Player {
+	return &Player{
+		Tween: t,
+		Speed: 1,
+		cid:   cid,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Play starts playing a tween each frame of the context's scene. The player stops
+// when the scene ends, if it has not already finished.
+func Play(ctx *scene.Context, cid event.CallerID, t Tween) *Player {
+	p := NewPlayer(cid, t)
+	p.Bind(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Stop()
+		case <-p.done:
+		case <-p.stop:
+		}
+	}()
+	return p
+}
+
+// Bind causes this player to Update every frame on the given handler, until it
+// finishes, is stopped, or the returned binding is unbound.
+func (p *Player) Bind(h event.Handler) event.Binding {
+	return h.UnsafeBind(event.Enter.UnsafeEventID, p.cid, func(_ event.CallerID, h event.Handler, payload interface{}) event.Response {
+		if p.Update(h, payload.(event.EnterPayload).SinceLastFrame) {
+			return event.ResponseUnbindThisBinding
+		}
+		return 0
+	})
+}
+
+// Update advances the player for the elapsed time and applies its tween, returning
+// whether the player has finished or stopped. Complete is triggered on the given
+// handler when the tween finishes.
+func (p *Player) Update(h event.Handler, elapsed time.Duration) bool {
+	if p.Stopped() || p.Done() {
+		return true
+	}
+	if p.Paused {
+		return false
+	}
+	p.Elapsed = add(p.Elapsed, time.Duration(float64(elapsed)*p.Speed))
+	if p.Elapsed < 0 {
+		p.Elapsed = 0
+	}
+	p.Tween.Seek(p.Elapsed)
+	if p.Elapsed >= p.Tween.Duration() {
+		p.doneOnce.Do(func() {
+			close(p.done)
+		})
+		event.TriggerForCallerOn(h, p.cid, Complete, p)
+		return true
+	}
+	return false
+}
+
+// Stop stops this player where it is. Stopped players cannot be resumed.
+func (p *Player) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+// Stopped returns whether this player has been stopped.
+func (p *Player) Stopped() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns whether this player has finished its tween.
+func (p *Player) Done() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package tween
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/event"
+	"github.com/oakmound/oak/v4/scene"
+)
+
+type target struct {
+	event.CallerID
+}
+
+func (tg *target) CID() event.CallerID {
+	return tg.CallerID
+}
+
+func TestPlay(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	tg := &target{}
+	tg.CallerID = b.GetCallerMap().Register(tg)
+	cctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := &scene.Context{Context: cctx, Handler: b}
+
+	completed := make(chan *Player, 2)
+	<-event.Bind(b, Complete, tg, func(_ *target, p *Player) event.Response {
+		completed <- p
+		return 0
+	}).Bound
+
+	var v float64
+	p := Play(ctx, tg.CallerID, Float(&v, 0, 10, time.Second, nil))
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		<-event.TriggerOn(b, event.Enter, event.EnterPayload{SinceLastFrame: 300 * time.Millisecond})
+	}
+	if v != 10 || !p.Done() {
+		t.Fatalf("expected tween to finish at 10, got %v", v)
+	}
+	select {
+	case got := <-completed:
+		if got != p {
+			t.Errorf("completion triggered for the wrong player")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for completion")
+	}
+	<-event.TriggerOn(b, event.Enter, event.EnterPayload{SinceLastFrame: 300 * time.Millisecond})
+	time.Sleep(10 * time.Millisecond)
+	if len(completed) != 0 {
+		t.Errorf("completion triggered more than once")
+	}
+}
+
+func TestPlayStopsAtSceneEnd(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	cctx, cancel := context.WithCancel(context.Background())
+	ctx := &scene.Context{Context: cctx, Handler: b}
+
+	var v float64
+	p := Play(ctx, 0, Float(&v, 0, 10, time.Second, nil))
+	time.Sleep(10 * time.Millisecond)
+	<-event.TriggerOn(b, event.Enter, event.EnterPayload{SinceLastFrame: 500 * time.Millisecond})
+	cancel()
+	deadline := time.After(time.Second)
+	for !p.Stopped() {
+		select {
+		case <-deadline:
+			t.Fatalf("player was not stopped when its scene ended")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	<-event.TriggerOn(b, event.Enter, event.EnterPayload{SinceLastFrame: 500 * time.Millisecond})
+	if v != 5 || p.Done() {
+		t.Errorf("stopped player kept playing: %v", v)
+	}
+}
+
+func TestPlayerPauseSpeed(t *testing.T) {
+	b := event.NewBus(event.NewCallerMap())
+	var v float64
+	p := NewPlayer(0, Float(&v, 0, 10, time.Second, nil))
+	p.Speed = 2
+	p.Update(b, 250*time.Millisecond)
+	if v != 5 {
+		t.Errorf("double speed player was at %v", v)
+	}
+	p.Paused = true
+	p.Update(b, 250*time.Millisecond)
+	if v != 5 {
+		t.Errorf("paused player advanced to %v", v)
+	}
+}
@@ -0,0 +1,112 @@
+package tween
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/ease"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/render"
+	"github.com/oakmound/oak/v4/render/mod"
+)
+
+// Forever is the duration of tweens which never end.
+const Forever = time.Duration(math.MaxInt64)
+
+// A Tween animates something over a fixed duration.
+type Tween interface {
+	// Duration returns how long this tween lasts.
+	Duration() time.Duration
+	// Seek applies the state of this tween at the given time since it started.
+	// Times outside of the tween's duration are clamped.
+	Seek(at time.Duration)
+}
+
+// A Property tweens between two values of some type, setting each value it
+// reaches.
+type Property[T any] struct {
+	From, To T
+	Length   time.Duration
+	// Ease shapes progress from From to To. If nil, progress is linear.
+	Ease ease.Func
+	// Lerp returns the value f of the way from one value to another.
+	Lerp func(from, to T, f float64) T
+	Set  func(T)
+}
+
+// Value returns a property tween from one value to another over the duration d.
+func Value[T any](from, to T, d time.Duration, e ease.Func, lerp func(from, to T, f float64) T, set func(T)) *Property[T] {
+	return &Property[T]{
+		From:   from,
+		To:     to,
+		Length: d,
+		Ease:   e,
+		Lerp:   lerp,
+		Set:    set,
+	}
+}
+
+// Duration returns how long this tween lasts.
+func (p *Property[T]) Duration() time.Duration {
+	return p.Length
+}
+
+// Seek sets the property's value at the given time.
+func (p *Property[T]) Seek(at time.Duration) {
+	f := 1.0
+	if p.Length > 0 {
+		f = math.Max(0, math.Min(1, float64(at)/float64(p.Length)))
+	}
+	if p.Ease != nil {
+		f = p.Ease(f)
+	}
+	p.Set(p.Lerp(p.From, p.To, f))
+}
+
+// Float tweens the value v points to.
+func Float(v *float64, from, to float64, d time.Duration, e ease.Func) *Property[float64] {
+	return Value(from, to, d, e, ease.Lerp, func(f float64) {
+		*v = f
+	})
+}
+
+// Position tweens the position of p.
+func Position(p render.Positional, from, to floatgeom.Point2, d time.Duration, e ease.Func) *Property[floatgeom.Point2] {
+	return Value(from, to, d, e, lerpPoint, func(pt floatgeom.Point2) {
+		p.SetPos(pt.X(), pt.Y())
+	})
+}
+
+func lerpPoint(a, b floatgeom.Point2, f float64) floatgeom.Point2 {
+	return floatgeom.Point2{ease.Lerp(a.X(), b.X(), f), ease.Lerp(a.Y(), b.Y(), f)}
+}
+
+// Color tweens between two colors, passing each color reached to set.
+func Color(from, to color.Color, d time.Duration, e ease.Func, set func(color.Color)) *Property[color.Color] {
+	return Value(from, to, d, e, lerpColor, set)
+}
+
+func lerpColor(a, b color.Color, f float64) color.Color {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	channel := func(c1, c2 uint32) uint16 {
+		return uint16(math.Max(0, math.Min(65535, math.Round(ease.Lerp(float64(c1), float64(c2), f)))))
+	}
+	return color.RGBA64{channel(r1, r2), channel(g1, g2), channel(b1, b2), channel(a1, a2)}
+}
+
+// Fade tweens how faded m is, applying mod.Fade to m's image as it was when the
+// tween was created. Alpha values range from 0, unchanged, to 255, invisible.
+func Fade(m render.Modifiable, from, to int, d time.Duration, e ease.Func) *Property[int] {
+	rgba := m.GetRGBA()
+	original := image.NewRGBA(rgba.Bounds())
+	copy(original.Pix, rgba.Pix)
+	return Value(from, to, d, e, func(a, b int, f float64) int {
+		return int(math.Round(ease.Lerp(float64(a), float64(b), f)))
+	}, func(alpha int) {
+		copy(rgba.Pix, original.Pix)
+		m.Filter(mod.Fade(alpha))
+	})
+}
@@ -0,0 +1,76 @@
+package tween
+
+import (
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v4/alg/ease"
+	"github.com/oakmound/oak/v4/alg/floatgeom"
+	"github.com/oakmound/oak/v4/render"
+)
+
+func TestFloat(t *testing.T) {
+	var v float64
+	tw := Float(&v, 10, 20, time.Second, nil)
+	tests := []struct {
+		at   time.Duration
+		want float64
+	}{
+		{-time.Second, 10},
+		{0, 10},
+		{250 * time.Millisecond, 12.5},
+		{time.Second, 20},
+		{2 * time.Second, 20},
+	}
+	for _, tc := range tests {
+		tw.Seek(tc.at)
+		if math.Abs(v-tc.want) > 1e-9 {
+			t.Errorf("at %v got %v, expected %v", tc.at, v, tc.want)
+		}
+	}
+	tw.Ease = ease.InQuad
+	tw.Seek(500 * time.Millisecond)
+	if math.Abs(v-12.5) > 1e-9 {
+		t.Errorf("eased value was %v, expected 12.5", v)
+	}
+	instant := Float(&v, 0, 5, 0, nil)
+	instant.Seek(0)
+	if v != 5 {
+		t.Errorf("zero length tween should jump to its end, got %v", v)
+	}
+}
+
+func TestPositionAndColor(t *testing.T) {
+	sp := render.NewEmptySprite(0, 0, 1, 1)
+	Position(sp, floatgeom.Point2{0, 0}, floatgeom.Point2{10, 20}, time.Second, nil).Seek(time.Second / 2)
+	if sp.X() != 5 || sp.Y() != 10 {
+		t.Errorf("sprite at %v,%v, expected 5,10", sp.X(), sp.Y())
+	}
+	var c color.Color
+	Color(color.RGBA{0, 0, 0, 255}, color.RGBA{255, 100, 0, 255}, time.Second, nil, func(cl color.Color) {
+		c = cl
+	}).Seek(time.Second / 2)
+	r, g, b, a := c.RGBA()
+	if r>>8 != 128 || g>>8 != 50 || b != 0 || a != 65535 {
+		t.Errorf("unexpected halfway color %v", c)
+	}
+}
+
+func TestFade(t *testing.T) {
+	sp := render.NewColorBox(2, 2, color.RGBA{255, 0, 0, 255})
+	tw := Fade(sp, 0, 255, time.Second, nil)
+	tw.Seek(time.Second / 2)
+	if a := sp.GetRGBA().RGBAAt(0, 0).A; a != 127 {
+		t.Errorf("half faded alpha was %v", a)
+	}
+	tw.Seek(time.Second)
+	if a := sp.GetRGBA().RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("fully faded alpha was %v", a)
+	}
+	tw.Seek(0)
+	if a := sp.GetRGBA().RGBAAt(0, 0).A; a != 255 {
+		t.Errorf("unfaded alpha was %v", a)
+	}
+}